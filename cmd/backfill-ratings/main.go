@@ -0,0 +1,36 @@
+// Command backfill-ratings rebuilds the rating aggregates of every business from its reviews.
+// They are kept up to date by a trigger, this is for repairing them after manual changes.
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/internal/usecase/repo"
+	"github.com/Akorm0181/yelp/pkg/logger"
+	"github.com/Akorm0181/yelp/pkg/postgres"
+)
+
+func main() {
+	// Configuration
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Fatalf("Config error: %s", err)
+	}
+
+	l := logger.New(cfg.Log.Level)
+
+	pg, err := postgres.New(cfg.PG.URL, postgres.MaxPoolSize(1))
+	if err != nil {
+		log.Fatalf("Postgres error: %s", err)
+	}
+	defer pg.Close()
+
+	corrected, err := repo.NewBusinessRepo(pg, cfg, l).RecalculateRatings(context.Background())
+	if err != nil {
+		log.Fatalf("Recalculating ratings: %s", err)
+	}
+
+	l.Info("business ratings recalculated, %d corrected", corrected)
+}
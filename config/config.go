@@ -9,13 +9,18 @@ import (
 type (
 	// Config -.
 	Config struct {
-		App   `yaml:"app"`
-		HTTP  `yaml:"http"`
-		Log   `yaml:"logger"`
-		PG    `yaml:"postgres"`
-		JWT   `yaml:"jwt"`
-		Redis `yaml:"redis"`
-		Gmail `yaml:"gmail"`
+		App             `yaml:"app"`
+		HTTP            `yaml:"http"`
+		Log             `yaml:"logger"`
+		PG              `yaml:"postgres"`
+		JWT             `yaml:"jwt"`
+		Redis           `yaml:"redis"`
+		Gmail           `yaml:"gmail"`
+		MFA             `yaml:"mfa"`
+		OIDC            `yaml:"oidc"`
+		RateLimit       `yaml:"rate_limit"`
+		AccountDeletion `yaml:"account_deletion"`
+		SoftDelete      `yaml:"soft_delete"`
 	}
 
 	// App -.
@@ -42,13 +47,30 @@ type (
 
 	// JWT -.
 	JWT struct {
-		Secret string `env-required:"true" yaml:"secret" env:"JWT_SECRET"`
+		// Secret is the legacy HS256 key, it verifies tokens without a kid and signs only
+		// while no key from Keys is active. Once Keys are configured it is only used while
+		// LegacySecret is on, which only has to outlast the access tokens it signed (15 minutes),
+		// refresh tokens are opaque and don't depend on it.
+		Secret       string   `yaml:"secret" env:"JWT_SECRET"`
+		LegacySecret bool     `yaml:"legacy_secret" env:"JWT_LEGACY_SECRET"`
+		Keys         []JWTKey `yaml:"keys"`
+	}
+
+	// JWTKey is an RS256 or EdDSA signing key. The newest key whose ActiveFrom (RFC3339) has
+	// passed signs new tokens, every listed key verifies them and is published in the JWKS.
+	JWTKey struct {
+		ID             string `yaml:"kid"`
+		Algorithm      string `yaml:"alg"`
+		PrivateKeyFile string `yaml:"private_key_file"`
+		ActiveFrom     string `yaml:"active_from"`
 	}
 
 	// Redis -.
 	Redis struct {
-		RedisHost string `env-required:"true" yaml:"host" env:"REDIS_HOST"`
-		RedisPort int    `env-required:"true" yaml:"port" env:"REDIS_PORT"`
+		RedisHost     string `env-required:"true" yaml:"host" env:"REDIS_HOST"`
+		RedisPort     int    `env-required:"true" yaml:"port" env:"REDIS_PORT"`
+		RedisUsername string `yaml:"username" env:"REDIS_USERNAME"`
+		RedisPassword string `yaml:"password" env:"REDIS_PASSWORD"`
 	}
 
 	// Gmail -.
@@ -58,6 +80,61 @@ type (
 		Host      string `env-required:"true" yaml:"host" env:"SMTP_HOST"`
 		Port      string `env-required:"true" yaml:"port" env:"SMTP_PORT"`
 	}
+
+	// MFA -.
+	MFA struct {
+		// Enforce makes two-factor authentication mandatory for MfaRequiredRoles.
+		Enforce bool   `yaml:"enforce" env:"MFA_ENFORCE" env-default:"false"`
+		Issuer  string `yaml:"issuer"  env:"MFA_ISSUER"  env-default:"Yelp"`
+	}
+
+	// OIDC -.
+	OIDC struct {
+		Providers []OIDCProvider `yaml:"providers"`
+	}
+
+	// OIDCProvider is an identity provider whose ID tokens are accepted for social login.
+	OIDCProvider struct {
+		Name      string   `yaml:"name"`
+		Issuer    string   `yaml:"issuer"`
+		JWKSURL   string   `yaml:"jwks_url"`
+		ClientIDs []string `yaml:"client_ids"`
+	}
+
+	// RateLimit -.
+	RateLimit struct {
+		Enabled bool             `yaml:"enabled" env:"RATE_LIMIT_ENABLED" env-default:"true"`
+		Groups  []RateLimitGroup `yaml:"groups"`
+		// Login lockout: after LoginMaxFailures wrong passwords the account is locked for
+		// LoginLockoutSeconds, doubling with every further lockout up to LoginMaxLockoutSeconds.
+		LoginMaxFailures       int `yaml:"login_max_failures"        env-default:"5"`
+		LoginLockoutSeconds    int `yaml:"login_lockout_seconds"     env-default:"60"`
+		LoginMaxLockoutSeconds int `yaml:"login_max_lockout_seconds" env-default:"3600"`
+	}
+
+	// RateLimitGroup limits a group of routes to Limit requests per WindowSeconds for each
+	// of the Keys (ip, email, username) separately.
+	RateLimitGroup struct {
+		Name          string   `yaml:"name"`
+		Limit         int      `yaml:"limit"`
+		WindowSeconds int      `yaml:"window_seconds"`
+		Keys          []string `yaml:"keys"`
+	}
+
+	// AccountDeletion -.
+	AccountDeletion struct {
+		// GraceDays is how long a requested deletion can still be cancelled by logging in.
+		GraceDays            int `yaml:"grace_days"             env:"ACCOUNT_DELETION_GRACE_DAYS" env-default:"30"`
+		PurgeIntervalMinutes int `yaml:"purge_interval_minutes" env-default:"60"`
+	}
+
+	// SoftDelete -.
+	SoftDelete struct {
+		// RetentionDays is how long deleted businesses, reviews and events can be restored
+		// before they are purged.
+		RetentionDays        int `yaml:"retention_days"         env:"SOFT_DELETE_RETENTION_DAYS" env-default:"30"`
+		PurgeIntervalMinutes int `yaml:"purge_interval_minutes" env-default:"60"`
+	}
 )
 
 // NewConfig returns app config.
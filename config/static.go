@@ -17,8 +17,51 @@ var (
 	ErrorConflict       = "CONFLICT"
 	ErrorBadRequest     = "BAD_REQUEST"
 	ErrorDuplicateKey   = "DUPLICATE_KEY"
+	ErrorTokenReused    = "TOKEN_REUSED"
+	ErrorInvalidOtp     = "INVALID_OTP"
+	ErrorOtpExpired     = "OTP_EXPIRED"
+	ErrorTooManyAttempt = "TOO_MANY_ATTEMPTS"
+	ErrorOtpCooldown    = "OTP_COOLDOWN"
+	ErrorOtpLocked      = "OTP_LOCKED"
+	ErrorInvalidMfaCode = "INVALID_MFA_CODE"
+	ErrorMfaRequired    = "MFA_REQUIRED"
+	ErrorAccountLocked  = "ACCOUNT_LOCKED"
+	ErrorUserBlocked    = "USER_BLOCKED"
+	ErrorNotVerified    = "EMAIL_NOT_VERIFIED"
+	ErrorChangeCooldown = "CHANGE_COOLDOWN"
 )
 
 var (
-	TokenExpireTime = 24 * time.Hour * 7 // 7 days
+	TokenExpireTime       = 24 * time.Hour * 7 // 7 days, lifetime of a session and its refresh tokens
+	AccessTokenExpireTime = 15 * time.Minute
+	OtpExpireTime         = 5 * time.Minute
+	OtpMaxAttempts        = 5
+	OtpResendCooldown     = time.Minute
+	OtpLockoutTime        = 15 * time.Minute
+	SessionTouchInterval  = 5 * time.Minute // how often last_active_at of a session is refreshed
+	SessionCacheTTL       = 5 * time.Minute
+	MfaTokenExpireTime    = 5 * time.Minute
+	MfaRecoveryCodes      = 10
+	LoginFailureWindow    = 15 * time.Minute // failed passwords older than this are forgotten
+	LoginLockoutMemory    = 24 * time.Hour   // lockouts within this period escalate the next one
+	ApiKeyTouchInterval   = 5 * time.Minute  // how often last_used_at of an api key is refreshed
+	ApiKeyPrefix          = "yk_"
+	UserStatusCacheTTL    = time.Minute         // how long AuthMiddleware trusts a cached account status
+	UsernameCooldown      = 30 * 24 * time.Hour // minimum time between two username changes
+	UsernameReservedTime  = 90 * 24 * time.Hour // a freed username can't be taken by someone else for this long
+	MaxSearchRadiusKm     = 100.0
+	MaxGalleryAttachments = 50  // photos and videos per business
+	MaxMenuItems          = 500 // items over all sections of one menu
+	MaxBusinessCategories = 3
+	MaxSearchResults      = 500 // deepest result a search page can reach, every type ranks this many rows
 )
+
+// ApiKeyScopes can be granted to api keys, config/policy.csv maps each "scope:<name>" subject
+// to the routes it opens.
+var ApiKeyScopes = []string{"business:read", "business:write", "event:write", "promotion:write", "review:read"}
+
+// DietaryTags are the tags a menu item can be marked with.
+var DietaryTags = []string{"vegetarian", "vegan", "gluten_free", "dairy_free", "nut_free", "halal", "kosher", "spicy"}
+
+// MfaRequiredRoles must use two-factor authentication when MFA.Enforce is on.
+var MfaRequiredRoles = []string{"admin", "superadmin", "business_owner"}
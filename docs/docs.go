@@ -15,9 +15,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
+        "/api-key": {
             "post": {
-                "description": "Login",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a key for integrations, send it in the X-API-Key header. The key is only shown in this response.",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,25 +30,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "api-key"
                 ],
-                "summary": "Login",
+                "summary": "Create an api key",
                 "parameters": [
                     {
-                        "description": "User",
+                        "description": "Api key",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.LoginRequest"
+                            "$ref": "#/definitions/entity.CreateApiKeyRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/entity.SuccessResponse"
+                            "$ref": "#/definitions/entity.CreateApiKeyResponse"
                         }
                     },
                     "400": {
@@ -55,14 +60,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/logout": {
-            "post": {
+        "/api-key/list": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Logout",
+                "description": "Get my api keys",
                 "consumes": [
                     "application/json"
                 ],
@@ -70,14 +75,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "api-key"
+                ],
+                "summary": "Get my api keys",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "Logout",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.SuccessResponse"
+                            "$ref": "#/definitions/entity.ApiKeyList"
                         }
                     },
                     "400": {
@@ -89,9 +110,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/register": {
-            "post": {
-                "description": "Register",
+        "/api-key/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke an api key",
                 "consumes": [
                     "application/json"
                 ],
@@ -99,25 +125,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "api-key"
                 ],
-                "summary": "Register",
+                "summary": "Revoke an api key",
                 "parameters": [
                     {
-                        "description": "User",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.RegisterRequest"
-                        }
+                        "type": "string",
+                        "description": "Api key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.User"
+                            "$ref": "#/definitions/entity.SuccessResponse"
                         }
                     },
                     "400": {
@@ -129,9 +153,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/verify-email": {
+        "/auth/2fa/disable": {
             "post": {
-                "description": "Register",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Turns off 2FA for the current user. Not allowed for roles where 2FA is mandatory.",
                 "consumes": [
                     "application/json"
                 ],
@@ -141,15 +170,15 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Register",
+                "summary": "Turn off two-factor authentication",
                 "parameters": [
                     {
-                        "description": "User",
+                        "description": "Code",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.VerifyEmail"
+                            "$ref": "#/definitions/entity.MfaCodeRequest"
                         }
                     }
                 ],
@@ -157,7 +186,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.User"
+                            "$ref": "#/definitions/entity.SuccessResponse"
                         }
                     },
                     "400": {
@@ -165,18 +194,24 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/bookmark": {
-            "put": {
+        "/auth/2fa/enable": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update a bookmark",
+                "description": "Turns on 2FA after checking a code from the authenticator app. When called with an mfa_token from Login it also completes the login.",
                 "consumes": [
                     "application/json"
                 ],
@@ -184,17 +219,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "bookmark"
+                    "auth"
                 ],
-                "summary": "Update a bookmark",
+                "summary": "Confirm two-factor enrollment",
                 "parameters": [
                     {
-                        "description": "Bookmark object",
-                        "name": "bookmark",
+                        "description": "Code",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.Bookmark"
+                            "$ref": "#/definitions/entity.MfaCodeRequest"
                         }
                     }
                 ],
@@ -202,7 +237,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Bookmark"
+                            "$ref": "#/definitions/entity.SuccessResponse"
                         }
                     },
                     "400": {
@@ -210,16 +245,24 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
                     }
                 }
-            },
+            }
+        },
+        "/auth/2fa/setup": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new bookmark",
+                "description": "Generates a TOTP secret and recovery codes. Called with a Bearer token, or with the mfa_token from Login when enrollment is mandatory. 2FA is active only after /auth/2fa/enable.",
                 "consumes": [
                     "application/json"
                 ],
@@ -227,25 +270,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "bookmark"
+                    "auth"
                 ],
-                "summary": "Create a new bookmark",
+                "summary": "Start two-factor enrollment",
                 "parameters": [
                     {
-                        "description": "Bookmark object",
-                        "name": "bookmark",
+                        "description": "Mfa token",
+                        "name": "body",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.Bookmark"
+                            "$ref": "#/definitions/entity.MfaSetupRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Bookmark"
+                            "$ref": "#/definitions/entity.MfaSetupResponse"
                         }
                     },
                     "400": {
@@ -253,18 +295,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/bookmark/list": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get a list of bookmarks",
+        "/auth/2fa/verify": {
+            "post": {
+                "description": "Completes a login that returned mfa_required, using a code from the authenticator app or a recovery code",
                 "consumes": [
                     "application/json"
                 ],
@@ -272,37 +315,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "bookmark"
+                    "auth"
                 ],
-                "summary": "Get a list of bookmarks",
+                "summary": "Second login step",
                 "parameters": [
                     {
-                        "type": "number",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "number",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query",
-                        "required": true
+                        "description": "Code",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.MfaCodeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.BookmarksList"
+                            "$ref": "#/definitions/entity.User"
                         }
                     },
                     "400": {
@@ -310,18 +341,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/bookmark/{id}": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get a bookmark by ID",
+        "/auth/forgot-password": {
+            "post": {
+                "description": "Sends a password reset code to the email if an account with it exists",
                 "consumes": [
                     "application/json"
                 ],
@@ -329,23 +361,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "bookmark"
+                    "auth"
                 ],
-                "summary": "Get a bookmark by ID",
+                "summary": "Forgot password",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Bookmark ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Email",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ForgotPasswordRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Bookmark"
+                            "$ref": "#/definitions/entity.SuccessResponse"
                         }
                     },
                     "400": {
@@ -355,14 +389,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Delete a bookmark",
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Login",
                 "consumes": [
                     "application/json"
                 ],
@@ -370,16 +401,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "bookmark"
+                    "auth"
                 ],
-                "summary": "Delete a bookmark",
+                "summary": "Login",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Bookmark ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "User",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.LoginRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -394,18 +427,24 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/business": {
-            "put": {
+        "/auth/logout": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update a business",
+                "description": "Logout",
                 "consumes": [
                     "application/json"
                 ],
@@ -413,25 +452,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "business"
-                ],
-                "summary": "Update a business",
-                "parameters": [
-                    {
-                        "description": "Business object",
-                        "name": "business",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.Business"
-                        }
-                    }
+                    "auth"
                 ],
+                "summary": "Logout",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Business"
+                            "$ref": "#/definitions/entity.SuccessResponse"
                         }
                     },
                     "400": {
@@ -441,14 +469,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/oidc": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Create a new business",
+                "description": "Login with an OpenID Connect ID token (Sign in with Google/Apple). Links the identity to the account with the same verified email or creates a new active account, then returns the same response as /auth/login.",
                 "consumes": [
                     "application/json"
                 ],
@@ -456,25 +481,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "business"
+                    "auth"
                 ],
-                "summary": "Create a new business",
+                "summary": "Social login",
                 "parameters": [
                     {
-                        "description": "Business object",
-                        "name": "business",
+                        "description": "ID token",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.Business"
+                            "$ref": "#/definitions/entity.OIDCLoginRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Business"
+                            "$ref": "#/definitions/entity.User"
                         }
                     },
                     "400": {
@@ -482,18 +507,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/business-category": {
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Update a business-category",
+        "/auth/refresh": {
+            "post": {
+                "description": "Exchanges a refresh token for a new access/refresh token pair. Presenting an already used refresh token revokes the whole session.",
                 "consumes": [
                     "application/json"
                 ],
@@ -501,17 +527,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "business-category"
+                    "auth"
                 ],
-                "summary": "Update a business-category",
+                "summary": "Refresh access token",
                 "parameters": [
                     {
-                        "description": "BusinessCategory object",
-                        "name": "business-category",
+                        "description": "Refresh token",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.BusinessCategory"
+                            "$ref": "#/definitions/entity.RefreshTokenRequest"
                         }
                     }
                 ],
@@ -519,7 +545,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.BusinessCategory"
+                            "$ref": "#/definitions/entity.TokenResponse"
                         }
                     },
                     "400": {
@@ -527,16 +553,65 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
                     }
                 }
-            },
+            }
+        },
+        "/auth/register": {
             "post": {
-                "security": [
+                "description": "Register",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Register",
+                "parameters": [
                     {
-                        "BearerAuth": []
+                        "description": "User",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.RegisterRequest"
+                        }
                     }
                 ],
-                "description": "Create a new business-category",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/resend-otp": {
+            "post": {
+                "description": "Sends a new email verification code to a user that is not verified yet",
                 "consumes": [
                     "application/json"
                 ],
@@ -544,25 +619,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "business-category"
+                    "auth"
                 ],
-                "summary": "Create a new business-category",
+                "summary": "Resend verification code",
                 "parameters": [
                     {
-                        "description": "BusinessCategory object",
-                        "name": "business_category",
+                        "description": "Email",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.BusinessCategory"
+                            "$ref": "#/definitions/entity.ResendOtpRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.BusinessCategory"
+                            "$ref": "#/definitions/entity.SuccessResponse"
                         }
                     },
                     "400": {
@@ -570,18 +645,59 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/business-category/list": {
-            "get": {
-                "security": [
+        "/auth/reset-password": {
+            "post": {
+                "description": "Sets a new password using the emailed code and logs the user out of every session",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Reset password",
+                "parameters": [
                     {
-                        "BearerAuth": []
+                        "description": "Reset password",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ResetPasswordRequest"
+                        }
                     }
                 ],
-                "description": "Get a list of categories",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify-email": {
+            "post": {
+                "description": "Register",
                 "consumes": [
                     "application/json"
                 ],
@@ -589,36 +705,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "business-category"
+                    "auth"
                 ],
-                "summary": "Get a list of categories",
+                "summary": "Register",
                 "parameters": [
                     {
-                        "type": "number",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "number",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "User",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.VerifyEmail"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.BusinessCategoryList"
+                            "$ref": "#/definitions/entity.User"
                         }
                     },
                     "400": {
@@ -630,14 +735,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/business-category/{id}": {
-            "get": {
+        "/bookmark": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a business-category by ID",
+                "description": "Update a bookmark",
                 "consumes": [
                     "application/json"
                 ],
@@ -645,30 +750,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "business-category"
+                    "bookmark"
                 ],
-                "summary": "Get a business-category by ID",
+                "summary": "Update a bookmark",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "BusinessCategory ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Category ID",
-                        "name": "category_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Bookmark object",
+                        "name": "bookmark",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.Bookmark"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.BusinessCategory"
+                            "$ref": "#/definitions/entity.Bookmark"
                         }
                     },
                     "400": {
@@ -679,13 +779,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a business-category",
+                "description": "Create a new bookmark",
                 "consumes": [
                     "application/json"
                 ],
@@ -693,23 +793,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "business-category"
+                    "bookmark"
                 ],
-                "summary": "Delete a business-category",
+                "summary": "Create a new bookmark",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "BusinessCategory ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Bookmark object",
+                        "name": "bookmark",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.Bookmark"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/entity.SuccessResponse"
+                            "$ref": "#/definitions/entity.Bookmark"
                         }
                     },
                     "400": {
@@ -717,18 +819,24 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/business/list": {
+        "/bookmark/list": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a list of businesses",
+                "description": "Get a list of bookmarks",
                 "consumes": [
                     "application/json"
                 ],
@@ -736,9 +844,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "business"
+                    "bookmark"
                 ],
-                "summary": "Get a list of businesses",
+                "summary": "Get a list of bookmarks",
                 "parameters": [
                     {
                         "type": "number",
@@ -758,14 +866,15 @@ const docTemplate = `{
                         "type": "string",
                         "description": "search",
                         "name": "search",
-                        "in": "query"
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.BusinessList"
+                            "$ref": "#/definitions/entity.BookmarksList"
                         }
                     },
                     "400": {
@@ -777,100 +886,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/business/upload/{id}": {
-            "post": {
+        "/bookmark/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Upload Multiple Files",
+                "description": "Get a bookmark by ID",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "business"
+                    "bookmark"
                 ],
-                "summary": "Upload Multiple Files",
-                "operationId": "upload_business_pic_file",
+                "summary": "Get a bookmark by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Business attachment id",
+                        "description": "Bookmark ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "file"
-                        },
-                        "collectionFormat": "csv",
-                        "description": "File to upload",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Success Request",
-                        "schema": {
-                            "$ref": "#/definitions/entity.MultipleFileUploadResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/entity.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Server error",
-                        "schema": {
-                            "$ref": "#/definitions/entity.ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/business/{id}": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get a business by ID",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "business"
-                ],
-                "summary": "Get a business by ID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Business ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/entity.Business"
-                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Bookmark"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -886,7 +934,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a business",
+                "description": "Delete a bookmark",
                 "consumes": [
                     "application/json"
                 ],
@@ -894,13 +942,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "business"
+                    "bookmark"
                 ],
-                "summary": "Delete a business",
+                "summary": "Delete a bookmark",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Business ID",
+                        "description": "Bookmark ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -922,14 +970,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/event": {
+        "/business": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an event",
+                "description": "Update a business",
                 "consumes": [
                     "application/json"
                 ],
@@ -937,17 +985,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "event"
+                    "business"
                 ],
-                "summary": "Update an event",
+                "summary": "Update a business",
                 "parameters": [
                     {
-                        "description": "Event object",
-                        "name": "event",
+                        "description": "Business object",
+                        "name": "business",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.Event"
+                            "$ref": "#/definitions/entity.Business"
                         }
                     }
                 ],
@@ -955,7 +1003,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Event"
+                            "$ref": "#/definitions/entity.Business"
                         }
                     },
                     "400": {
@@ -972,7 +1020,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create an event",
+                "description": "Create a new business",
                 "consumes": [
                     "application/json"
                 ],
@@ -980,25 +1028,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "event"
+                    "business"
                 ],
-                "summary": "Create an event",
+                "summary": "Create a new business",
                 "parameters": [
                     {
-                        "description": "Event object",
-                        "name": "event",
+                        "description": "Business object",
+                        "name": "business",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.Event"
+                            "$ref": "#/definitions/entity.Business"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/entity.Event"
+                            "$ref": "#/definitions/entity.Business"
                         }
                     },
                     "400": {
@@ -1010,14 +1058,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/event/:id/participants": {
-            "get": {
+        "/business-attribute": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get participants of an event",
+                "description": "Changes the name and options. Businesses lose values that are no longer an option, key and value_type stay as they are.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1025,37 +1073,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "event"
+                    "business-attribute"
                 ],
-                "summary": "Get participants of an event",
+                "summary": "Update a business attribute",
                 "parameters": [
                     {
-                        "type": "number",
-                        "description": "Page",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "number",
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Event ID",
-                        "name": "event_id",
-                        "in": "query",
-                        "required": true
+                        "description": "BusinessAttribute object",
+                        "name": "business_attribute",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.BusinessAttribute"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.EventParticipantList"
+                            "$ref": "#/definitions/entity.BusinessAttribute"
                         }
                     },
                     "400": {
@@ -1065,16 +1101,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/event/add-participant": {
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Add a participant to an event",
+                "description": "Create an amenity like wifi or parking, assign it to categories to use it",
                 "consumes": [
                     "application/json"
                 ],
@@ -1082,25 +1116,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "event"
+                    "business-attribute"
                 ],
-                "summary": "Add a participant to an event",
+                "summary": "Create a business attribute",
                 "parameters": [
                     {
-                        "description": "Participant object",
-                        "name": "participant",
+                        "description": "BusinessAttribute object",
+                        "name": "business_attribute",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.EventParticipant"
+                            "$ref": "#/definitions/entity.BusinessAttribute"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/entity.EventParticipant"
+                            "$ref": "#/definitions/entity.BusinessAttribute"
                         }
                     },
                     "400": {
@@ -1112,14 +1146,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/event/list": {
+        "/business-attribute/list": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a list of events",
+                "description": "Get a list of business attributes",
                 "consumes": [
                     "application/json"
                 ],
@@ -1127,28 +1161,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "event"
+                    "business-attribute"
                 ],
-                "summary": "Get a list of events",
+                "summary": "Get a list of business attributes",
                 "parameters": [
                     {
                         "type": "number",
-                        "description": "Page",
+                        "description": "page",
                         "name": "page",
                         "in": "query",
                         "required": true
                     },
                     {
                         "type": "number",
-                        "description": "Limit",
+                        "description": "limit",
                         "name": "limit",
                         "in": "query",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Business ID",
-                        "name": "business_id",
+                        "description": "search",
+                        "name": "search",
                         "in": "query"
                     }
                 ],
@@ -1156,7 +1190,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.EventList"
+                            "$ref": "#/definitions/entity.BusinessAttributeList"
                         }
                     },
                     "400": {
@@ -1168,14 +1202,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/event/remove-participant": {
-            "delete": {
+        "/business-attribute/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Remove a participant from an event",
+                "description": "Get a business attribute by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1183,25 +1217,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "event"
+                    "business-attribute"
                 ],
-                "summary": "Remove a participant from an event",
+                "summary": "Get a business attribute by ID",
                 "parameters": [
                     {
-                        "description": "Participant object",
-                        "name": "participant",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.EventParticipant"
-                        }
+                        "type": "string",
+                        "description": "BusinessAttribute ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.SuccessResponse"
+                            "$ref": "#/definitions/entity.BusinessAttribute"
                         }
                     },
                     "400": {
@@ -1211,16 +1243,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/event/{id}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get an event by ID",
+                "description": "Deletes the attribute together with its values on every business",
                 "consumes": [
                     "application/json"
                 ],
@@ -1228,13 +1258,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "event"
+                    "business-attribute"
                 ],
-                "summary": "Get an event by ID",
+                "summary": "Delete a business attribute",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Event ID",
+                        "description": "BusinessAttribute ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1244,7 +1274,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Event"
+                            "$ref": "#/definitions/entity.SuccessResponse"
                         }
                     },
                     "400": {
@@ -1254,14 +1284,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/business-category": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete an event",
+                "description": "Update a business-category, a category can't be moved below itself",
                 "consumes": [
                     "application/json"
                 ],
@@ -1269,23 +1301,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "event"
+                    "business-category"
                 ],
-                "summary": "Delete an event",
+                "summary": "Update a business-category",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Event ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "BusinessCategory object",
+                        "name": "business-category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.BusinessCategory"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.SuccessResponse"
+                            "$ref": "#/definitions/entity.BusinessCategory"
                         }
                     },
                     "400": {
@@ -1295,45 +1329,40 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/firebase": {
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Upload Multiple Files",
+                "description": "Create a new business-category, parent_id puts it below another category. The slug is made from the name when it is empty.",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Upload File"
+                    "business-category"
                 ],
-                "summary": "Upload Multiple Files",
-                "operationId": "upload_multiple_files",
+                "summary": "Create a new business-category",
                 "parameters": [
                     {
-                        "type": "array",
-                        "items": {
-                            "type": "file"
-                        },
-                        "collectionFormat": "csv",
-                        "description": "File to upload",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
+                        "description": "BusinessCategory object",
+                        "name": "business_category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.BusinessCategory"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Success Request",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/entity.MultipleFileUploadResponse"
+                            "$ref": "#/definitions/entity.BusinessCategory"
                         }
                     },
                     "400": {
@@ -1341,24 +1370,18 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
-                    },
-                    "500": {
-                        "description": "Server error",
-                        "schema": {
-                            "$ref": "#/definitions/entity.ErrorResponse"
-                        }
                     }
                 }
             }
         },
-        "/firebase/{id}": {
-            "delete": {
+        "/business-category/list": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete File",
+                "description": "Get a list of categories",
                 "consumes": [
                     "application/json"
                 ],
@@ -1366,24 +1389,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Upload File"
+                    "business-category"
                 ],
-                "summary": "Delete File",
-                "operationId": "delete_file",
+                "summary": "Get a list of categories",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ID of the file to delete",
-                        "name": "id",
+                        "type": "number",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "limit",
+                        "name": "limit",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only the children of this category, root for the top level",
+                        "name": "parent_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Success Request",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/entity.BusinessCategoryList"
                         }
                     },
                     "400": {
@@ -1391,9 +1432,40 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/business-category/tree": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Every top level category with the categories below it as children",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "business-category"
+                ],
+                "summary": "Get the category tree",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.BusinessCategory"
+                            }
+                        }
                     },
-                    "500": {
-                        "description": "Server error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
@@ -1401,14 +1473,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/follower": {
-            "post": {
+        "/business-category/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create/Delete a follower",
+                "description": "Get a business-category by ID or slug together with the categories above it",
                 "consumes": [
                     "application/json"
                 ],
@@ -1416,25 +1488,64 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "follower"
+                    "business-category"
                 ],
-                "summary": "Create/Delete a follower",
+                "summary": "Get a business-category by ID",
                 "parameters": [
                     {
-                        "description": "Follower object",
-                        "name": "follower",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "BusinessCategory ID or slug",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Follower"
+                            "$ref": "#/definitions/entity.BusinessCategory"
                         }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a business-category, categories that still have children can't be deleted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "business-category"
+                ],
+                "summary": "Delete a business-category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "BusinessCategory ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Follower"
+                            "$ref": "#/definitions/entity.SuccessResponse"
                         }
                     },
                     "400": {
@@ -1446,14 +1557,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/follower/list": {
+        "/business-category/{id}/attributes": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a list of followers",
+                "description": "The attributes businesses of the category can have",
                 "consumes": [
                     "application/json"
                 ],
@@ -1461,42 +1572,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "follower"
+                    "business-category"
                 ],
-                "summary": "Get a list of followers",
+                "summary": "Get the attributes of a category",
                 "parameters": [
-                    {
-                        "type": "number",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "number",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "following_id",
-                        "name": "following_id",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "BusinessCategory ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.UserList"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.BusinessAttribute"
+                            }
                         }
                     },
                     "400": {
@@ -1506,16 +1601,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/notification": {
+            },
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update a notification",
+                "description": "Replaces the attributes businesses of the category can have",
                 "consumes": [
                     "application/json"
                 ],
@@ -1523,17 +1616,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "notification"
+                    "business-category"
                 ],
-                "summary": "Update a notification",
+                "summary": "Set the attributes of a category",
                 "parameters": [
                     {
-                        "description": "Notification object",
-                        "name": "notification",
+                        "type": "string",
+                        "description": "BusinessCategory ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Attribute ids",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.Notification"
+                            "$ref": "#/definitions/entity.CategoryAttributesRequest"
                         }
                     }
                 ],
@@ -1541,7 +1641,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Notification"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.BusinessAttribute"
+                            }
                         }
                     },
                     "400": {
@@ -1551,14 +1654,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/business-claim": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a notification and send an email to user",
+                "description": "Asks an admin to hand the business over to you as its verified owner. Owners of a claimed business use it to get verified. Upload evidence to the claim afterwards.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1566,17 +1671,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "notification"
+                    "business-claim"
                 ],
-                "summary": "Create a new notification",
+                "summary": "Claim a business",
                 "parameters": [
                     {
-                        "description": "Notification data",
+                        "description": "Claim",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.Notification"
+                            "$ref": "#/definitions/entity.CreateBusinessClaim"
                         }
                     }
                 ],
@@ -1584,7 +1689,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/entity.Notification"
+                            "$ref": "#/definitions/entity.BusinessClaim"
                         }
                     },
                     "400": {
@@ -1596,14 +1701,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/notification/list": {
+        "/business-claim/list": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a list of notifications",
+                "description": "Admins see every claim, business owners only their own. Evidence is only included by GET /business-claim/{id}.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1611,9 +1716,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "notification"
+                    "business-claim"
                 ],
-                "summary": "Get a list of notifications",
+                "summary": "Get a list of business claims",
                 "parameters": [
                     {
                         "type": "number",
@@ -1631,8 +1736,14 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "user_id",
-                        "name": "user_id",
+                        "description": "pending, approved or rejected",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "business",
+                        "name": "business_id",
                         "in": "query"
                     }
                 ],
@@ -1640,7 +1751,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.NotificationList"
+                            "$ref": "#/definitions/entity.BusinessClaimList"
                         }
                     },
                     "400": {
@@ -1652,14 +1763,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/notification/status": {
-            "put": {
+        "/business-claim/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update a notification's status",
+                "description": "Get a business claim by ID with its evidence",
                 "consumes": [
                     "application/json"
                 ],
@@ -1667,25 +1778,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "notification"
+                    "business-claim"
                 ],
-                "summary": "Update a notification status",
+                "summary": "Get a business claim by ID",
                 "parameters": [
                     {
-                        "description": "Notification object",
-                        "name": "notification",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.Notification"
-                        }
+                        "type": "string",
+                        "description": "Claim ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Notification"
+                            "$ref": "#/definitions/entity.BusinessClaim"
                         }
                     },
                     "400": {
@@ -1697,14 +1806,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/notification/update-status": {
-            "put": {
+        "/business-claim/{id}/approve": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update status notification by ID",
+                "description": "Transfers the business to the claimant as its verified owner, rejects the other pending claims for it and notifies the previous owner",
                 "consumes": [
                     "application/json"
                 ],
@@ -1712,17 +1821,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "notification"
+                    "business-claim"
                 ],
-                "summary": "Update status a notification by ID",
+                "summary": "Approve a business claim",
                 "parameters": [
                     {
-                        "description": "Notification object",
-                        "name": "notification",
+                        "type": "string",
+                        "description": "Claim ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review",
+                        "name": "body",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.Notification"
+                            "$ref": "#/definitions/entity.ReviewBusinessClaim"
                         }
                     }
                 ],
@@ -1730,7 +1845,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Notification"
+                            "$ref": "#/definitions/entity.BusinessClaim"
                         }
                     },
                     "400": {
@@ -1742,71 +1857,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/notification/{id}": {
-            "get": {
+        "/business-claim/{id}/evidence": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a notification by ID",
+                "description": "Documents that show you own the business, e.g. a business license or a utility bill",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "notification"
+                    "business-claim"
                 ],
-                "summary": "Get a notification by ID",
+                "summary": "Upload evidence for a claim",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Notification ID",
+                        "description": "Claim ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/entity.Notification"
-                        }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/entity.ErrorResponse"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Delete a notification",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "notification"
-                ],
-                "summary": "Delete a notification",
-                "parameters": [
                     {
-                        "type": "string",
-                        "description": "Notification ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "array",
+                        "items": {
+                            "type": "file"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Files to upload",
+                        "name": "file",
+                        "in": "formData",
                         "required": true
                     }
                 ],
@@ -1814,7 +1899,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.SuccessResponse"
+                            "$ref": "#/definitions/entity.BusinessClaim"
                         }
                     },
                     "400": {
@@ -1826,14 +1911,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/promotion": {
+        "/business-claim/{id}/reject": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new promotion",
+                "description": "Reject a business claim",
                 "consumes": [
                     "application/json"
                 ],
@@ -1841,25 +1926,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "promotion"
+                    "business-claim"
                 ],
-                "summary": "Create a new promotion",
+                "summary": "Reject a business claim",
                 "parameters": [
                     {
-                        "description": "Promotion object",
-                        "name": "promotion",
+                        "type": "string",
+                        "description": "Claim ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review",
+                        "name": "body",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.Promotion"
+                            "$ref": "#/definitions/entity.ReviewBusinessClaim"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Promotion"
+                            "$ref": "#/definitions/entity.BusinessClaim"
                         }
                     },
                     "400": {
@@ -1871,14 +1962,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/promotion/list": {
+        "/business/list": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a list of promotions",
+                "description": "Get a list of businesses. With lat and lng every business gets its distance_km and the list is sorted by it, radius_km limits the search to a circle. min_lat, min_lng, max_lat and max_lng limit it to a bounding box.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1886,9 +1977,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "promotion"
+                    "business"
                 ],
-                "summary": "Get a list of promotions",
+                "summary": "Get a list of businesses",
                 "parameters": [
                     {
                         "type": "number",
@@ -1909,13 +2000,97 @@ const docTemplate = `{
                         "description": "search",
                         "name": "search",
                         "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "latitude of the point to search around",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "longitude of the point to search around",
+                        "name": "lng",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "search radius in km",
+                        "name": "radius_km",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "bounding box",
+                        "name": "min_lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "bounding box",
+                        "name": "min_lng",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "bounding box",
+                        "name": "max_lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "bounding box",
+                        "name": "max_lng",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated price ranges, $ to $$$$ or 1 to 4",
+                        "name": "price_range",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "category id or slug, businesses of the categories below it match too",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "attribute filter like attr[wifi]=free, may be repeated for different keys",
+                        "name": "attr[key]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only businesses open right now",
+                        "name": "open_now",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only businesses open at this RFC3339 time",
+                        "name": "open_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "only businesses rated at least this",
+                        "name": "min_rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "distance, rating, review_count or created_at",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.PromotionGetList"
+                            "$ref": "#/definitions/entity.BusinessList"
                         }
                     },
                     "400": {
@@ -1927,38 +2102,50 @@ const docTemplate = `{
                 }
             }
         },
-        "/promotion/{id}": {
-            "get": {
+        "/business/upload/{id}": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a promotion by ID",
+                "description": "Upload Multiple Files",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "promotion"
+                    "business"
                 ],
-                "summary": "Get a promotion by ID",
+                "summary": "Upload Multiple Files",
+                "operationId": "upload_business_pic_file",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Promotion ID",
+                        "description": "Business attachment id",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "file"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "File to upload",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Success Request",
                         "schema": {
-                            "$ref": "#/definitions/entity.Promotion"
+                            "$ref": "#/definitions/entity.MultipleFileUploadResponse"
                         }
                     },
                     "400": {
@@ -1966,16 +2153,24 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/business/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a promotion",
+                "description": "Get a business by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1983,13 +2178,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "promotion"
+                    "business"
                 ],
-                "summary": "Delete a promotion",
+                "summary": "Get a business by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Promotion ID",
+                        "description": "Business ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1999,7 +2194,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.SuccessResponse"
+                            "$ref": "#/definitions/entity.Business"
                         }
                     },
                     "400": {
@@ -2009,16 +2204,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/report": {
-            "put": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update a report",
+                "description": "Delete a business, it is hidden with its reviews, events, bookmarks and reports until an admin restores it or the retention period ends",
                 "consumes": [
                     "application/json"
                 ],
@@ -2026,25 +2219,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "report"
+                    "business"
                 ],
-                "summary": "Update a report",
+                "summary": "Delete a business",
                 "parameters": [
                     {
-                        "description": "Report object",
-                        "name": "report",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.Report"
-                        }
+                        "type": "string",
+                        "description": "Business ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Report"
+                            "$ref": "#/definitions/entity.SuccessResponse"
                         }
                     },
                     "400": {
@@ -2054,40 +2245,57 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/business/{id}/attachment": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new report",
+                "description": "Uploads images and videos to the end of the business gallery. The first image becomes the cover if the business has none.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "report"
+                    "business"
                 ],
-                "summary": "Create a new report",
+                "summary": "Add photos to a business",
                 "parameters": [
                     {
-                        "description": "Report object",
-                        "name": "report",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.Report"
-                        }
+                        "type": "string",
+                        "description": "Business ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "file"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Images or videos",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Caption for the uploaded files",
+                        "name": "caption",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Report"
+                            "$ref": "#/definitions/entity.BusinessAttachmentList"
                         }
                     },
                     "400": {
@@ -2099,14 +2307,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/report/list": {
-            "get": {
+        "/business/{id}/attachment/order": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a list of reports",
+                "description": "Sets the gallery order, ids must list every attachment of the business exactly once",
                 "consumes": [
                     "application/json"
                 ],
@@ -2114,36 +2322,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "report"
+                    "business"
                 ],
-                "summary": "Get a list of reports",
+                "summary": "Reorder business photos",
                 "parameters": [
                     {
-                        "type": "number",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query",
+                        "type": "string",
+                        "description": "Business ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "number",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "Attachment ids in the new order",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.BusinessAttachmentOrderRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.ReportList"
+                            "$ref": "#/definitions/entity.BusinessAttachmentList"
                         }
                     },
                     "400": {
@@ -2155,14 +2359,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/report/{id}": {
-            "get": {
+        "/business/{id}/attachment/{attachment_id}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a report by ID",
+                "description": "An empty caption removes it",
                 "consumes": [
                     "application/json"
                 ],
@@ -2170,23 +2374,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "report"
+                    "business"
                 ],
-                "summary": "Get a report by ID",
+                "summary": "Update the caption of a business photo",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Report ID",
+                        "description": "Business ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attachment ID",
+                        "name": "attachment_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Caption",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.BusinessAttachmentCaptionRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Report"
+                            "$ref": "#/definitions/entity.BusinessAttachment"
                         }
                     },
                     "400": {
@@ -2203,7 +2423,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a report",
+                "description": "Removes the attachment from the gallery and its file from storage",
                 "consumes": [
                     "application/json"
                 ],
@@ -2211,16 +2431,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "report"
+                    "business"
                 ],
-                "summary": "Delete a report",
+                "summary": "Delete a business photo",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Report ID",
+                        "description": "Business ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attachment ID",
+                        "name": "attachment_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -2239,14 +2466,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/review": {
+        "/business/{id}/attachment/{attachment_id}/cover": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update a review",
+                "description": "Makes an image the cover of the business, replacing the previous cover",
                 "consumes": [
                     "application/json"
                 ],
@@ -2254,68 +2481,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "review"
+                    "business"
                 ],
-                "summary": "Update a review",
+                "summary": "Set the business cover photo",
                 "parameters": [
                     {
-                        "description": "Review object",
-                        "name": "review",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.Review"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/entity.Review"
-                        }
+                        "type": "string",
+                        "description": "Business ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/entity.ErrorResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Create a new review",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "review"
-                ],
-                "summary": "Create a new review",
-                "parameters": [
                     {
-                        "description": "Review object",
-                        "name": "review",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.Review"
-                        }
+                        "type": "string",
+                        "description": "Attachment ID",
+                        "name": "attachment_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Review"
+                            "$ref": "#/definitions/entity.BusinessAttachment"
                         }
                     },
                     "400": {
@@ -2327,14 +2516,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/review/list": {
+        "/business/{id}/menu": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a list of users",
+                "description": "Get the published menu of a business",
                 "consumes": [
                     "application/json"
                 ],
@@ -2342,40 +2531,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "review"
+                    "menu"
                 ],
-                "summary": "Get a list of users",
+                "summary": "Get the menu of a business",
                 "parameters": [
-                    {
-                        "type": "number",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "number",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
-                    },
                     {
                         "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "Business ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.ReviewList"
+                            "$ref": "#/definitions/entity.Menu"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
@@ -2383,14 +2559,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/review/{id}": {
+        "/business/{id}/menu/draft": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a review by ID",
+                "description": "Get the unpublished draft of the menu",
                 "consumes": [
                     "application/json"
                 ],
@@ -2398,13 +2574,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "review"
+                    "menu"
                 ],
-                "summary": "Get a review by ID",
+                "summary": "Get the menu draft",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Review ID",
+                        "description": "Business ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2414,24 +2590,24 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Review"
+                            "$ref": "#/definitions/entity.Menu"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
                     }
                 }
             },
-            "delete": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a review",
+                "description": "Replaces the content of the draft, a new version is started when there is no draft. Customers see it once it is published.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2439,23 +2615,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "review"
+                    "menu"
                 ],
-                "summary": "Delete a review",
+                "summary": "Save the menu draft",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Review ID",
+                        "description": "Business ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Sections and items in menu order",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.MenuDraftRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.SuccessResponse"
+                            "$ref": "#/definitions/entity.Menu"
                         }
                     },
                     "400": {
@@ -2467,14 +2652,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/session": {
-            "put": {
+        "/business/{id}/menu/draft/publish": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update a session",
+                "description": "The draft becomes the menu customers see, the menu published before is archived",
                 "consumes": [
                     "application/json"
                 ],
@@ -2482,29 +2667,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "session"
+                    "menu"
                 ],
-                "summary": "Update a session",
+                "summary": "Publish the menu draft",
                 "parameters": [
                     {
-                        "description": "Session object",
-                        "name": "session",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.Session"
-                        }
+                        "type": "string",
+                        "description": "Business ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Session"
+                            "$ref": "#/definitions/entity.Menu"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
@@ -2512,14 +2695,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/session/list": {
+        "/business/{id}/menu/versions": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a list of users",
+                "description": "Lists the draft, published and archived menus without their content, newest first",
                 "consumes": [
                     "application/json"
                 ],
@@ -2527,10 +2710,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "session"
+                    "menu"
                 ],
-                "summary": "Get a list of users",
+                "summary": "Get the menu versions of a business",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Business ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "number",
                         "description": "page",
@@ -2544,19 +2734,13 @@ const docTemplate = `{
                         "name": "limit",
                         "in": "query",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "user_id",
-                        "name": "user_id",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.SessionList"
+                            "$ref": "#/definitions/entity.MenuList"
                         }
                     },
                     "400": {
@@ -2568,14 +2752,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/session/{id}": {
+        "/business/{id}/menu/versions/{version}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a session by ID",
+                "description": "Get any version of the menu with its content",
                 "consumes": [
                     "application/json"
                 ],
@@ -2583,40 +2767,49 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "session"
+                    "menu"
                 ],
-                "summary": "Get a session by ID",
+                "summary": "Get a menu version",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Session ID",
+                        "description": "Business ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Session"
+                            "$ref": "#/definitions/entity.Menu"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/business/{id}/restore": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a session",
+                "description": "Brings back a deleted business with everything that belongs to it, as long as it is not purged yet",
                 "consumes": [
                     "application/json"
                 ],
@@ -2624,13 +2817,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "session"
+                    "business"
                 ],
-                "summary": "Delete a session",
+                "summary": "Restore a deleted business",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Session ID",
+                        "description": "Business ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2640,11 +2833,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.SuccessResponse"
+                            "$ref": "#/definitions/entity.Business"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
@@ -2652,14 +2845,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tag": {
+        "/event": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update a tag",
+                "description": "Update an event",
                 "consumes": [
                     "application/json"
                 ],
@@ -2667,17 +2860,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tag"
+                    "event"
                 ],
-                "summary": "Update a tag",
+                "summary": "Update an event",
                 "parameters": [
                     {
-                        "description": "Tag object",
-                        "name": "tag",
+                        "description": "Event object",
+                        "name": "event",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.Tag"
+                            "$ref": "#/definitions/entity.Event"
                         }
                     }
                 ],
@@ -2685,7 +2878,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Tag"
+                            "$ref": "#/definitions/entity.Event"
                         }
                     },
                     "400": {
@@ -2702,7 +2895,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a tag",
+                "description": "Create an event",
                 "consumes": [
                     "application/json"
                 ],
@@ -2710,17 +2903,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tag"
+                    "event"
                 ],
-                "summary": "Create a tag",
+                "summary": "Create an event",
                 "parameters": [
                     {
-                        "description": "Tag object",
-                        "name": "tag",
+                        "description": "Event object",
+                        "name": "event",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.Tag"
+                            "$ref": "#/definitions/entity.Event"
                         }
                     }
                 ],
@@ -2728,7 +2921,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Tag"
+                            "$ref": "#/definitions/entity.Event"
                         }
                     },
                     "400": {
@@ -2736,18 +2929,24 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/tag/list": {
+        "/event/:id/participants": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a list of users",
+                "description": "Get participants of an event",
                 "consumes": [
                     "application/json"
                 ],
@@ -2755,36 +2954,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tag"
+                    "event"
                 ],
-                "summary": "Get a list of users",
+                "summary": "Get participants of an event",
                 "parameters": [
                     {
                         "type": "number",
-                        "description": "page",
+                        "description": "Page",
                         "name": "page",
                         "in": "query",
                         "required": true
                     },
                     {
                         "type": "number",
-                        "description": "limit",
+                        "description": "Limit",
                         "name": "limit",
                         "in": "query",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "Event ID",
+                        "name": "event_id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.TagList"
+                            "$ref": "#/definitions/entity.EventParticipantList"
                         }
                     },
                     "400": {
@@ -2796,14 +2996,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tag/{id}": {
-            "get": {
+        "/event/add-participant": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a tag by ID",
+                "description": "Add a participant to an event",
                 "consumes": [
                     "application/json"
                 ],
@@ -2811,23 +3011,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tag"
+                    "event"
                 ],
-                "summary": "Get a tag by ID",
+                "summary": "Add a participant to an event",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Tag ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Participant object",
+                        "name": "participant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.EventParticipant"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Tag"
+                            "$ref": "#/definitions/entity.EventParticipant"
                         }
                     },
                     "400": {
@@ -2837,14 +3039,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/event/list": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a tag",
+                "description": "Get a list of events",
                 "consumes": [
                     "application/json"
                 ],
@@ -2852,23 +3056,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tag"
+                    "event"
                 ],
-                "summary": "Delete a tag",
+                "summary": "Get a list of events",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Tag ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "number",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Business ID",
+                        "name": "business_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.SuccessResponse"
+                            "$ref": "#/definitions/entity.EventList"
                         }
                     },
                     "400": {
@@ -2880,14 +3097,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user": {
-            "put": {
+        "/event/remove-participant": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update a user",
+                "description": "Remove a participant from an event",
                 "consumes": [
                     "application/json"
                 ],
@@ -2895,17 +3112,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "event"
                 ],
-                "summary": "Update a user",
+                "summary": "Remove a participant from an event",
                 "parameters": [
                     {
-                        "description": "User object",
-                        "name": "user",
+                        "description": "Participant object",
+                        "name": "participant",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.User"
+                            "$ref": "#/definitions/entity.EventParticipant"
                         }
                     }
                 ],
@@ -2913,7 +3130,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.User"
+                            "$ref": "#/definitions/entity.SuccessResponse"
                         }
                     },
                     "400": {
@@ -2923,14 +3140,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/event/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new user",
+                "description": "Get an event by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -2938,25 +3157,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "event"
                 ],
-                "summary": "Create a new user",
+                "summary": "Get an event by ID",
                 "parameters": [
                     {
-                        "description": "User object",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.User"
-                        }
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.User"
+                            "$ref": "#/definitions/entity.Event"
                         }
                     },
                     "400": {
@@ -2966,16 +3183,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/user/list": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a list of users",
+                "description": "Delete an event, an admin can restore it until the retention period ends",
                 "consumes": [
                     "application/json"
                 ],
@@ -2983,40 +3198,70 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "event"
                 ],
-                "summary": "Get a list of users",
+                "summary": "Delete an event",
                 "parameters": [
                     {
-                        "type": "number",
-                        "description": "page",
-                        "name": "page",
-                        "in": "query",
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SuccessResponse"
+                        }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/event/{id}/restore": {
+            "post": {
+                "security": [
                     {
-                        "type": "number",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
-                    },
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Brings back a deleted event that is not purged yet",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event"
+                ],
+                "summary": "Restore a deleted event",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "search",
-                        "name": "search",
-                        "in": "query"
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.UserList"
+                            "$ref": "#/definitions/entity.SuccessResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
@@ -3024,7 +3269,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/upload": {
+        "/firebase": {
             "post": {
                 "security": [
                     {
@@ -3039,10 +3284,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "Upload File"
                 ],
                 "summary": "Upload Multiple Files",
-                "operationId": "upload_profile_pic_file",
+                "operationId": "upload_multiple_files",
                 "parameters": [
                     {
                         "type": "array",
@@ -3078,14 +3323,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/{id}": {
-            "get": {
+        "/firebase/{id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a user by ID",
+                "description": "Delete File",
                 "consumes": [
                     "application/json"
                 ],
@@ -3093,23 +3338,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "Upload File"
                 ],
-                "summary": "Get a user by ID",
+                "summary": "Delete File",
+                "operationId": "delete_file",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "ID of the file to delete",
                         "name": "id",
-                        "in": "path",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "Success Request",
                         "schema": {
-                            "$ref": "#/definitions/entity.User"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -3117,16 +3363,24 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/follower": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a user",
+                "description": "Create/Delete a follower",
                 "consumes": [
                     "application/json"
                 ],
@@ -3134,37 +3388,3119 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "follower"
                 ],
-                "summary": "Delete a user",
+                "summary": "Create/Delete a follower",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Follower object",
+                        "name": "follower",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.Follower"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Follower"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/follower/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a list of followers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "follower"
+                ],
+                "summary": "Get a list of followers",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "following_id",
+                        "name": "following_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.UserList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notification": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a notification",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification"
+                ],
+                "summary": "Update a notification",
+                "parameters": [
+                    {
+                        "description": "Notification object",
+                        "name": "notification",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.Notification"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Notification"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a notification and send an email to user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification"
+                ],
+                "summary": "Create a new notification",
+                "parameters": [
+                    {
+                        "description": "Notification data",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.Notification"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Notification"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notification/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a list of notifications",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification"
+                ],
+                "summary": "Get a list of notifications",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "user_id",
+                        "name": "user_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.NotificationList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notification/status": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a notification's status",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification"
+                ],
+                "summary": "Update a notification status",
+                "parameters": [
+                    {
+                        "description": "Notification object",
+                        "name": "notification",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.Notification"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Notification"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notification/update-status": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update status notification by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification"
+                ],
+                "summary": "Update status a notification by ID",
+                "parameters": [
+                    {
+                        "description": "Notification object",
+                        "name": "notification",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.Notification"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Notification"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notification/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a notification by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification"
+                ],
+                "summary": "Get a notification by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Notification"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a notification",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification"
+                ],
+                "summary": "Delete a notification",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/owner-request": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The request is reviewed by an admin, after approval the account gets the business_owner role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "owner-request"
+                ],
+                "summary": "Apply to become a business owner",
+                "parameters": [
+                    {
+                        "description": "Owner request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.CreateOwnerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.OwnerRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/owner-request/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admins see every request, other users only their own",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "owner-request"
+                ],
+                "summary": "Get a list of owner requests",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, approved or rejected",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.OwnerRequestList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/owner-request/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get an owner request by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "owner-request"
+                ],
+                "summary": "Get an owner request by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Owner request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.OwnerRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/owner-request/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gives the applicant the business_owner role and signs out their sessions, they log in again on the business_owner platform",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "owner-request"
+                ],
+                "summary": "Approve an owner request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Owner request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ReviewOwnerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.OwnerRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/owner-request/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reject an owner request",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "owner-request"
+                ],
+                "summary": "Reject an owner request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Owner request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ReviewOwnerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.OwnerRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/promotion": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new promotion",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotion"
+                ],
+                "summary": "Create a new promotion",
+                "parameters": [
+                    {
+                        "description": "Promotion object",
+                        "name": "promotion",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.Promotion"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Promotion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/promotion/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a list of promotions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotion"
+                ],
+                "summary": "Get a list of promotions",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.PromotionGetList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/promotion/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a promotion by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotion"
+                ],
+                "summary": "Get a promotion by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Promotion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a promotion",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotion"
+                ],
+                "summary": "Delete a promotion",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/report": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a report",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Update a report",
+                "parameters": [
+                    {
+                        "description": "Report object",
+                        "name": "report",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.Report"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Report"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new report",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Create a new report",
+                "parameters": [
+                    {
+                        "description": "Report object",
+                        "name": "report",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.Report"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Report"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a list of reports",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Get a list of reports",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ReportList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/report/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a report by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Get a report by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Report ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Report"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a report",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Delete a report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Report ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/review": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a review",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "summary": "Update a review",
+                "parameters": [
+                    {
+                        "description": "Review object",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.Review"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Review"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new review",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "summary": "Create a new review",
+                "parameters": [
+                    {
+                        "description": "Review object",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.Review"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Review"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/review/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a list of users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "summary": "Get a list of users",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ReviewList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/review/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a review by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "summary": "Get a review by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Review"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a review, an admin can restore it until the retention period ends",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "summary": "Delete a review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/review/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Brings back a deleted review that is not purged yet, it counts in the business rating again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "review"
+                ],
+                "summary": "Restore a deleted review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Full-text search ranked by relevance. The last word also matches as a prefix, so it can back a typeahead. Snippets are HTML escaped with the matched words wrapped in \u003cmark\u003e\u003c/mark\u003e. Results can be paged up to the 500th.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Search businesses, reviews and events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "search text",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated: business,review,event, all by default",
+                        "name": "types",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/session": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a session",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "session"
+                ],
+                "summary": "Update a session",
+                "parameters": [
+                    {
+                        "description": "Session object",
+                        "name": "session",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.Session"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Session"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/session/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a list of users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "session"
+                ],
+                "summary": "Get a list of users",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "user_id",
+                        "name": "user_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SessionList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/session/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists active sessions (devices) of the current user, the session of this request is marked with is_current",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "session"
+                ],
+                "summary": "List my sessions",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SessionList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/session/me/others": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes every session of the current user except the one making this request",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "session"
+                ],
+                "summary": "Log out everywhere else",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.RowsEffected"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/session/me/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Logs out one session (device) of the current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "session"
+                ],
+                "summary": "Revoke one of my sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/session/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a session by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "session"
+                ],
+                "summary": "Get a session by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Session"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a session",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "session"
+                ],
+                "summary": "Delete a session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tag": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a tag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag"
+                ],
+                "summary": "Update a tag",
+                "parameters": [
+                    {
+                        "description": "Tag object",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.Tag"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a tag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag"
+                ],
+                "summary": "Create a tag",
+                "parameters": [
+                    {
+                        "description": "Tag object",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.Tag"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tag/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a list of users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag"
+                ],
+                "summary": "Get a list of users",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TagList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tag/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a tag by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag"
+                ],
+                "summary": "Get a tag by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a tag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag"
+                ],
+                "summary": "Delete a tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Update a user",
+                "parameters": [
+                    {
+                        "description": "User object",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Create a new user",
+                "parameters": [
+                    {
+                        "description": "User object",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/list": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a list of users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get a list of users",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.UserList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/change-history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get my email and username changes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get my email and username changes",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "email or username",
+                        "name": "kind",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.UserChangeList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/delete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Signs out every session, suspends the api keys and deletes the account after the grace period. Logging in again before that cancels the deletion and lifts the suspension.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Request deletion of my account",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.AccountDeletionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/email": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sends a code to the new address, the change is applied once it is confirmed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Change my email address",
+                "parameters": [
+                    {
+                        "description": "New email",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.EmailChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/email/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Applies the pending email change and notifies the previous address",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Confirm my new email address",
+                "parameters": [
+                    {
+                        "description": "Otp",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.EmailChangeConfirmRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Profile, reviews with attachments, bookmarks, follows, event participation and notifications as a JSON file",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Download my data",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.AccountExport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/me/username": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Usernames can be changed once per cooldown period. The previous username stays reserved for you for a while before anyone else can take it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Change my username",
+                "parameters": [
+                    {
+                        "description": "New username",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UsernameChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/upload": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Upload Multiple Files",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Upload Multiple Files",
+                "operationId": "upload_profile_pic_file",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "file"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "File to upload",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.MultipleFileUploadResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a user by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get a user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Delete a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}/block": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Blocks the user until blocked_until, or for good when it is empty, and signs out all of their sessions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Block a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason and optional expiry",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UserStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.UserStatusAudit"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}/status-history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the status changes of a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get the status changes of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.UserStatusAuditList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}/unblock": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Unblock a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Unblock a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UserStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.UserStatusAudit"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "entity.AccountDeletionResponse": {
+            "type": "object",
+            "properties": {
+                "delete_after": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.AccountExport": {
+            "type": "object",
+            "properties": {
+                "bookmarks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Bookmark"
+                    }
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.EventParticipant"
+                    }
+                },
+                "exported_at": {
+                    "type": "string"
+                },
+                "followers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.AccountExportFollow"
+                    }
+                },
+                "following": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.AccountExportFollow"
+                    }
+                },
+                "notifications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Notification"
+                    }
+                },
+                "profile": {
+                    "$ref": "#/definitions/entity.User"
+                },
+                "reviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Review"
+                    }
+                }
+            }
+        },
+        "entity.AccountExportFollow": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "user_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ApiKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "suspended_at": {
+                    "description": "while the account of the owner is scheduled for deletion",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ApiKeyList": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ApiKey"
+                    }
+                }
+            }
+        },
+        "entity.AttributeFacet": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "values": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.FacetCount"
+                    }
+                }
+            }
+        },
+        "entity.Bookmark": {
+            "type": "object",
+            "properties": {
+                "business_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.BookmarksList": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Bookmark"
+                    }
+                }
+            }
+        },
+        "entity.Business": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.BusinessAttachment"
+                    }
+                },
+                "attributes": {
+                    "description": "attribute key to value",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.BusinessCategory"
+                    }
+                },
+                "category_ids": {
+                    "description": "the first one is the primary category",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "claim_state": {
+                    "description": "unclaimed, claimed, verified",
+                    "type": "string"
+                },
+                "contact_info": {
+                    "$ref": "#/definitions/entity.ContactInfo"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "distance_km": {
+                    "description": "only set by a search around a point",
+                    "type": "number"
+                },
+                "hours_of_operation": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.OpeningHours"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "open_now": {
+                    "type": "boolean"
+                },
+                "owner_id": {
+                    "type": "string"
+                },
+                "price_range": {
+                    "description": "$ to $$$$, empty when unknown",
+                    "type": "string"
+                },
+                "rating_avg": {
+                    "type": "number"
+                },
+                "rating_histogram": {
+                    "description": "reviews per star, index 0 is 1 star",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
                     }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/entity.SuccessResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/entity.ErrorResponse"
-                        }
+                },
+                "review_count": {
+                    "type": "integer"
+                },
+                "special_hours": {
+                    "description": "upcoming dates only",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.SpecialHours"
                     }
+                },
+                "time_zone": {
+                    "description": "IANA name, UTC by default",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "verified_at": {
+                    "type": "string"
                 }
             }
-        }
-    },
-    "definitions": {
-        "entity.Bookmark": {
+        },
+        "entity.BusinessAttachment": {
+            "type": "object",
+            "properties": {
+                "caption": {
+                    "type": "string"
+                },
+                "content_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "filepath": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_cover": {
+                    "type": "boolean"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "uploaded_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.BusinessAttachmentCaptionRequest": {
+            "type": "object",
+            "properties": {
+                "caption": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.BusinessAttachmentList": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.BusinessAttachment"
+                    }
+                }
+            }
+        },
+        "entity.BusinessAttachmentOrderRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "description": "every attachment of the business, in the new order",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "entity.BusinessAttribute": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "value_type": {
+                    "description": "bool or enum",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.BusinessAttributeList": {
+            "type": "object",
+            "properties": {
+                "business_attributes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.BusinessAttribute"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.BusinessCategory": {
+            "type": "object",
+            "properties": {
+                "ancestors": {
+                    "description": "the path from the root, only filled for a single category",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.BusinessCategory"
+                    }
+                },
+                "children": {
+                    "description": "only filled in the tree",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.BusinessCategory"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.BusinessCategoryList": {
+            "type": "object",
+            "properties": {
+                "businesses_categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.BusinessCategory"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.BusinessClaim": {
             "type": "object",
             "properties": {
                 "business_id": {
@@ -3173,9 +6509,34 @@ const docTemplate = `{
                 "created_at": {
                     "type": "string"
                 },
+                "evidence": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.BusinessClaimEvidence"
+                    }
+                },
                 "id": {
                     "type": "string"
                 },
+                "note": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "review_note": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "pending, approved, rejected",
+                    "type": "string"
+                },
                 "updated_at": {
                     "type": "string"
                 },
@@ -3184,7 +6545,21 @@ const docTemplate = `{
                 }
             }
         },
-        "entity.BookmarksList": {
+        "entity.BusinessClaimEvidence": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "filepath": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.BusinessClaimList": {
             "type": "object",
             "properties": {
                 "count": {
@@ -3193,133 +6568,160 @@ const docTemplate = `{
                 "items": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/entity.Bookmark"
+                        "$ref": "#/definitions/entity.BusinessClaim"
+                    }
+                }
+            }
+        },
+        "entity.BusinessList": {
+            "type": "object",
+            "properties": {
+                "businesses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Business"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "facets": {
+                    "description": "attribute values over the whole result, not just the page",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.AttributeFacet"
+                    }
+                }
+            }
+        },
+        "entity.CategoryAttributesRequest": {
+            "type": "object",
+            "properties": {
+                "attribute_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
                 }
             }
         },
-        "entity.Business": {
+        "entity.ContactInfo": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "website": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.CreateApiKeyRequest": {
             "type": "object",
             "properties": {
-                "address": {
+                "expires_at": {
+                    "description": "optional, RFC3339",
                     "type": "string"
                 },
-                "attachments": {
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/entity.BusinessAttachment"
+                        "type": "string"
                     }
-                },
-                "category_id": {
-                    "type": "string"
-                },
-                "contact_info": {
-                    "$ref": "#/definitions/entity.ContactInfo"
-                },
+                }
+            }
+        },
+        "entity.CreateApiKeyResponse": {
+            "type": "object",
+            "properties": {
                 "created_at": {
                     "type": "string"
                 },
-                "description": {
+                "expires_at": {
                     "type": "string"
                 },
-                "hours_of_operation": {
-                    "$ref": "#/definitions/entity.HoursOfOperation"
-                },
                 "id": {
                     "type": "string"
                 },
-                "latitude": {
-                    "type": "number"
+                "key": {
+                    "type": "string"
                 },
-                "longitude": {
-                    "type": "number"
+                "last_used_at": {
+                    "type": "string"
                 },
                 "name": {
                     "type": "string"
                 },
-                "owner_id": {
+                "prefix": {
                     "type": "string"
                 },
-                "updated_at": {
-                    "type": "string"
-                }
-            }
-        },
-        "entity.BusinessAttachment": {
-            "type": "object",
-            "properties": {
-                "content_type": {
+                "revoked_at": {
                     "type": "string"
                 },
-                "created_at": {
-                    "type": "string"
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "filepath": {
+                "suspended_at": {
+                    "description": "while the account of the owner is scheduled for deletion",
                     "type": "string"
                 },
-                "id": {
+                "updated_at": {
                     "type": "string"
                 },
-                "updated_at": {
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "entity.BusinessCategory": {
+        "entity.CreateBusinessClaim": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
+                "business_id": {
                     "type": "string"
                 },
-                "name": {
+                "note": {
                     "type": "string"
                 },
-                "updated_at": {
+                "phone": {
                     "type": "string"
                 }
             }
         },
-        "entity.BusinessCategoryList": {
+        "entity.CreateOwnerRequest": {
             "type": "object",
             "properties": {
-                "businesses_categories": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/entity.BusinessCategory"
-                    }
+                "business_name": {
+                    "type": "string"
                 },
-                "count": {
-                    "type": "integer"
+                "note": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
                 }
             }
         },
-        "entity.BusinessList": {
+        "entity.EmailChangeConfirmRequest": {
             "type": "object",
             "properties": {
-                "businesses": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/entity.Business"
-                    }
-                },
-                "count": {
-                    "type": "integer"
+                "otp": {
+                    "type": "string"
                 }
             }
         },
-        "entity.ContactInfo": {
+        "entity.EmailChangeRequest": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "phone": {
-                    "type": "string"
-                },
-                "website": {
+                "new_email": {
                     "type": "string"
                 }
             }
@@ -3438,6 +6840,17 @@ const docTemplate = `{
                 }
             }
         },
+        "entity.FacetCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
         "entity.Follower": {
             "type": "object",
             "properties": {
@@ -3452,82 +6865,308 @@ const docTemplate = `{
                 }
             }
         },
-        "entity.HoursOfOperation": {
+        "entity.ForgotPasswordRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "platform": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Menu": {
+            "type": "object",
+            "properties": {
+                "business_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "published_at": {
+                    "type": "string"
+                },
+                "published_by": {
+                    "type": "string"
+                },
+                "sections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.MenuSection"
+                    }
+                },
+                "status": {
+                    "description": "draft, published, archived",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.MenuDraftRequest": {
+            "type": "object",
+            "properties": {
+                "sections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.MenuSection"
+                    }
+                }
+            }
+        },
+        "entity.MenuItem": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "description": "ISO 4217 code, e.g. USD",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "dietary_tags": {
+                    "description": "one of config.DietaryTags",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "photo_url": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "sold_out": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "entity.MenuList": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "menus": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Menu"
+                    }
+                }
+            }
+        },
+        "entity.MenuSection": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.MenuItem"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.MfaCodeRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "mfa_token": {
+                    "type": "string"
+                },
+                "recovery_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.MfaSetupRequest": {
+            "type": "object",
+            "properties": {
+                "mfa_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.MfaSetupResponse": {
+            "type": "object",
+            "properties": {
+                "provisioning_uri": {
+                    "type": "string"
+                },
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.MultipleFileUploadResponse": {
+            "type": "object",
+            "properties": {
+                "url": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Url"
+                    }
+                }
+            }
+        },
+        "entity.Notification": {
             "type": "object",
             "properties": {
-                "friday": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
                     "type": "string"
                 },
-                "monday": {
+                "id": {
                     "type": "string"
                 },
-                "saturday": {
+                "message": {
                     "type": "string"
                 },
-                "sunday": {
+                "owner_id": {
                     "type": "string"
                 },
-                "thursday": {
+                "ownerrole": {
                     "type": "string"
                 },
-                "tuesday": {
+                "status": {
                     "type": "string"
                 },
-                "wednesday": {
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "entity.LoginRequest": {
+        "entity.NotificationList": {
             "type": "object",
             "properties": {
-                "email": {
+                "count": {
+                    "type": "integer"
+                },
+                "notifications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Notification"
+                    }
+                }
+            }
+        },
+        "entity.OIDCLoginRequest": {
+            "type": "object",
+            "properties": {
+                "id_token": {
                     "type": "string"
                 },
-                "password": {
+                "nonce": {
                     "type": "string"
                 },
                 "platform": {
                     "type": "string"
                 },
-                "username": {
+                "provider": {
                     "type": "string"
                 }
             }
         },
-        "entity.MultipleFileUploadResponse": {
+        "entity.OpeningHours": {
             "type": "object",
             "properties": {
-                "url": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/entity.Url"
-                    }
+                "closes_at": {
+                    "description": "HH:MM",
+                    "type": "string"
+                },
+                "opens_at": {
+                    "description": "HH:MM in the business time zone",
+                    "type": "string"
+                },
+                "weekday": {
+                    "description": "0 is Sunday",
+                    "type": "integer"
                 }
             }
         },
-        "entity.Notification": {
+        "entity.OwnerRequest": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "business_name": {
                     "type": "string"
                 },
-                "email": {
+                "created_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "message": {
+                "note": {
                     "type": "string"
                 },
-                "owner_id": {
+                "phone": {
                     "type": "string"
                 },
-                "ownerrole": {
+                "review_note": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
                     "type": "string"
                 },
                 "status": {
+                    "description": "pending, approved, rejected",
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 },
                 "user_id": {
@@ -3535,16 +7174,16 @@ const docTemplate = `{
                 }
             }
         },
-        "entity.NotificationList": {
+        "entity.OwnerRequestList": {
             "type": "object",
             "properties": {
                 "count": {
                     "type": "integer"
                 },
-                "notifications": {
+                "items": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/entity.Notification"
+                        "$ref": "#/definitions/entity.OwnerRequest"
                     }
                 }
             }
@@ -3592,6 +7231,14 @@ const docTemplate = `{
                 }
             }
         },
+        "entity.RefreshTokenRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
         "entity.RegisterRequest": {
             "type": "object",
             "properties": {
@@ -3649,6 +7296,28 @@ const docTemplate = `{
                 }
             }
         },
+        "entity.ResendOtpRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ResetPasswordRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string"
+                },
+                "otp": {
+                    "type": "string"
+                }
+            }
+        },
         "entity.Review": {
             "type": "object",
             "properties": {
@@ -3701,6 +7370,14 @@ const docTemplate = `{
                 }
             }
         },
+        "entity.ReviewBusinessClaim": {
+            "type": "object",
+            "properties": {
+                "review_note": {
+                    "type": "string"
+                }
+            }
+        },
         "entity.ReviewList": {
             "type": "object",
             "properties": {
@@ -3715,6 +7392,66 @@ const docTemplate = `{
                 }
             }
         },
+        "entity.ReviewOwnerRequest": {
+            "type": "object",
+            "properties": {
+                "review_note": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.RowsEffected": {
+            "type": "object",
+            "properties": {
+                "rows_effected": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.SearchResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "counts": {
+                    "description": "matches per type",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.SearchResult"
+                    }
+                }
+            }
+        },
+        "entity.SearchResult": {
+            "type": "object",
+            "properties": {
+                "business_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "number"
+                },
+                "snippet": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
         "entity.Session": {
             "type": "object",
             "properties": {
@@ -3733,6 +7470,9 @@ const docTemplate = `{
                 "is_active": {
                     "type": "boolean"
                 },
+                "is_current": {
+                    "type": "boolean"
+                },
                 "last_active_at": {
                     "type": "string"
                 },
@@ -3764,6 +7504,27 @@ const docTemplate = `{
                 }
             }
         },
+        "entity.SpecialHours": {
+            "type": "object",
+            "properties": {
+                "closed": {
+                    "type": "boolean"
+                },
+                "closes_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "opens_at": {
+                    "type": "string"
+                }
+            }
+        },
         "entity.SuccessResponse": {
             "type": "object",
             "properties": {
@@ -3806,6 +7567,20 @@ const docTemplate = `{
                 }
             }
         },
+        "entity.TokenResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "session": {
+                    "$ref": "#/definitions/entity.Session"
+                }
+            }
+        },
         "entity.Url": {
             "type": "object",
             "properties": {
@@ -3826,6 +7601,12 @@ const docTemplate = `{
                 "bio": {
                     "type": "string"
                 },
+                "block_reason": {
+                    "type": "string"
+                },
+                "blocked_until": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
@@ -3847,6 +7628,9 @@ const docTemplate = `{
                 "profile_picture": {
                     "type": "string"
                 },
+                "refresh_token": {
+                    "type": "string"
+                },
                 "status": {
                     "type": "string"
                 },
@@ -3864,6 +7648,47 @@ const docTemplate = `{
                 }
             }
         },
+        "entity.UserChange": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "email, username",
+                    "type": "string"
+                },
+                "new_value": {
+                    "type": "string"
+                },
+                "old_value": {
+                    "type": "string"
+                },
+                "reserved_until": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.UserChangeList": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.UserChange"
+                    }
+                }
+            }
+        },
         "entity.UserList": {
             "type": "object",
             "properties": {
@@ -3878,6 +7703,69 @@ const docTemplate = `{
                 }
             }
         },
+        "entity.UserStatusAudit": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "type": "string"
+                },
+                "blocked_until": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "new_status": {
+                    "type": "string"
+                },
+                "old_status": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.UserStatusAuditList": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.UserStatusAudit"
+                    }
+                }
+            }
+        },
+        "entity.UserStatusRequest": {
+            "type": "object",
+            "properties": {
+                "blocked_until": {
+                    "description": "optional, RFC3339, blocks for good when empty",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.UsernameChangeRequest": {
+            "type": "object",
+            "properties": {
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
         "entity.VerifyEmail": {
             "type": "object",
             "properties": {
@@ -2,10 +2,12 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -15,7 +17,9 @@ import (
 	"github.com/Akorm0181/yelp/pkg/httpserver"
 	"github.com/Akorm0181/yelp/pkg/logger"
 	"github.com/Akorm0181/yelp/pkg/postgres"
+	"github.com/Akorm0181/yelp/pkg/ratelimit"
 	rediscache "github.com/golanguzb70/redis-cache"
+	goredis "github.com/redis/go-redis/v9"
 )
 
 // Run creates objects via constructors.
@@ -29,21 +33,52 @@ func Run(cfg *config.Config) {
 	}
 	defer pg.Close()
 
-	// Use case
-	useCase := usecase.New(pg, cfg, l)
-
 	// redis
 	redis, err := rediscache.New(&rediscache.Config{
-		RedisHost: cfg.Redis.RedisHost,
-		RedisPort: cfg.Redis.RedisPort,
+		RedisHost:     cfg.Redis.RedisHost,
+		RedisPort:     cfg.Redis.RedisPort,
+		RedisUsername: cfg.Redis.RedisUsername,
+		RedisPassword: cfg.Redis.RedisPassword,
 	})
 	if err != nil {
 		l.Fatal(fmt.Errorf("app - Run - rediscache.New: %w", err))
 	}
 
+	// rate limiter, requests are let through on redis errors so it has to work from the start
+	limiterClient := goredis.NewClient(&goredis.Options{
+		Addr:     fmt.Sprintf("%s:%d", cfg.Redis.RedisHost, cfg.Redis.RedisPort),
+		Username: cfg.Redis.RedisUsername,
+		Password: cfg.Redis.RedisPassword,
+	})
+	defer limiterClient.Close()
+
+	err = limiterClient.Ping(context.Background()).Err()
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Run - rate limiter redis ping: %w", err))
+	}
+
+	limiter := ratelimit.New(limiterClient)
+
+	// token signing keys
+	keys, err := newKeySet(cfg.JWT)
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Run - newKeySet: %w", err))
+	}
+
+	// Use case
+	useCase := usecase.New(pg, cfg, l, redis)
+
+	// Background jobs
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	go purgeDeletedAccounts(jobCtx, useCase, l, time.Duration(cfg.AccountDeletion.PurgeIntervalMinutes)*time.Minute)
+	go purgeSoftDeleted(jobCtx, useCase, l, time.Duration(cfg.SoftDelete.RetentionDays)*24*time.Hour,
+		time.Duration(cfg.SoftDelete.PurgeIntervalMinutes)*time.Minute)
+
 	// HTTP Server
 	handler := gin.New()
-	v1.NewRouter(handler, l, cfg, useCase, redis)
+	v1.NewRouter(handler, l, cfg, useCase, redis, limiter, keys)
 
 	httpServer := httpserver.New(handler, httpserver.Port(cfg.HTTP.Port))
 
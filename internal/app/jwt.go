@@ -0,0 +1,48 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/pkg/jwt"
+)
+
+// newKeySet loads the configured signing keys. The legacy secret goes last so that a
+// configured key without active_from takes over signing from it right away, and is left out
+// once keys are configured unless legacy_secret is on.
+func newKeySet(cfg config.JWT) (*jwt.KeySet, error) {
+	keys := make([]jwt.Key, 0, len(cfg.Keys)+1)
+
+	for _, k := range cfg.Keys {
+		pem, err := os.ReadFile(k.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %q: %w", k.ID, err)
+		}
+
+		var activeFrom time.Time
+		if k.ActiveFrom != "" {
+			activeFrom, err = time.Parse(time.RFC3339, k.ActiveFrom)
+			if err != nil {
+				return nil, fmt.Errorf("jwt key %q: active_from: %w", k.ID, err)
+			}
+		}
+
+		keys = append(keys, jwt.Key{
+			ID:         k.ID,
+			Algorithm:  k.Algorithm,
+			PrivateKey: string(pem),
+			ActiveFrom: activeFrom,
+		})
+	}
+
+	if cfg.Secret != "" && (len(cfg.Keys) == 0 || cfg.LegacySecret) {
+		keys = append(keys, jwt.Key{
+			Algorithm: jwt.HS256,
+			Secret:    cfg.Secret,
+		})
+	}
+
+	return jwt.NewKeySet(keys...)
+}
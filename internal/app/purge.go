@@ -0,0 +1,119 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/Akorm0181/yelp/internal/usecase"
+	"github.com/Akorm0181/yelp/pkg/logger"
+)
+
+const _purgeBatchSize = 100
+
+// purgeDeletedAccounts deletes accounts whose deletion grace period is over, every interval
+// until ctx is done. A zero interval turns the job off.
+func purgeDeletedAccounts(ctx context.Context, useCase *usecase.UseCase, l *logger.Logger, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purgeDueAccounts(ctx, useCase, l)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func purgeDueAccounts(ctx context.Context, useCase *usecase.UseCase, l *logger.Logger) {
+	for {
+		ids, err := useCase.AccountRepo.GetDueDeletions(ctx, _purgeBatchSize)
+		if err != nil {
+			l.Error(fmt.Errorf("app - purgeDueAccounts - GetDueDeletions: %w", err))
+			return
+		}
+
+		failed := 0
+		for _, id := range ids {
+			// sessions are removed by ON DELETE CASCADE which bypasses the session cache
+			_, err = useCase.SessionRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+				Filter: []entity.Filter{{Column: "user_id", Type: "eq", Value: id}},
+				Items:  []entity.UpdateFieldItem{{Column: "is_active", Value: "false"}},
+			})
+			if err != nil {
+				// the next accounts still get their turn, this one is tried again next tick
+				l.Error(fmt.Errorf("app - purgeDueAccounts - SessionRepo.UpdateField %s: %w", id, err))
+				failed++
+				continue
+			}
+
+			purged, err := useCase.AccountRepo.Purge(ctx, id)
+			if err != nil {
+				l.Error(fmt.Errorf("app - purgeDueAccounts - Purge %s: %w", id, err))
+				failed++
+				continue
+			}
+
+			if purged {
+				l.Info("app - purgeDueAccounts - account deleted: %s", id)
+			}
+		}
+
+		// the failed accounts would come back in the next batch, they wait for the next tick
+		if len(ids) < _purgeBatchSize || failed > 0 {
+			return
+		}
+	}
+}
+
+// purgeSoftDeleted removes businesses, reviews and events that were deleted longer than
+// retention ago, every interval until ctx is done. A zero interval turns the job off.
+func purgeSoftDeleted(ctx context.Context, useCase *usecase.UseCase, l *logger.Logger, retention, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purgeDueSoftDeleted(ctx, useCase, l, time.Now().Add(-retention))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func purgeDueSoftDeleted(ctx context.Context, useCase *usecase.UseCase, l *logger.Logger, deletedBefore time.Time) {
+	purges := []struct {
+		name  string
+		purge func(context.Context, time.Time) (int64, error)
+	}{
+		{"reviews", useCase.ReviewRepo.Purge},
+		{"events", useCase.EventRepo.Purge},
+		{"businesses", useCase.BusinessRepo.Purge},
+	}
+
+	for _, item := range purges {
+		purged, err := item.purge(ctx, deletedBefore)
+		if err != nil {
+			l.Error(fmt.Errorf("app - purgeDueSoftDeleted - %s: %w", item.name, err))
+			continue
+		}
+
+		if purged > 0 {
+			l.Info("app - purgeDueSoftDeleted - %s purged: %d", item.name, purged)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestAccountDeletion godoc
+// @Router /user/me/delete [post]
+// @Summary Request deletion of my account
+// @Description Signs out every session, suspends the api keys and deletes the account after the grace period. Logging in again before that cancels the deletion and lifts the suspension.
+// @Security BearerAuth
+// @Tags user
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} entity.AccountDeletionResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) RequestAccountDeletion(ctx *gin.Context) {
+	h.scheduleAccountDeletion(ctx, GetUserID(ctx))
+}
+
+func (h *Handler) scheduleAccountDeletion(ctx *gin.Context, userID string) {
+	deleteAfter := time.Now().AddDate(0, 0, h.Config.AccountDeletion.GraceDays)
+
+	err := h.UseCase.AccountRepo.RequestDeletion(ctx, userID, deleteAfter)
+	if h.HandleDbError(ctx, err, "Error requesting account deletion") {
+		return
+	}
+
+	_, err = h.UseCase.SessionRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{{Column: "user_id", Type: "eq", Value: userID}},
+		Items:  []entity.UpdateFieldItem{{Column: "is_active", Value: "false"}},
+	})
+	if h.HandleDbError(ctx, err, "Error deactivating user sessions") {
+		return
+	}
+
+	ctx.JSON(200, entity.AccountDeletionResponse{
+		Message:     "Account will be deleted, log in before the deletion date to cancel it",
+		DeleteAfter: deleteAfter.Format(time.RFC3339),
+	})
+}
+
+// cancelAccountDeletion is called on every login, signing in is how a deletion is cancelled.
+func (h *Handler) cancelAccountDeletion(ctx *gin.Context, userID string) {
+	cancelled, err := h.UseCase.AccountRepo.CancelDeletion(ctx, userID)
+	if err != nil {
+		h.Logger.Error(err, "Error cancelling account deletion")
+		return
+	}
+
+	if cancelled {
+		h.Logger.Info("account deletion cancelled by login: %s", userID)
+	}
+}
+
+// ExportMyData godoc
+// @Router /user/me/export [get]
+// @Summary Download my data
+// @Description Profile, reviews with attachments, bookmarks, follows, event participation and notifications as a JSON file
+// @Security BearerAuth
+// @Tags user
+// @Produce  json
+// @Success 200 {object} entity.AccountExport
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) ExportMyData(ctx *gin.Context) {
+	userID := GetUserID(ctx)
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: userID})
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+	user.Password = ""
+
+	export, err := h.UseCase.AccountRepo.Export(ctx, userID)
+	if h.HandleDbError(ctx, err, "Error exporting user data") {
+		return
+	}
+	export.Profile = user
+
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="yelp-data-%s.json"`, userID))
+	ctx.IndentedJSON(200, export)
+}
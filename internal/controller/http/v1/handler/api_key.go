@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/Akorm0181/yelp/pkg/hash"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateApiKey godoc
+// @Router /api-key [post]
+// @Summary Create an api key
+// @Description Creates a key for integrations, send it in the X-API-Key header. The key is only shown in this response.
+// @Security BearerAuth
+// @Tags api-key
+// @Accept  json
+// @Produce  json
+// @Param body body entity.CreateApiKeyRequest true "Api key"
+// @Success 201 {object} entity.CreateApiKeyResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateApiKey(ctx *gin.Context) {
+	var (
+		body entity.CreateApiKeyRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || body.Name == "" || len(body.Scopes) == 0 {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	for _, scope := range body.Scopes {
+		if !slices.Contains(config.ApiKeyScopes, scope) {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Unknown scope "+scope, http.StatusBadRequest)
+			return
+		}
+	}
+
+	if body.ExpiresAt != "" {
+		expiresAt, err := time.Parse(time.RFC3339, body.ExpiresAt)
+		if err != nil || expiresAt.Before(time.Now()) {
+			h.ReturnError(ctx, config.ErrorBadRequest, "expires_at must be a future RFC3339 time", http.StatusBadRequest)
+			return
+		}
+	}
+
+	token, err := hash.GenerateToken(32)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorInternalServer, "Oops, something went wrong!!!", http.StatusInternalServerError)
+		return
+	}
+	key := config.ApiKeyPrefix + token
+
+	apiKey, err := h.UseCase.ApiKeyRepo.Create(ctx, entity.ApiKey{
+		UserID:    GetUserID(ctx),
+		Name:      body.Name,
+		Prefix:    key[:len(config.ApiKeyPrefix)+6],
+		KeyHash:   hash.HashToken(key),
+		Scopes:    slices.Compact(slices.Sorted(slices.Values(body.Scopes))),
+		ExpiresAt: body.ExpiresAt,
+	})
+	if h.HandleDbError(ctx, err, "Error creating api key") {
+		return
+	}
+
+	ctx.JSON(201, entity.CreateApiKeyResponse{
+		ApiKey: apiKey,
+		Key:    key,
+	})
+}
+
+// GetMyApiKeys godoc
+// @Router /api-key/list [get]
+// @Summary Get my api keys
+// @Description Get my api keys
+// @Security BearerAuth
+// @Tags api-key
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Success 200 {object} entity.ApiKeyList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetMyApiKeys(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+	req.Filters = append(req.Filters, entity.Filter{
+		Column: "user_id",
+		Type:   "eq",
+		Value:  GetUserID(ctx),
+	})
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "created_at",
+		Order:  "desc",
+	})
+
+	keys, err := h.UseCase.ApiKeyRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting api keys") {
+		return
+	}
+
+	ctx.JSON(200, keys)
+}
+
+// RevokeApiKey godoc
+// @Router /api-key/{id} [delete]
+// @Summary Revoke an api key
+// @Description Revoke an api key
+// @Security BearerAuth
+// @Tags api-key
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Api key ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) RevokeApiKey(ctx *gin.Context) {
+	rows, err := h.UseCase.ApiKeyRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{
+			{Column: "id", Type: "eq", Value: ctx.Param("id")},
+			{Column: "user_id", Type: "eq", Value: GetUserID(ctx)},
+			{Column: "revoked_at", Type: "isnull"},
+		},
+		Items: []entity.UpdateFieldItem{
+			{Column: "revoked_at", Value: "now()"},
+			{Column: "updated_at", Value: "now()"},
+		},
+	})
+	if h.HandleDbError(ctx, err, "Error revoking api key") {
+		return
+	}
+
+	if rows.RowsEffected == 0 {
+		h.ReturnError(ctx, config.ErrorNotFound, "Api key not found", http.StatusNotFound)
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Api key revoked",
+	})
+}
+
+// apiKeyPrincipal authenticates a request made with an api key. The principal gets the
+// current role of the key owner, the key's scopes narrow it down further in AuthMiddleware.
+func (h *Handler) apiKeyPrincipal(ctx *gin.Context, key string) (entity.Principal, bool) {
+	apiKey, err := h.UseCase.ApiKeyRepo.GetSingle(ctx, entity.ApiKeySingleRequest{
+		KeyHash: hash.HashToken(key),
+	})
+	if err != nil || apiKey.RevokedAt != "" || apiKey.SuspendedAt != "" {
+		return entity.Principal{}, false
+	}
+
+	if apiKey.ExpiresAt != "" {
+		expiresAt, err := time.Parse(time.RFC3339, apiKey.ExpiresAt)
+		if err == nil && time.Now().After(expiresAt) {
+			return entity.Principal{}, false
+		}
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: apiKey.UserID})
+	if err != nil {
+		return entity.Principal{}, false
+	}
+
+	h.touchApiKey(ctx, apiKey)
+
+	return entity.Principal{
+		UserID:   user.ID,
+		UserRole: user.UserRole,
+		UserType: user.UserType,
+		ApiKeyID: apiKey.ID,
+		Scopes:   apiKey.Scopes,
+	}, true
+}
+
+// touchApiKey bumps last_used_at of the key, at most once per config.ApiKeyTouchInterval.
+func (h *Handler) touchApiKey(ctx *gin.Context, apiKey entity.ApiKey) {
+	lastUsedAt, err := time.Parse(time.RFC3339, apiKey.LastUsedAt)
+	if err == nil && time.Since(lastUsedAt) < config.ApiKeyTouchInterval {
+		return
+	}
+
+	_, err = h.UseCase.ApiKeyRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{{Column: "id", Type: "eq", Value: apiKey.ID}},
+		Items:  []entity.UpdateFieldItem{{Column: "last_used_at", Value: "now()"}},
+	})
+	if err != nil {
+		h.Logger.Error(err, "Error updating api key last_used_at")
+	}
+}
@@ -1,7 +1,7 @@
 package handler
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
 	"time"
 
@@ -9,8 +9,8 @@ import (
 	"github.com/Akorm0181/yelp/internal/entity"
 	"github.com/Akorm0181/yelp/pkg/etc"
 	"github.com/Akorm0181/yelp/pkg/hash"
-	"github.com/Akorm0181/yelp/pkg/jwt"
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
 )
 
 // Login godoc
@@ -23,6 +23,7 @@ import (
 // @Param body body entity.LoginRequest true "User"
 // @Success 200 {object} entity.SuccessResponse
 // @Failure 400 {object} entity.ErrorResponse
+// @Failure 429 {object} entity.ErrorResponse
 func (h *Handler) Login(ctx *gin.Context) {
 	var (
 		body entity.LoginRequest
@@ -42,57 +43,43 @@ func (h *Handler) Login(ctx *gin.Context) {
 		return
 	}
 
-	if user.UserType == "user" && body.Platform == "admin" {
-		h.ReturnError(ctx, config.ErrorForbidden, "User can't login to admin web", http.StatusBadRequest)
+	if !h.checkLoginPlatform(ctx, user, body.Platform) {
 		return
-	} else if user.UserType == "admin" && body.Platform != "admin" {
-		h.ReturnError(ctx, config.ErrorForbidden, "Admin can only login to admin web", http.StatusBadRequest)
-		return
-	} else if user.UserType == "business_owner" && body.Platform != "business_owner" {
-		h.ReturnError(ctx, config.ErrorForbidden, "Business owner can only login to business owner web", http.StatusBadRequest)
+	}
+
+	if lockout := h.loginLocked(ctx, user.ID); lockout > 0 {
+		h.returnLoginLocked(ctx, lockout)
 		return
 	}
 
 	if !hash.CheckPasswordHash(body.Password, user.Password) {
+		if lockout := h.recordLoginFailure(ctx, user.ID); lockout > 0 {
+			h.returnLoginLocked(ctx, lockout)
+			return
+		}
+
 		h.ReturnError(ctx, config.ErrorInvalidPass, "Incorrect password", http.StatusBadRequest)
 		return
 	}
 
-	// create session
-	newSession := entity.Session{
-		UserID:       user.ID,
-		IPAddress:    ctx.ClientIP(),
-		ExpiresAt:    time.Now().Add(time.Hour * 999999).Format(time.RFC3339),
-		UserAgent:    ctx.Request.UserAgent(),
-		IsActive:     true,
-		LastActiveAt: time.Now().Format(time.RFC3339),
-		Platform:     body.Platform,
-	}
+	h.resetLoginFailures(ctx, user.ID)
 
-	session, err := h.UseCase.SessionRepo.Create(ctx, newSession)
-	if h.HandleDbError(ctx, err, "Error while creating new session") {
+	if !h.checkAccountStatus(ctx, &user) {
 		return
 	}
 
-	// generate jwt token
-	jwtFields := map[string]interface{}{
-		"sub":        user.ID,
-		"user_role":  user.UserRole,
-		"user_type":  user.UserType,
-		"platform":   body.Platform,
-		"session_id": session.ID,
+	// accounts with two-factor authentication get a short-lived mfa token instead of a session
+	mfaResponse, err := h.mfaLoginChallenge(ctx, user, body.Platform)
+	if h.HandleDbError(ctx, err, "Error checking two-factor authentication") {
+		return
 	}
 
-	user.AccessToken, err = jwt.GenerateJWT(jwtFields, h.Config.JWT.Secret)
-	if err != nil {
-		h.ReturnError(ctx, config.ErrorInternalServer, "Oops, something went wrong!!!", http.StatusInternalServerError)
+	if mfaResponse != nil {
+		ctx.JSON(200, mfaResponse)
 		return
 	}
 
-	ctx.JSON(200, gin.H{
-		"user":    user,
-		"session": session,
-	})
+	h.completeLogin(ctx, user, body.Platform)
 }
 
 // Logout godoc
@@ -106,7 +93,7 @@ func (h *Handler) Login(ctx *gin.Context) {
 // @Success 200 {object} entity.SuccessResponse
 // @Failure 400 {object} entity.ErrorResponse
 func (h *Handler) Logout(ctx *gin.Context) {
-	sessionID := ctx.GetHeader("session_id")
+	sessionID := GetSessionID(ctx)
 	if sessionID == "" {
 		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid session ID", 400)
 		return
@@ -134,6 +121,7 @@ func (h *Handler) Logout(ctx *gin.Context) {
 // @Param body body entity.RegisterRequest true "User"
 // @Success 200 {object} entity.User
 // @Failure 400 {object} entity.ErrorResponse
+// @Failure 429 {object} entity.ErrorResponse
 func (h *Handler) Register(ctx *gin.Context) {
 	var (
 		body entity.RegisterRequest
@@ -154,6 +142,10 @@ func (h *Handler) Register(ctx *gin.Context) {
 		return
 	}
 
+	if !h.usernameAvailable(ctx, body.UserName, "") {
+		return
+	}
+
 	body.Password, err = hash.HashPassword(body.Password)
 	if err != nil {
 		h.ReturnError(ctx, config.ErrorInternalServer, "Oops, something went wrong!!!", http.StatusInternalServerError)
@@ -174,24 +166,9 @@ func (h *Handler) Register(ctx *gin.Context) {
 		return
 	}
 
-	// send verification code to user
-	otp := etc.GenerateOTP(6)
-	err = h.Redis.Set(ctx, fmt.Sprintf("otp-%s", user.Email), otp, 5*60)
-	if err != nil {
-		h.ReturnError(ctx, config.ErrorInternalServer, "Error setting OTP", 500)
-		return
-	}
-
-	// send otp code to user's email
-	emailBody, err := etc.GenerateOtpEmailBody(otp)
-	if err != nil {
-		h.ReturnError(ctx, config.ErrorInternalServer, "Error sending OTP", 500)
-		return
-	}
-
-	err = etc.SendEmail(h.Config.Gmail.Host, h.Config.Gmail.Port, h.Config.Gmail.Email, h.Config.Gmail.EmailPass, body.Email, emailBody)
-	if err != nil {
-		h.ReturnError(ctx, config.ErrorInternalServer, "Error sending OTP", 500)
+	// send verification code to user's email
+	err = h.sendOtp(ctx, otpVerify, user.Email, etc.GenerateOtpEmailBody)
+	if h.HandleOtpSendError(ctx, err) {
 		return
 	}
 
@@ -221,66 +198,419 @@ func (h *Handler) VerifyEmail(ctx *gin.Context) {
 		return
 	}
 
-	key := fmt.Sprintf("otp-%s", body.Email)
+	if !h.checkOtp(ctx, otpVerify, body.Email, body.Otp) {
+		return
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{
+		Email: body.Email,
+	})
+	if h.HandleDbError(ctx, err, "get single user") {
+		return
+	}
+
+	// only an unverified account is activated, a blocked one stays blocked
+	if user.Status == "inverify" {
+		_, err = h.UseCase.UserRepo.ChangeStatus(ctx, entity.UserStatusChange{
+			UserID:     user.ID,
+			Status:     "active",
+			Reason:     "email verified",
+			FromStatus: "inverify",
+		})
+		switch {
+		case err == nil:
+			user.Status = "active"
+			_ = h.Redis.Del(ctx, userStatusKey(user.ID))
+		case errors.Is(err, pgx.ErrNoRows):
+			// the status was changed in the meantime, the check below goes by the new one
+			user, err = h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: user.ID})
+			if h.HandleDbError(ctx, err, "get single user") {
+				return
+			}
+		default:
+			h.HandleDbError(ctx, err, "Error verifying email")
+			return
+		}
+	}
+
+	if !h.checkLoginPlatform(ctx, user, body.Platform) {
+		return
+	}
+
+	if !h.checkAccountStatus(ctx, &user) {
+		return
+	}
+
+	// the emailed code is no second factor, accounts with 2fa still have to pass it
+	mfaResponse, err := h.mfaLoginChallenge(ctx, user, body.Platform)
+	if h.HandleDbError(ctx, err, "Error checking two-factor authentication") {
+		return
+	}
 
-	otp, err := h.Redis.Get(ctx, key)
+	if mfaResponse != nil {
+		ctx.JSON(200, mfaResponse)
+		return
+	}
+
+	h.completeLogin(ctx, user, body.Platform)
+}
+
+// RefreshToken godoc
+// @Router /auth/refresh [post]
+// @Summary Refresh access token
+// @Description Exchanges a refresh token for a new access/refresh token pair. Presenting an already used refresh token revokes the whole session.
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param body body entity.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} entity.TokenResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+func (h *Handler) RefreshToken(ctx *gin.Context) {
+	var (
+		body entity.RefreshTokenRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || body.RefreshToken == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	token, err := h.UseCase.RefreshTokenRepo.GetSingle(ctx, entity.RefreshTokenSingleRequest{
+		TokenHash: hash.HashToken(body.RefreshToken),
+	})
 	if err != nil {
-		h.ReturnError(ctx, config.ErrorInternalServer, "Ooops, something went wrong", http.StatusInternalServerError)
+		h.ReturnError(ctx, config.ErrorInvalidToken, "Invalid refresh token", http.StatusUnauthorized)
 		return
 	}
 
-	if otp != body.Otp {
-		h.ReturnError(ctx, config.ErrorBadRequest, "Incorrect otp", http.StatusBadRequest)
+	if token.UsedAt != "" {
+		h.revokeSessionFamily(ctx, token.SessionID)
+		h.ReturnError(ctx, config.ErrorTokenReused, "Refresh token has already been used, session revoked", http.StatusUnauthorized)
 		return
 	}
 
-	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{
-		Email: body.Email,
+	expiresAt, _ := time.Parse(time.RFC3339, token.ExpiresAt)
+	if token.IsRevoked || time.Now().After(expiresAt) {
+		h.ReturnError(ctx, config.ErrorSessionExpired, "Refresh token expired", http.StatusUnauthorized)
+		return
+	}
+
+	session, err := h.UseCase.SessionRepo.GetSingle(ctx, entity.Id{ID: token.SessionID})
+	if err != nil || !session.IsActive {
+		h.ReturnError(ctx, config.ErrorSessionExpired, "Session is not active", http.StatusUnauthorized)
+		return
+	}
+
+	// mark the token as used, a concurrent refresh with the same token loses the race and is treated as reuse
+	res, err := h.UseCase.RefreshTokenRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{
+			{Column: "id", Type: "eq", Value: token.ID},
+			{Column: "used_at", Type: "isnull"},
+		},
+		Items: []entity.UpdateFieldItem{
+			{Column: "used_at", Value: "now()"},
+		},
 	})
-	if h.HandleDbError(ctx, err, "get single user") {
+	if h.HandleDbError(ctx, err, "Error updating refresh token") {
+		return
+	}
+
+	if res.RowsEffected == 0 {
+		h.revokeSessionFamily(ctx, token.SessionID)
+		h.ReturnError(ctx, config.ErrorTokenReused, "Refresh token has already been used, session revoked", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: session.UserID})
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+
+	if !h.checkAccountStatus(ctx, &user) {
+		return
+	}
+
+	session.IPAddress = ctx.ClientIP()
+	session, err = h.UseCase.SessionRepo.Update(ctx, session)
+	if h.HandleDbError(ctx, err, "Error updating session") {
+		return
+	}
+
+	accessToken, refreshToken, err := h.issueTokens(ctx, user, session)
+	if h.HandleDbError(ctx, err, "Error issuing tokens") {
 		return
 	}
 
-	user.Status = "active"
+	ctx.JSON(200, entity.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		Session:      session,
+	})
+}
+
+// checkLoginPlatform makes sure the account signs in on the platform its role belongs to.
+// Business owners are users with the business_owner role, so the role is checked, not the type.
+func (h *Handler) checkLoginPlatform(ctx *gin.Context, user entity.User, platform string) bool {
+	switch {
+	case user.UserType == "admin" && platform != "admin":
+		h.ReturnError(ctx, config.ErrorForbidden, "Admin can only login to admin web", http.StatusBadRequest)
+	case user.UserType == "admin":
+		return true
+	case user.UserRole == "business_owner" && platform != "business_owner":
+		h.ReturnError(ctx, config.ErrorForbidden, "Business owner can only login to business owner web", http.StatusBadRequest)
+	case user.UserRole != "business_owner" && (platform == "admin" || platform == "business_owner"):
+		h.ReturnError(ctx, config.ErrorForbidden, "User can't login to "+platform+" web", http.StatusBadRequest)
+	default:
+		return true
+	}
+
+	return false
+}
 
-	_, err = h.UseCase.UserRepo.Update(ctx, user)
-	if h.HandleDbError(ctx, err, "update user") {
+// completeLogin starts a session for the user and writes the login response.
+func (h *Handler) completeLogin(ctx *gin.Context, user entity.User, platform string) {
+	session, err := h.startSession(ctx, &user, platform)
+	if h.HandleDbError(ctx, err, "Error while creating new session") {
 		return
 	}
 
-	// create session
-	newSession := entity.Session{
+	h.cancelAccountDeletion(ctx, user.ID)
+
+	user.Password = ""
+
+	ctx.JSON(200, gin.H{
+		"user":    user,
+		"session": session,
+	})
+}
+
+// startSession creates a new session for the user and sets a fresh access/refresh token pair on it.
+func (h *Handler) startSession(ctx *gin.Context, user *entity.User, platform string) (entity.Session, error) {
+	session, err := h.UseCase.SessionRepo.Create(ctx, entity.Session{
 		UserID:       user.ID,
 		IPAddress:    ctx.ClientIP(),
-		ExpiresAt:    time.Now().Add(time.Hour * 999999).Format(time.RFC3339),
+		ExpiresAt:    time.Now().Add(config.TokenExpireTime).Format(time.RFC3339),
 		UserAgent:    ctx.Request.UserAgent(),
 		IsActive:     true,
 		LastActiveAt: time.Now().Format(time.RFC3339),
-		Platform:     body.Platform,
+		Platform:     platform,
+	})
+	if err != nil {
+		return entity.Session{}, err
 	}
 
-	session, err := h.UseCase.SessionRepo.Create(ctx, newSession)
-	if h.HandleDbError(ctx, err, "Error while creating new session") {
-		return
+	user.AccessToken, user.RefreshToken, err = h.issueTokens(ctx, *user, session)
+	if err != nil {
+		return entity.Session{}, err
 	}
 
-	// generate jwt token
+	return session, nil
+}
+
+// Values of the typ claim. Access and mfa tokens are signed with the same keys, verifiers
+// that read the JWKS tell them apart by it.
+const (
+	accessTokenType = "access"
+	mfaTokenType    = "mfa"
+)
+
+// issueTokens signs a short-lived access token and stores a new refresh token for the session.
+// Refresh tokens never outlive the session they belong to.
+func (h *Handler) issueTokens(ctx *gin.Context, user entity.User, session entity.Session) (string, string, error) {
 	jwtFields := map[string]interface{}{
+		"typ":        accessTokenType,
 		"sub":        user.ID,
 		"user_role":  user.UserRole,
 		"user_type":  user.UserType,
-		"platform":   body.Platform,
+		"platform":   session.Platform,
 		"session_id": session.ID,
 	}
 
-	user.AccessToken, err = jwt.GenerateJWT(jwtFields, h.Config.JWT.Secret)
+	accessToken, err := h.JWT.Sign(jwtFields, config.AccessTokenExpireTime)
+	if err != nil {
+		return "", "", err
+	}
+
+	refreshToken, err := hash.GenerateToken(32)
+	if err != nil {
+		return "", "", err
+	}
+
+	_, err = h.UseCase.RefreshTokenRepo.Create(ctx, entity.RefreshToken{
+		SessionID: session.ID,
+		TokenHash: hash.HashToken(refreshToken),
+		ExpiresAt: session.ExpiresAt,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+// revokeSessionFamily deactivates the session and every refresh token issued for it.
+func (h *Handler) revokeSessionFamily(ctx *gin.Context, sessionID string) {
+	_, err := h.UseCase.SessionRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{{Column: "id", Type: "eq", Value: sessionID}},
+		Items: []entity.UpdateFieldItem{
+			{Column: "is_active", Value: "false"},
+			{Column: "updated_at", Value: "now()"},
+		},
+	})
+	if err != nil {
+		h.Logger.Error(err, "Error revoking session")
+	}
+
+	_, err = h.UseCase.RefreshTokenRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{{Column: "session_id", Type: "eq", Value: sessionID}},
+		Items:  []entity.UpdateFieldItem{{Column: "is_revoked", Value: "true"}},
+	})
+	if err != nil {
+		h.Logger.Error(err, "Error revoking refresh tokens")
+	}
+}
+
+// ResendOtp godoc
+// @Router /auth/resend-otp [post]
+// @Summary Resend verification code
+// @Description Sends a new email verification code to a user that is not verified yet
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param body body entity.ResendOtpRequest true "Email"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 429 {object} entity.ErrorResponse
+func (h *Handler) ResendOtp(ctx *gin.Context) {
+	var (
+		body entity.ResendOtpRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || body.Email == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	response := entity.SuccessResponse{
+		Message: "If the email is waiting for verification, a new code has been sent",
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{Email: body.Email})
+	if err != nil || user.Status != "inverify" {
+		ctx.JSON(200, response)
+		return
+	}
+
+	err = h.sendOtp(ctx, otpVerify, user.Email, etc.GenerateOtpEmailBody)
+	if h.HandleOtpSendError(ctx, err) {
+		return
+	}
+
+	ctx.JSON(200, response)
+}
+
+// ForgotPassword godoc
+// @Router /auth/forgot-password [post]
+// @Summary Forgot password
+// @Description Sends a password reset code to the email if an account with it exists
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param body body entity.ForgotPasswordRequest true "Email"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) ForgotPassword(ctx *gin.Context) {
+	var (
+		body entity.ForgotPasswordRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || body.Email == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	response := entity.SuccessResponse{
+		Message: "If an account with this email exists, a reset code has been sent",
+	}
+
+	// the same answer is returned for unknown emails so the endpoint can't be used to enumerate accounts
+	_, err = h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{Email: body.Email})
+	if err != nil {
+		ctx.JSON(200, response)
+		return
+	}
+
+	err = h.sendOtp(ctx, otpReset, body.Email, etc.GeneratePasswordResetEmailBody)
+	if h.HandleOtpSendError(ctx, err) {
+		return
+	}
+
+	ctx.JSON(200, response)
+}
+
+// ResetPassword godoc
+// @Router /auth/reset-password [post]
+// @Summary Reset password
+// @Description Sets a new password using the emailed code and logs the user out of every session
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param body body entity.ResetPasswordRequest true "Reset password"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) ResetPassword(ctx *gin.Context) {
+	var (
+		body entity.ResetPasswordRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || body.Email == "" || body.NewPassword == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if !h.checkOtp(ctx, otpReset, body.Email, body.Otp) {
+		return
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{Email: body.Email})
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+
+	password, err := hash.HashPassword(body.NewPassword)
 	if err != nil {
 		h.ReturnError(ctx, config.ErrorInternalServer, "Oops, something went wrong!!!", http.StatusInternalServerError)
 		return
 	}
 
-	ctx.JSON(200, gin.H{
-		"user":    user,
-		"session": session,
+	_, err = h.UseCase.UserRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{{Column: "id", Type: "eq", Value: user.ID}},
+		Items: []entity.UpdateFieldItem{
+			{Column: "password", Value: password},
+			{Column: "updated_at", Value: "now()"},
+		},
+	})
+	if h.HandleDbError(ctx, err, "Error updating password") {
+		return
+	}
+
+	_, err = h.UseCase.SessionRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{{Column: "user_id", Type: "eq", Value: user.ID}},
+		Items: []entity.UpdateFieldItem{
+			{Column: "is_active", Value: "false"},
+			{Column: "updated_at", Value: "now()"},
+		},
+	})
+	if h.HandleDbError(ctx, err, "Error deactivating sessions") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Password has been reset, please login again",
 	})
 }
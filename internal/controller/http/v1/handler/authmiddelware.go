@@ -1,12 +1,12 @@
 package handler
 
 import (
-	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/Akorm0181/yelp/config"
 	"github.com/Akorm0181/yelp/internal/entity"
-	"github.com/Akorm0181/yelp/pkg/jwt"
 	"github.com/casbin/casbin"
 	"github.com/gin-gonic/gin"
 )
@@ -14,42 +14,68 @@ import (
 func (h *Handler) AuthMiddleware(e *casbin.Enforcer) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var (
-			userRole string
-			act      = c.Request.Method
-			obj      = c.FullPath()
+			userRole  string
+			tokenErr  error
+			principal entity.Principal
+			act       = c.Request.Method
+			obj       = c.FullPath()
 		)
 
+		for _, header := range claimHeaders {
+			c.Request.Header.Del(header)
+		}
+
 		token := c.GetHeader("Authorization")
-		if token == "" {
+		apiKey := c.GetHeader(apiKeyHeader)
+
+		switch {
+		case token == "" && apiKey != "":
+			var ok bool
+			principal, ok = h.apiKeyPrincipal(c, apiKey)
+			if !ok {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Api key is invalid"})
+				return
+			}
+			if !h.accountActive(c, principal.UserID) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Account is not active"})
+				return
+			}
+			userRole = principal.UserRole
+			c.Set(principalKey, principal)
+		case token == "":
 			userRole = "unauthorized"
 		}
 
 		if userRole == "" {
 			token = strings.TrimPrefix(token, "Bearer ")
 
-			claims, err := jwt.ParseJWT(token, h.Config.JWT.Secret)
+			claims, err := h.JWT.Parse(token)
 			if err != nil {
-				userRole = "unauthorized"
+				tokenErr = err
 			}
 
-			v, ok := claims["user_role"].(string)
-			if !ok {
+			// access tokens signed before the typ claim was added have none
+			typ, hasTyp := claims["typ"]
+
+			principal = principalFromClaims(claims)
+			if err != nil || (hasTyp && typ != accessTokenType) || principal.UserRole == "" || principal.SessionID == "" {
 				userRole = "unauthorized"
+				principal = entity.Principal{}
 			} else {
-				userRole = v
-			}
-
-			for key, value := range claims {
-				c.Request.Header.Set(key, fmt.Sprintf("%v", value))
+				userRole = principal.UserRole
 			}
 		}
 
-		// TO DO: Check if session is valid
-
-		if userRole != "unauthorized" {
-			session, err := h.UseCase.SessionRepo.GetSingle(c, entity.Id{ID: c.GetHeader("session_id")})
+		// session lookups are served from the redis backed session cache
+		if userRole != "unauthorized" && principal.ApiKeyID == "" {
+			session, err := h.UseCase.SessionRepo.GetSingle(c, entity.Id{ID: principal.SessionID})
 			if err != nil {
-				fmt.Println("error while gettign single session", err)
+				h.Logger.Error(err, "error while getting single session")
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Session is invalid"})
+				return
+			}
+
+			if session.UserID != principal.UserID {
 				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Session is invalid"})
 				return
 			}
@@ -58,9 +84,26 @@ func (h *Handler) AuthMiddleware(e *casbin.Enforcer) gin.HandlerFunc {
 				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Session is not active"})
 				return
 			}
+
+			expiresAt, err := time.Parse(time.RFC3339, session.ExpiresAt)
+			if err == nil && time.Now().After(expiresAt) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Session is expired"})
+				return
+			}
+
+			if !h.accountActive(c, principal.UserID) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Account is not active"})
+				return
+			}
+
+			h.touchSession(c, session)
+			c.Set(principalKey, principal)
 		}
 
 		ok, err := e.EnforceSafe(userRole, obj, act)
+		if err == nil && ok && principal.ApiKeyID != "" {
+			ok, err = enforceScopes(e, principal.Scopes, obj, act)
+		}
 		if err != nil {
 			h.Logger.Error(err, "Error enforcing policy")
 			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "access denied"})
@@ -68,6 +111,12 @@ func (h *Handler) AuthMiddleware(e *casbin.Enforcer) gin.HandlerFunc {
 		}
 
 		if !ok {
+			// an expired or malformed access token should make the client refresh, not give up
+			if tokenErr != nil {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token is invalid or expired"})
+				return
+			}
+
 			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "access denied"})
 			return
 		}
@@ -75,3 +124,36 @@ func (h *Handler) AuthMiddleware(e *casbin.Enforcer) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// apiKeyHeader carries the api key of integrations, used instead of a Bearer token.
+const apiKeyHeader = "X-API-Key"
+
+// enforceScopes allows a request made with an api key when one of the key's scopes does.
+// The role of the key owner has to allow the request as well.
+func enforceScopes(e *casbin.Enforcer, scopes []string, obj, act string) (bool, error) {
+	for _, scope := range scopes {
+		ok, err := e.EnforceSafe("scope:"+scope, obj, act)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+
+	return false, nil
+}
+
+// touchSession bumps last_active_at of the session, at most once per config.SessionTouchInterval
+// so the device list stays meaningful without a write on every request.
+func (h *Handler) touchSession(c *gin.Context, session entity.Session) {
+	lastActiveAt, err := time.Parse(time.RFC3339, session.LastActiveAt)
+	if err == nil && time.Since(lastActiveAt) < config.SessionTouchInterval {
+		return
+	}
+
+	_, err = h.UseCase.SessionRepo.UpdateField(c, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{{Column: "id", Type: "eq", Value: session.ID}},
+		Items:  []entity.UpdateFieldItem{{Column: "last_active_at", Value: "now()"}},
+	})
+	if err != nil {
+		h.Logger.Error(err, "Error updating session last_active_at")
+	}
+}
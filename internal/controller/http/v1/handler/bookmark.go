@@ -19,6 +19,7 @@ import (
 // @Param bookmark body entity.Bookmark true "Bookmark object"
 // @Success 201 {object} entity.Bookmark
 // @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
 func (h *Handler) CreateBookmark(ctx *gin.Context) {
 	var (
 		body entity.Bookmark
@@ -30,7 +31,11 @@ func (h *Handler) CreateBookmark(ctx *gin.Context) {
 		return
 	}
 
-	body.UserID = ctx.GetHeader("sub")
+	body.UserID = GetUserID(ctx)
+
+	if !h.checkBusinessLive(ctx, body.BusinessID) {
+		return
+	}
 
 	bookmark, err := h.UseCase.BookmarkRepo.Create(ctx, body)
 	if h.HandleDbError(ctx, err, "Error creating bookmark") {
@@ -159,8 +164,8 @@ func (h *Handler) DeleteBookmark(ctx *gin.Context) {
 
 	req.ID = ctx.Param("id")
 
-	if ctx.GetHeader("user_type") == "user" {
-		req.ID = ctx.GetHeader("sub")
+	if GetUserType(ctx) == "user" {
+		req.ID = GetUserID(ctx)
 	}
 
 	err := h.UseCase.BookmarkRepo.Delete(ctx, req)
@@ -1,14 +1,21 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Akorm0181/yelp/config"
 	"github.com/Akorm0181/yelp/internal/entity"
 	"github.com/Akorm0181/yelp/pkg/firebase"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 )
 
 // CreateBusiness godoc
@@ -33,7 +40,48 @@ func (h *Handler) CreateBusiness(ctx *gin.Context) {
 		return
 	}
 
-	body.OwnerID = ctx.GetHeader("sub")
+	// a business owner's own listing is claimed, listings added by anyone else wait for a claim
+	body.CreatedBy = GetUserID(ctx)
+	body.OwnerID = ""
+	body.ClaimState = "unclaimed"
+	if GetUserRole(ctx) == "business_owner" {
+		body.OwnerID = GetUserID(ctx)
+		body.ClaimState = "claimed"
+	}
+
+	err = validateBusinessHours(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !h.checkTimeZone(ctx, body.TimeZone) {
+		return
+	}
+
+	if !validPriceRange(body.PriceRange) {
+		h.ReturnError(ctx, config.ErrorBadRequest, "price_range must be one of $, $$, $$$ or $$$$", http.StatusBadRequest)
+		return
+	}
+
+	err = validateBusinessCategories(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if len(body.Attributes) > 0 {
+		attributes, err := h.UseCase.BusinessAttributeRepo.GetByCategories(ctx, body.CategoryIDs)
+		if h.HandleDbError(ctx, err, "Error getting category attributes") {
+			return
+		}
+
+		err = validateAttributeValues(attributes, body.Attributes)
+		if err != nil {
+			h.ReturnError(ctx, config.ErrorBadRequest, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
 
 	business, err := h.UseCase.BusinessRepo.Create(ctx, body)
 	if h.HandleDbError(ctx, err, "Error creating business") {
@@ -42,6 +90,7 @@ func (h *Handler) CreateBusiness(ctx *gin.Context) {
 
 	business.Attachments, err = h.UseCase.BusinessAttachmentRepo.MultipleUpsert(ctx, entity.BusinessAttachmentMultipleInsertRequest{
 		BusinessId:  business.ID,
+		UploadedBy:  GetUserID(ctx),
 		Attachments: body.Attachments,
 	})
 	if h.HandleDbError(ctx, err, "Error creating business") {
@@ -76,19 +125,7 @@ func (h *Handler) GetBusiness(ctx *gin.Context) {
 		return
 	}
 
-	businessAttachments, err := h.UseCase.BusinessAttachmentRepo.GetList(ctx,
-		entity.GetListFilter{
-			Filters: []entity.Filter{
-				{
-					Column: "business_id",
-					Type:   "eq",
-					Value:  req.ID,
-				},
-			},
-			Page:  1,
-			Limit: 10,
-		},
-	)
+	businessAttachments, err := h.UseCase.BusinessAttachmentRepo.GetList(ctx, galleryFilter(req.ID))
 
 	if h.HandleDbError(ctx, err, "Error getting tweet attachments") {
 		return
@@ -102,7 +139,7 @@ func (h *Handler) GetBusiness(ctx *gin.Context) {
 // GetBusinesss godoc
 // @Router /business/list [get]
 // @Summary Get a list of businesses
-// @Description Get a list of businesses
+// @Description Get a list of businesses. With lat and lng every business gets its distance_km and the list is sorted by it, radius_km limits the search to a circle. min_lat, min_lng, max_lat and max_lng limit it to a bounding box.
 // @Security BearerAuth
 // @Tags business
 // @Accept  json
@@ -110,11 +147,26 @@ func (h *Handler) GetBusiness(ctx *gin.Context) {
 // @Param page query number true "page"
 // @Param limit query number true "limit"
 // @Param search query string false "search"
+// @Param lat query number false "latitude of the point to search around"
+// @Param lng query number false "longitude of the point to search around"
+// @Param radius_km query number false "search radius in km"
+// @Param min_lat query number false "bounding box"
+// @Param min_lng query number false "bounding box"
+// @Param max_lat query number false "bounding box"
+// @Param max_lng query number false "bounding box"
+// @Param price_range query string false "comma separated price ranges, $ to $$$$ or 1 to 4"
+// @Param category query string false "category id or slug, businesses of the categories below it match too"
+// @Param attr[key] query string false "attribute filter like attr[wifi]=free, may be repeated for different keys"
+// @Param open_now query bool false "only businesses open right now"
+// @Param open_at query string false "only businesses open at this RFC3339 time"
+// @Param min_rating query number false "only businesses rated at least this"
+// @Param sort query string false "distance, rating, review_count or created_at"
 // @Success 200 {object} entity.BusinessList
 // @Failure 400 {object} entity.ErrorResponse
 func (h *Handler) GetBusinesses(ctx *gin.Context) {
 	var (
-		req entity.GetListFilter
+		req entity.BusinessListRequest
+		err error
 	)
 
 	page := ctx.DefaultQuery("page", "1")
@@ -123,29 +175,106 @@ func (h *Handler) GetBusinesses(ctx *gin.Context) {
 
 	req.Page, _ = strconv.Atoi(page)
 	req.Limit, _ = strconv.Atoi(limit)
-	req.Filters = append(req.Filters,
-		entity.Filter{
-			Column: "name",
-			Type:   "search",
-			Value:  search,
-		},
-		entity.Filter{
-			Column: "address",
-			Type:   "search",
-			Value:  search,
-		},
-		entity.Filter{
-			Column: "description",
-			Type:   "search",
-			Value:  search,
-		},
-	)
-
-	req.OrderBy = append(req.OrderBy, entity.OrderBy{
-		Column: "created_at",
-		Order:  "desc",
+	req.Filters = append(req.Filters, entity.Filter{
+		Column: "b.search_vector",
+		Type:   "fts",
+		Value:  search,
 	})
 
+	req.Near, err = parseGeoRadius(ctx)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req.Box, err = parseGeoBox(ctx)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req.Attributes = ctx.QueryMap("attr")
+
+	if category := ctx.Query("category"); category != "" {
+		businessCategory, err := h.categoryFromParam(ctx, category)
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Unknown category "+category, http.StatusBadRequest)
+			return
+		}
+		if h.HandleDbError(ctx, err, "Error getting business-category") {
+			return
+		}
+		req.CategoryID = businessCategory.ID
+	}
+
+	req.PriceRanges, err = parsePriceRanges(ctx.Query("price_range"))
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if minRating := ctx.Query("min_rating"); minRating != "" {
+		rating, err := strconv.ParseFloat(minRating, 64)
+		if err != nil || rating < 1 || rating > 5 {
+			h.ReturnError(ctx, config.ErrorBadRequest, "min_rating must be between 1 and 5", http.StatusBadRequest)
+			return
+		}
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "b.rating_avg",
+			Type:   "gte",
+			Value:  minRating,
+		})
+	}
+
+	req.OpenAt = ctx.Query("open_at")
+	if req.OpenAt != "" {
+		_, err = time.Parse(time.RFC3339, req.OpenAt)
+		if err != nil {
+			h.ReturnError(ctx, config.ErrorBadRequest, "open_at must be an RFC3339 time", http.StatusBadRequest)
+			return
+		}
+	} else if ctx.Query("open_now") == "true" {
+		req.OpenAt = time.Now().Format(time.RFC3339)
+	}
+
+	sort := ctx.DefaultQuery("sort", "")
+	if sort == "" && req.Near != nil {
+		sort = "distance"
+	}
+
+	switch sort {
+	case "distance":
+		if req.Near == nil {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Sorting by distance needs lat and lng", http.StatusBadRequest)
+			return
+		}
+		req.OrderBy = append(req.OrderBy, entity.OrderBy{
+			Column: "distance_km",
+			Order:  "asc",
+		})
+	case "rating":
+		req.OrderBy = append(req.OrderBy, entity.OrderBy{
+			Column: "b.rating_avg",
+			Order:  "desc",
+		}, entity.OrderBy{
+			Column: "b.review_count",
+			Order:  "desc",
+		})
+	case "review_count":
+		req.OrderBy = append(req.OrderBy, entity.OrderBy{
+			Column: "b.review_count",
+			Order:  "desc",
+		})
+	case "", "created_at":
+		req.OrderBy = append(req.OrderBy, entity.OrderBy{
+			Column: "created_at",
+			Order:  "desc",
+		})
+	default:
+		h.ReturnError(ctx, config.ErrorBadRequest, "Unknown sort "+sort, http.StatusBadRequest)
+		return
+	}
+
 	businesses, err := h.UseCase.BusinessRepo.GetList(ctx, req)
 	if h.HandleDbError(ctx, err, "Error getting businesses") {
 		return
@@ -154,6 +283,217 @@ func (h *Handler) GetBusinesses(ctx *gin.Context) {
 	ctx.JSON(200, businesses)
 }
 
+// checkTimeZone makes sure postgres knows the time zone as well, a zone it can't convert to
+// would break the opening hours of every business list. Writes the error response itself.
+func (h *Handler) checkTimeZone(ctx *gin.Context, timeZone string) bool {
+	exists, err := h.UseCase.BusinessRepo.TimeZoneExists(ctx, timeZone)
+	if h.HandleDbError(ctx, err, "Error checking time zone") {
+		return false
+	}
+
+	if !exists {
+		h.ReturnError(ctx, config.ErrorBadRequest, "unknown time_zone "+timeZone, http.StatusBadRequest)
+		return false
+	}
+
+	return true
+}
+
+// checkBusinessLive makes sure the business exists and is not deleted before something is
+// attached to it. Writes the error response itself.
+func (h *Handler) checkBusinessLive(ctx *gin.Context, businessID string) bool {
+	if businessID == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "business_id is required", http.StatusBadRequest)
+		return false
+	}
+
+	_, err := h.UseCase.BusinessRepo.GetSingle(ctx, entity.BusinessSingleRequest{ID: businessID})
+	return !h.HandleDbError(ctx, err, "Error getting business")
+}
+
+// validateBusinessHours checks the time zone and the hours of a business before it is saved,
+// an empty time zone becomes UTC.
+func validateBusinessHours(business *entity.Business) error {
+	if business.TimeZone == "" {
+		business.TimeZone = "UTC"
+	}
+
+	// Local is the zone of the server, postgres doesn't know it
+	_, err := time.LoadLocation(business.TimeZone)
+	if err != nil || business.TimeZone == "Local" {
+		return fmt.Errorf("unknown time_zone %s", business.TimeZone)
+	}
+
+	for _, item := range business.HoursOfOperation {
+		if item.Weekday < 0 || item.Weekday > 6 {
+			return errors.New("weekday must be between 0 (Sunday) and 6 (Saturday)")
+		}
+
+		err = validateHoursRange(item.OpensAt, item.ClosesAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	closedDates := map[string]bool{}
+	openDates := map[string]bool{}
+	for _, item := range business.SpecialHours {
+		_, err = time.Parse("2006-01-02", item.Date)
+		if err != nil {
+			return fmt.Errorf("special hours date %q must be YYYY-MM-DD", item.Date)
+		}
+
+		if item.Closed {
+			closedDates[item.Date] = true
+			continue
+		}
+		openDates[item.Date] = true
+
+		err = validateHoursRange(item.OpensAt, item.ClosesAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	for date := range closedDates {
+		if openDates[date] {
+			return fmt.Errorf("special hours of %s are both closed and open", date)
+		}
+	}
+
+	return nil
+}
+
+// validateBusinessCategories checks the categories of a business, the first one is its primary category.
+func validateBusinessCategories(business *entity.Business) error {
+	if len(business.CategoryIDs) == 0 || len(business.CategoryIDs) > config.MaxBusinessCategories {
+		return fmt.Errorf("a business needs between 1 and %d categories", config.MaxBusinessCategories)
+	}
+
+	for i, id := range business.CategoryIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			return fmt.Errorf("invalid category id %s", id)
+		}
+		if slices.Contains(business.CategoryIDs[:i], id) {
+			return fmt.Errorf("category %s is repeated", id)
+		}
+	}
+
+	return nil
+}
+
+func validateHoursRange(opensAt, closesAt string) error {
+	for _, value := range []string{opensAt, closesAt} {
+		_, err := time.Parse("15:04", value)
+		if err != nil {
+			return fmt.Errorf("hours %q must be HH:MM", value)
+		}
+	}
+
+	return nil
+}
+
+// parseGeoRadius reads lat, lng and radius_km, nil when no point is given.
+func parseGeoRadius(ctx *gin.Context) (*entity.GeoRadius, error) {
+	lat, lng, radius := ctx.Query("lat"), ctx.Query("lng"), ctx.Query("radius_km")
+	if lat == "" && lng == "" {
+		if radius != "" {
+			return nil, errors.New("radius_km needs lat and lng")
+		}
+		return nil, nil
+	}
+
+	var (
+		near entity.GeoRadius
+		err  error
+	)
+
+	near.Latitude, err = parseCoordinate(lat, 90)
+	if err != nil {
+		return nil, fmt.Errorf("lat %w", err)
+	}
+
+	near.Longitude, err = parseCoordinate(lng, 180)
+	if err != nil {
+		return nil, fmt.Errorf("lng %w", err)
+	}
+
+	if radius != "" {
+		near.RadiusKm, err = strconv.ParseFloat(radius, 64)
+		if err != nil || near.RadiusKm <= 0 || near.RadiusKm > config.MaxSearchRadiusKm {
+			return nil, fmt.Errorf("radius_km must be between 0 and %g", config.MaxSearchRadiusKm)
+		}
+	}
+
+	return &near, nil
+}
+
+// parseGeoBox reads min_lat, min_lng, max_lat and max_lng, nil when none is given.
+func parseGeoBox(ctx *gin.Context) (*entity.GeoBox, error) {
+	values := []string{ctx.Query("min_lat"), ctx.Query("min_lng"), ctx.Query("max_lat"), ctx.Query("max_lng")}
+	if values[0] == "" && values[1] == "" && values[2] == "" && values[3] == "" {
+		return nil, nil
+	}
+
+	var (
+		box    entity.GeoBox
+		err    error
+		fields = []*float64{&box.MinLatitude, &box.MinLongitude, &box.MaxLatitude, &box.MaxLongitude}
+		names  = []string{"min_lat", "min_lng", "max_lat", "max_lng"}
+		limits = []float64{90, 180, 90, 180}
+	)
+
+	for i := range values {
+		*fields[i], err = parseCoordinate(values[i], limits[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s %w", names[i], err)
+		}
+	}
+
+	if box.MinLatitude > box.MaxLatitude {
+		return nil, errors.New("min_lat must not be greater than max_lat")
+	}
+
+	return &box, nil
+}
+
+func validPriceRange(priceRange string) bool {
+	return priceRange == "" || (len(priceRange) <= 4 && strings.Trim(priceRange, "$") == "")
+}
+
+// parsePriceRanges reads a list like "$,$$" or "1,2" into price range levels.
+func parsePriceRanges(value string) ([]int, error) {
+	var ranges []int
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		level, err := strconv.Atoi(item)
+		if err != nil && validPriceRange(item) {
+			level, err = len(item), nil
+		}
+		if err != nil || level < 1 || level > 4 {
+			return nil, fmt.Errorf("unknown price_range %s", item)
+		}
+
+		ranges = append(ranges, level)
+	}
+
+	return ranges, nil
+}
+
+func parseCoordinate(value string, limit float64) (float64, error) {
+	coordinate, err := strconv.ParseFloat(value, 64)
+	if err != nil || coordinate < -limit || coordinate > limit {
+		return 0, fmt.Errorf("must be a number between %g and %g", -limit, limit)
+	}
+
+	return coordinate, nil
+}
+
 // UpdateBusiness godoc
 // @Router /business [put]
 // @Summary Update a business
@@ -176,11 +516,56 @@ func (h *Handler) UpdateBusiness(ctx *gin.Context) {
 		return
 	}
 
-	if ctx.GetHeader("sub") != body.OwnerID || ctx.GetHeader("user_type") != "admin" {
+	current, err := h.UseCase.BusinessRepo.GetSingle(ctx, entity.BusinessSingleRequest{ID: body.ID})
+	if h.HandleDbError(ctx, err, "Error getting business") {
+		return
+	}
+
+	if current.OwnerID != GetUserID(ctx) && GetUserType(ctx) != "admin" {
 		h.ReturnError(ctx, config.ErrorForbidden, "Access denied, only owner or admin can update business", 403)
 		return
 	}
 
+	// ownership only changes through an approved claim
+	body.OwnerID = current.OwnerID
+	body.ClaimState = current.ClaimState
+	body.VerifiedAt = current.VerifiedAt
+	body.CreatedBy = current.CreatedBy
+
+	err = validateBusinessHours(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !h.checkTimeZone(ctx, body.TimeZone) {
+		return
+	}
+
+	if !validPriceRange(body.PriceRange) {
+		h.ReturnError(ctx, config.ErrorBadRequest, "price_range must be one of $, $$, $$$ or $$$$", http.StatusBadRequest)
+		return
+	}
+
+	err = validateBusinessCategories(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if len(body.Attributes) > 0 {
+		attributes, err := h.UseCase.BusinessAttributeRepo.GetByCategories(ctx, body.CategoryIDs)
+		if h.HandleDbError(ctx, err, "Error getting category attributes") {
+			return
+		}
+
+		err = validateAttributeValues(attributes, body.Attributes)
+		if err != nil {
+			h.ReturnError(ctx, config.ErrorBadRequest, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
 	business, err := h.UseCase.BusinessRepo.Update(ctx, body)
 	if h.HandleDbError(ctx, err, "Error updating business") {
 		return
@@ -188,6 +573,7 @@ func (h *Handler) UpdateBusiness(ctx *gin.Context) {
 
 	business.Attachments, err = h.UseCase.BusinessAttachmentRepo.MultipleUpsert(ctx, entity.BusinessAttachmentMultipleInsertRequest{
 		BusinessId:  business.ID,
+		UploadedBy:  GetUserID(ctx),
 		Attachments: body.Attachments,
 	})
 	if h.HandleDbError(ctx, err, "Error upserting business attachments") {
@@ -200,7 +586,7 @@ func (h *Handler) UpdateBusiness(ctx *gin.Context) {
 // DeleteBusiness godoc
 // @Router /business/{id} [delete]
 // @Summary Delete a business
-// @Description Delete a business
+// @Description Delete a business, it is hidden with its reviews, events, bookmarks and reports until an admin restores it or the retention period ends
 // @Security BearerAuth
 // @Tags business
 // @Accept  json
@@ -221,7 +607,7 @@ func (h *Handler) DeleteBusiness(ctx *gin.Context) {
 		return
 	}
 
-	if res.OwnerID != ctx.GetHeader("sub") && ctx.GetHeader("user_type") != "admin" {
+	if res.OwnerID != GetUserID(ctx) && GetUserType(ctx) != "admin" {
 		h.ReturnError(ctx, config.ErrorForbidden, "Access denied, only owner or admin can delete business", 403)
 		return
 	}
@@ -236,6 +622,36 @@ func (h *Handler) DeleteBusiness(ctx *gin.Context) {
 	})
 }
 
+// RestoreBusiness godoc
+// @Router /business/{id}/restore [post]
+// @Summary Restore a deleted business
+// @Description Brings back a deleted business with everything that belongs to it, as long as it is not purged yet
+// @Security BearerAuth
+// @Tags business
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Business ID"
+// @Success 200 {object} entity.Business
+// @Failure 404 {object} entity.ErrorResponse
+func (h *Handler) RestoreBusiness(ctx *gin.Context) {
+	if GetUserType(ctx) != "admin" {
+		h.ReturnError(ctx, config.ErrorForbidden, "Access denied, only admin can restore business", 403)
+		return
+	}
+
+	err := h.UseCase.BusinessRepo.Restore(ctx, entity.Id{ID: ctx.Param("id")})
+	if h.HandleDbError(ctx, err, "Error restoring business") {
+		return
+	}
+
+	business, err := h.UseCase.BusinessRepo.GetSingle(ctx, entity.BusinessSingleRequest{ID: ctx.Param("id")})
+	if h.HandleDbError(ctx, err, "Error getting business") {
+		return
+	}
+
+	ctx.JSON(200, business)
+}
+
 // UploadBusinessPic godoc
 // @ID upload_business_pic_file
 // @Router /business/upload/{id} [post]
@@ -257,6 +673,15 @@ func (h *Handler) UploadBusinessPic(ctx *gin.Context) {
 
 	log.Print(param_id)
 
+	attachment, err := h.UseCase.BusinessAttachmentRepo.GetSingle(ctx, entity.Id{ID: param_id})
+	if h.HandleDbError(ctx, err, "Error getting business attachment") {
+		return
+	}
+
+	if !h.canManageBusiness(ctx, attachment.BusinessId) {
+		return
+	}
+
 	form, err := ctx.MultipartForm()
 	if err != nil {
 		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid file upload request", 400)
@@ -270,10 +695,11 @@ func (h *Handler) UploadBusinessPic(ctx *gin.Context) {
 
 	log.Print(resp.Url[0].Url)
 	_, err = h.UseCase.BusinessAttachmentRepo.Update(ctx, entity.BusinessAttachment{
-		Id:        param_id,
-		FilePath:  resp.Url[0].Url,
-		CreatedAt: time.Now().Format(time.RFC3339),
-		UpdatedAt: time.Now().Format(time.RFC3339),
+		Id:         param_id,
+		FilePath:   resp.Url[0].Url,
+		UploadedBy: GetUserID(ctx),
+		CreatedAt:  time.Now().Format(time.RFC3339),
+		UpdatedAt:  time.Now().Format(time.RFC3339),
 	})
 
 	if h.HandleDbError(ctx, err, "Error updating business attachments") {
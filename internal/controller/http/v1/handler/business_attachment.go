@@ -0,0 +1,344 @@
+package handler
+
+import (
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/Akorm0181/yelp/pkg/firebase"
+	"github.com/gin-gonic/gin"
+)
+
+// galleryFilter lists every attachment of a business in gallery order.
+func galleryFilter(businessID string) entity.GetListFilter {
+	return entity.GetListFilter{
+		Page:  1,
+		Limit: config.MaxGalleryAttachments,
+		Filters: []entity.Filter{
+			{
+				Column: "business_id",
+				Type:   "eq",
+				Value:  businessID,
+			},
+		},
+		OrderBy: []entity.OrderBy{
+			{Column: "position", Order: "ASC"},
+			{Column: "created_at", Order: "ASC"},
+		},
+	}
+}
+
+// canManageBusiness writes the error response and returns false unless the current user
+// owns the business or is an admin.
+func (h *Handler) canManageBusiness(ctx *gin.Context, businessID string) bool {
+	business, err := h.UseCase.BusinessRepo.GetSingle(ctx, entity.BusinessSingleRequest{ID: businessID})
+	if h.HandleDbError(ctx, err, "Error getting business") {
+		return false
+	}
+
+	if business.OwnerID != GetUserID(ctx) && GetUserType(ctx) != "admin" {
+		h.ReturnError(ctx, config.ErrorForbidden, "Access denied, only owner or admin can manage the business", http.StatusForbidden)
+		return false
+	}
+
+	return true
+}
+
+// galleryAttachment returns the attachment from the url, it has to belong to the business in the url.
+func (h *Handler) galleryAttachment(ctx *gin.Context) (entity.BusinessAttachment, bool) {
+	attachment, err := h.UseCase.BusinessAttachmentRepo.GetSingle(ctx, entity.Id{ID: ctx.Param("attachment_id")})
+	if h.HandleDbError(ctx, err, "Error getting business attachment") {
+		return entity.BusinessAttachment{}, false
+	}
+
+	if attachment.BusinessId != ctx.Param("id") {
+		h.ReturnError(ctx, config.ErrorNotFound, "Business attachment not found", http.StatusNotFound)
+		return entity.BusinessAttachment{}, false
+	}
+
+	return attachment, true
+}
+
+func attachmentContentType(file *multipart.FileHeader) string {
+	contentType := file.Header.Get("Content-Type")
+
+	switch {
+	case strings.HasPrefix(contentType, "image/"):
+		return "image"
+	case strings.HasPrefix(contentType, "video/"):
+		return "video"
+	default:
+		return ""
+	}
+}
+
+// UploadBusinessAttachments godoc
+// @Router /business/{id}/attachment [post]
+// @Summary Add photos to a business
+// @Description Uploads images and videos to the end of the business gallery. The first image becomes the cover if the business has none.
+// @Security BearerAuth
+// @Tags business
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Business ID"
+// @Param file formData []file true "Images or videos"
+// @Param caption formData string false "Caption for the uploaded files"
+// @Success 200 {object} entity.BusinessAttachmentList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) UploadBusinessAttachments(ctx *gin.Context) {
+	businessID := ctx.Param("id")
+
+	if !h.canManageBusiness(ctx, businessID) {
+		return
+	}
+
+	form, err := ctx.MultipartForm()
+	if err != nil || len(form.File["file"]) == 0 {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid file upload request", http.StatusBadRequest)
+		return
+	}
+
+	contentTypes := make([]string, 0, len(form.File["file"]))
+	for _, file := range form.File["file"] {
+		contentType := attachmentContentType(file)
+		if contentType == "" {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Only images and videos can be added to the gallery", http.StatusBadRequest)
+			return
+		}
+		contentTypes = append(contentTypes, contentType)
+	}
+
+	gallery, err := h.UseCase.BusinessAttachmentRepo.GetList(ctx, galleryFilter(businessID))
+	if h.HandleDbError(ctx, err, "Error getting business attachments") {
+		return
+	}
+
+	if int(gallery.Count)+len(contentTypes) > config.MaxGalleryAttachments {
+		h.ReturnError(ctx, config.ErrorBadRequest, fmt.Sprintf("A business can have at most %d photos and videos", config.MaxGalleryAttachments), http.StatusBadRequest)
+		return
+	}
+
+	hasCover := false
+	for _, attachment := range gallery.Items {
+		hasCover = hasCover || attachment.IsCover
+	}
+
+	resp, err := firebase.UploadFiles(form)
+	if err != nil {
+		h.Logger.Error(err, "Error uploading business attachments")
+		h.ReturnError(ctx, config.ErrorInternalServer, "Error uploading files", http.StatusInternalServerError)
+		return
+	}
+
+	for i, url := range resp.Url {
+		attachment, err := h.UseCase.BusinessAttachmentRepo.Create(ctx, entity.BusinessAttachment{
+			BusinessId:  businessID,
+			FilePath:    url.Url,
+			ContentType: contentTypes[i],
+			Caption:     ctx.PostForm("caption"),
+			UploadedBy:  GetUserID(ctx),
+		})
+		if h.HandleDbError(ctx, err, "Error saving business attachment") {
+			return
+		}
+
+		if !hasCover && attachment.ContentType == "image" {
+			err = h.UseCase.BusinessAttachmentRepo.SetCover(ctx, businessID, attachment.Id)
+			if h.HandleDbError(ctx, err, "Error setting business cover") {
+				return
+			}
+			hasCover = true
+		}
+	}
+
+	gallery, err = h.UseCase.BusinessAttachmentRepo.GetList(ctx, galleryFilter(businessID))
+	if h.HandleDbError(ctx, err, "Error getting business attachments") {
+		return
+	}
+
+	ctx.JSON(200, gallery)
+}
+
+// ReorderBusinessAttachments godoc
+// @Router /business/{id}/attachment/order [put]
+// @Summary Reorder business photos
+// @Description Sets the gallery order, ids must list every attachment of the business exactly once
+// @Security BearerAuth
+// @Tags business
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Business ID"
+// @Param body body entity.BusinessAttachmentOrderRequest true "Attachment ids in the new order"
+// @Success 200 {object} entity.BusinessAttachmentList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) ReorderBusinessAttachments(ctx *gin.Context) {
+	var (
+		body       entity.BusinessAttachmentOrderRequest
+		businessID = ctx.Param("id")
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if !h.canManageBusiness(ctx, businessID) {
+		return
+	}
+
+	gallery, err := h.UseCase.BusinessAttachmentRepo.GetList(ctx, galleryFilter(businessID))
+	if h.HandleDbError(ctx, err, "Error getting business attachments") {
+		return
+	}
+
+	remaining := make(map[string]bool, len(gallery.Items))
+	for _, attachment := range gallery.Items {
+		remaining[attachment.Id] = true
+	}
+
+	for _, id := range body.Ids {
+		if !remaining[id] {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Unknown or repeated attachment id "+id, http.StatusBadRequest)
+			return
+		}
+		delete(remaining, id)
+	}
+
+	if len(remaining) != 0 {
+		h.ReturnError(ctx, config.ErrorBadRequest, "ids must contain every attachment of the business", http.StatusBadRequest)
+		return
+	}
+
+	err = h.UseCase.BusinessAttachmentRepo.Reorder(ctx, businessID, body.Ids)
+	if h.HandleDbError(ctx, err, "Error reordering business attachments") {
+		return
+	}
+
+	gallery, err = h.UseCase.BusinessAttachmentRepo.GetList(ctx, galleryFilter(businessID))
+	if h.HandleDbError(ctx, err, "Error getting business attachments") {
+		return
+	}
+
+	ctx.JSON(200, gallery)
+}
+
+// SetBusinessCover godoc
+// @Router /business/{id}/attachment/{attachment_id}/cover [put]
+// @Summary Set the business cover photo
+// @Description Makes an image the cover of the business, replacing the previous cover
+// @Security BearerAuth
+// @Tags business
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Business ID"
+// @Param attachment_id path string true "Attachment ID"
+// @Success 200 {object} entity.BusinessAttachment
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) SetBusinessCover(ctx *gin.Context) {
+	if !h.canManageBusiness(ctx, ctx.Param("id")) {
+		return
+	}
+
+	attachment, ok := h.galleryAttachment(ctx)
+	if !ok {
+		return
+	}
+
+	if attachment.ContentType != "image" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Only an image can be the cover", http.StatusBadRequest)
+		return
+	}
+
+	err := h.UseCase.BusinessAttachmentRepo.SetCover(ctx, attachment.BusinessId, attachment.Id)
+	if h.HandleDbError(ctx, err, "Error setting business cover") {
+		return
+	}
+	attachment.IsCover = true
+
+	ctx.JSON(200, attachment)
+}
+
+// UpdateBusinessAttachment godoc
+// @Router /business/{id}/attachment/{attachment_id} [put]
+// @Summary Update the caption of a business photo
+// @Description An empty caption removes it
+// @Security BearerAuth
+// @Tags business
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Business ID"
+// @Param attachment_id path string true "Attachment ID"
+// @Param body body entity.BusinessAttachmentCaptionRequest true "Caption"
+// @Success 200 {object} entity.BusinessAttachment
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) UpdateBusinessAttachment(ctx *gin.Context) {
+	var (
+		body entity.BusinessAttachmentCaptionRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || len(body.Caption) > 500 {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body, caption is at most 500 characters", http.StatusBadRequest)
+		return
+	}
+
+	if !h.canManageBusiness(ctx, ctx.Param("id")) {
+		return
+	}
+
+	attachment, err := h.UseCase.BusinessAttachmentRepo.UpdateCaption(ctx, entity.BusinessAttachment{
+		Id:         ctx.Param("attachment_id"),
+		BusinessId: ctx.Param("id"),
+		Caption:    strings.TrimSpace(body.Caption),
+	})
+	if h.HandleDbError(ctx, err, "Error updating business attachment") {
+		return
+	}
+
+	ctx.JSON(200, attachment)
+}
+
+// DeleteBusinessAttachment godoc
+// @Router /business/{id}/attachment/{attachment_id} [delete]
+// @Summary Delete a business photo
+// @Description Removes the attachment from the gallery and its file from storage
+// @Security BearerAuth
+// @Tags business
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Business ID"
+// @Param attachment_id path string true "Attachment ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) DeleteBusinessAttachment(ctx *gin.Context) {
+	if !h.canManageBusiness(ctx, ctx.Param("id")) {
+		return
+	}
+
+	attachment, ok := h.galleryAttachment(ctx)
+	if !ok {
+		return
+	}
+
+	err := h.UseCase.BusinessAttachmentRepo.Delete(ctx, entity.Id{ID: attachment.Id})
+	if h.HandleDbError(ctx, err, "Error deleting business attachment") {
+		return
+	}
+
+	// the row is gone already, a file left behind in storage is only logged
+	objectName, err := firebase.ObjectName(attachment.FilePath)
+	if err == nil {
+		err = firebase.DeleteFile(objectName)
+	}
+	if err != nil {
+		h.Logger.Error(err, "Error deleting business attachment file")
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Business attachment deleted successfully",
+	})
+}
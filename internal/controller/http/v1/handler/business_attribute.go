@@ -0,0 +1,333 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var attributeKey = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// validateAttributeOptions checks the name and options of an attribute, bool attributes
+// get no options.
+func validateAttributeOptions(attribute *entity.BusinessAttribute) error {
+	attribute.Name = strings.TrimSpace(attribute.Name)
+	if attribute.Name == "" || len(attribute.Name) > 255 {
+		return errors.New("name is required and at most 255 characters")
+	}
+
+	switch attribute.ValueType {
+	case "bool":
+		attribute.Options = []string{}
+	case "enum":
+		var options []string
+		for _, option := range attribute.Options {
+			option = strings.TrimSpace(option)
+			if option == "" || len(option) > 64 || slices.Contains(options, option) {
+				return fmt.Errorf("invalid or repeated option %q", option)
+			}
+			options = append(options, option)
+		}
+		if len(options) == 0 {
+			return errors.New("an enum attribute needs options")
+		}
+		attribute.Options = options
+	default:
+		return errors.New("value_type must be bool or enum")
+	}
+
+	return nil
+}
+
+// validateAttributeValues checks the attribute values of a business against the attributes
+// of its category.
+func validateAttributeValues(attributes []entity.BusinessAttribute, values map[string]string) error {
+	for key, value := range values {
+		i := slices.IndexFunc(attributes, func(attribute entity.BusinessAttribute) bool {
+			return attribute.Key == key
+		})
+		if i < 0 {
+			return fmt.Errorf("attribute %s is not available for the category of the business", key)
+		}
+
+		switch attributes[i].ValueType {
+		case "bool":
+			if value != "true" && value != "false" {
+				return fmt.Errorf("attribute %s must be true or false", key)
+			}
+		case "enum":
+			if !slices.Contains(attributes[i].Options, value) {
+				return fmt.Errorf("attribute %s must be one of %s", key, strings.Join(attributes[i].Options, ", "))
+			}
+		}
+	}
+
+	return nil
+}
+
+// CreateBusinessAttribute godoc
+// @Router       /business-attribute [post]
+// @Summary      Create a business attribute
+// @Description  Create an amenity like wifi or parking, assign it to categories to use it
+// @Security     BearerAuth
+// @Tags         business-attribute
+// @Accept       json
+// @Produce      json
+// @Param        business_attribute body entity.BusinessAttribute true "BusinessAttribute object"
+// @Success      201 {object} entity.BusinessAttribute
+// @Failure      400 {object} entity.ErrorResponse
+func (h *Handler) CreateBusinessAttribute(ctx *gin.Context) {
+	var (
+		body entity.BusinessAttribute
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if GetUserType(ctx) != "admin" {
+		h.ReturnError(ctx, config.ErrorForbidden, "Access denied, only admin can create business-attribute", 403)
+		return
+	}
+
+	if !attributeKey.MatchString(body.Key) {
+		h.ReturnError(ctx, config.ErrorBadRequest, "key must be lowercase letters, digits and underscores", http.StatusBadRequest)
+		return
+	}
+
+	err = validateAttributeOptions(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	attribute, err := h.UseCase.BusinessAttributeRepo.Create(ctx, body)
+	if h.HandleDbError(ctx, err, "Error creating business-attribute") {
+		return
+	}
+
+	ctx.JSON(201, attribute)
+}
+
+// GetBusinessAttribute godoc
+// @Router       /business-attribute/{id} [get]
+// @Summary      Get a business attribute by ID
+// @Description  Get a business attribute by ID
+// @Security     BearerAuth
+// @Tags         business-attribute
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "BusinessAttribute ID"
+// @Success      200 {object} entity.BusinessAttribute
+// @Failure      400 {object} entity.ErrorResponse
+func (h *Handler) GetBusinessAttribute(ctx *gin.Context) {
+	attribute, err := h.UseCase.BusinessAttributeRepo.GetSingle(ctx, entity.Id{ID: ctx.Param("id")})
+	if h.HandleDbError(ctx, err, "Error getting business-attribute") {
+		return
+	}
+
+	ctx.JSON(200, attribute)
+}
+
+// GetBusinessAttributes godoc
+// @Router       /business-attribute/list [get]
+// @Summary      Get a list of business attributes
+// @Description  Get a list of business attributes
+// @Security     BearerAuth
+// @Tags         business-attribute
+// @Accept       json
+// @Produce      json
+// @Param        page query number true "page"
+// @Param        limit query number true "limit"
+// @Param        search query string false "search"
+// @Success      200 {object} entity.BusinessAttributeList
+// @Failure      400 {object} entity.ErrorResponse
+func (h *Handler) GetBusinessAttributes(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	search := ctx.DefaultQuery("search", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+	req.Filters = append(req.Filters,
+		entity.Filter{
+			Column: "name",
+			Type:   "search",
+			Value:  search,
+		},
+		entity.Filter{
+			Column: "key",
+			Type:   "search",
+			Value:  search,
+		},
+	)
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "name",
+		Order:  "asc",
+	})
+
+	attributes, err := h.UseCase.BusinessAttributeRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting business-attributes") {
+		return
+	}
+
+	ctx.JSON(200, attributes)
+}
+
+// UpdateBusinessAttribute godoc
+// @Router       /business-attribute [put]
+// @Summary      Update a business attribute
+// @Description  Changes the name and options. Businesses lose values that are no longer an option, key and value_type stay as they are.
+// @Security     BearerAuth
+// @Tags         business-attribute
+// @Accept       json
+// @Produce      json
+// @Param        business_attribute body entity.BusinessAttribute true "BusinessAttribute object"
+// @Success      200 {object} entity.BusinessAttribute
+// @Failure      400 {object} entity.ErrorResponse
+func (h *Handler) UpdateBusinessAttribute(ctx *gin.Context) {
+	var (
+		body entity.BusinessAttribute
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if GetUserType(ctx) != "admin" {
+		h.ReturnError(ctx, config.ErrorForbidden, "Access denied, only admin can update business-attribute", 403)
+		return
+	}
+
+	current, err := h.UseCase.BusinessAttributeRepo.GetSingle(ctx, entity.Id{ID: body.ID})
+	if h.HandleDbError(ctx, err, "Error getting business-attribute") {
+		return
+	}
+
+	body.ValueType = current.ValueType
+	err = validateAttributeOptions(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	attribute, err := h.UseCase.BusinessAttributeRepo.Update(ctx, body)
+	if h.HandleDbError(ctx, err, "Error updating business-attribute") {
+		return
+	}
+
+	ctx.JSON(200, attribute)
+}
+
+// DeleteBusinessAttribute godoc
+// @Router       /business-attribute/{id} [delete]
+// @Summary      Delete a business attribute
+// @Description  Deletes the attribute together with its values on every business
+// @Security     BearerAuth
+// @Tags         business-attribute
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "BusinessAttribute ID"
+// @Success      200 {object} entity.SuccessResponse
+// @Failure      400 {object} entity.ErrorResponse
+func (h *Handler) DeleteBusinessAttribute(ctx *gin.Context) {
+	if GetUserType(ctx) != "admin" {
+		h.ReturnError(ctx, config.ErrorForbidden, "Access denied, only admin can delete business-attribute", 403)
+		return
+	}
+
+	err := h.UseCase.BusinessAttributeRepo.Delete(ctx, entity.Id{ID: ctx.Param("id")})
+	if h.HandleDbError(ctx, err, "Error deleting business-attribute") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "BusinessAttribute deleted successfully",
+	})
+}
+
+// GetCategoryAttributes godoc
+// @Router       /business-category/{id}/attributes [get]
+// @Summary      Get the attributes of a category
+// @Description  The attributes businesses of the category can have
+// @Security     BearerAuth
+// @Tags         business-category
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "BusinessCategory ID"
+// @Success      200 {object} []entity.BusinessAttribute
+// @Failure      400 {object} entity.ErrorResponse
+func (h *Handler) GetCategoryAttributes(ctx *gin.Context) {
+	attributes, err := h.UseCase.BusinessAttributeRepo.GetByCategories(ctx, []string{ctx.Param("id")})
+	if h.HandleDbError(ctx, err, "Error getting category attributes") {
+		return
+	}
+
+	ctx.JSON(200, attributes)
+}
+
+// SetCategoryAttributes godoc
+// @Router       /business-category/{id}/attributes [put]
+// @Summary      Set the attributes of a category
+// @Description  Replaces the attributes businesses of the category can have
+// @Security     BearerAuth
+// @Tags         business-category
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "BusinessCategory ID"
+// @Param        body body entity.CategoryAttributesRequest true "Attribute ids"
+// @Success      200 {object} []entity.BusinessAttribute
+// @Failure      400 {object} entity.ErrorResponse
+func (h *Handler) SetCategoryAttributes(ctx *gin.Context) {
+	var (
+		body entity.CategoryAttributesRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if GetUserType(ctx) != "admin" {
+		h.ReturnError(ctx, config.ErrorForbidden, "Access denied, only admin can change category attributes", 403)
+		return
+	}
+
+	for _, id := range body.AttributeIDs {
+		if _, err = uuid.Parse(id); err != nil {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Invalid attribute id "+id, http.StatusBadRequest)
+			return
+		}
+	}
+
+	_, err = h.UseCase.BusinessCategoryRepo.GetSingle(ctx, entity.BusinessCategorySingleRequest{ID: ctx.Param("id")})
+	if h.HandleDbError(ctx, err, "Error getting business-category") {
+		return
+	}
+
+	attributes, err := h.UseCase.BusinessAttributeRepo.SetCategoryAttributes(ctx, ctx.Param("id"), body.AttributeIDs)
+	if h.HandleDbError(ctx, err, "Error setting category attributes") {
+		return
+	}
+
+	ctx.JSON(200, attributes)
+}
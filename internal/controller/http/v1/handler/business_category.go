@@ -1,17 +1,63 @@
 package handler
 
 import (
+	"errors"
+	"net/http"
+	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/Akorm0181/yelp/config"
 	"github.com/Akorm0181/yelp/internal/entity"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
+var (
+	categorySlug    = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	nonSlugCharater = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// validateCategory checks a category before it is saved, an empty slug is made from the name.
+func validateCategory(category *entity.BusinessCategory) error {
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" || len(category.Name) > 100 {
+		return errors.New("name is required and at most 100 characters")
+	}
+
+	if category.Slug == "" {
+		category.Slug = strings.Trim(nonSlugCharater.ReplaceAllString(strings.ToLower(category.Name), "-"), "-")
+	}
+	if !categorySlug.MatchString(category.Slug) || len(category.Slug) > 120 {
+		return errors.New("slug must be lowercase letters and digits separated by dashes")
+	}
+
+	if len(category.Icon) > 255 {
+		return errors.New("icon is at most 255 characters")
+	}
+
+	if category.ParentID != "" {
+		if _, err := uuid.Parse(category.ParentID); err != nil {
+			return errors.New("invalid parent_id")
+		}
+	}
+
+	return nil
+}
+
+// categoryFromParam finds the category of an id or a slug.
+func (h *Handler) categoryFromParam(ctx *gin.Context, value string) (entity.BusinessCategory, error) {
+	if _, err := uuid.Parse(value); err == nil {
+		return h.UseCase.BusinessCategoryRepo.GetSingle(ctx, entity.BusinessCategorySingleRequest{ID: value})
+	}
+
+	return h.UseCase.BusinessCategoryRepo.GetSingle(ctx, entity.BusinessCategorySingleRequest{Slug: value})
+}
+
 // CreateBusinessCategory godoc
 // @Router       /business-category [post]
 // @Summary      Create a new business-category
-// @Description  Create a new business-category
+// @Description  Create a new business-category, parent_id puts it below another category. The slug is made from the name when it is empty.
 // @Security     BearerAuth
 // @Tags         business-category
 // @Accept       json
@@ -30,11 +76,17 @@ func (h *Handler) CreateBusinessCategory(ctx *gin.Context) {
 		return
 	}
 
-	if ctx.GetHeader("user_type") != "admin" {
+	if GetUserType(ctx) != "admin" {
 		h.ReturnError(ctx, config.ErrorForbidden, "Access denied, only admin can create business-category", 403)
 		return
 	}
 
+	err = validateCategory(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	businessCategory, err := h.UseCase.BusinessCategoryRepo.Create(ctx, body)
 	if h.HandleDbError(ctx, err, "Error creating business-category") {
 		return
@@ -46,28 +98,25 @@ func (h *Handler) CreateBusinessCategory(ctx *gin.Context) {
 // GetBusinessCategory godoc
 // @Router       /business-category/{id} [get]
 // @Summary      Get a business-category by ID
-// @Description  Get a business-category by ID
+// @Description  Get a business-category by ID or slug together with the categories above it
 // @Security     BearerAuth
 // @Tags         business-category
 // @Accept       json
 // @Produce      json
-// @Param        id path string true "BusinessCategory ID"
-// @Param        category_id path string true "Category ID"
+// @Param        id path string true "BusinessCategory ID or slug"
 // @Success      200 {object} entity.BusinessCategory
 // @Failure      400 {object} entity.ErrorResponse
 func (h *Handler) GetBusinessCategory(ctx *gin.Context) {
-	var (
-		req entity.BusinessCategorySingleRequest
-	)
-
-	req.ID = ctx.Param("id")
-	req.Name = ctx.Param("name")
-
-	businessCategory, err := h.UseCase.BusinessCategoryRepo.GetSingle(ctx, req)
+	businessCategory, err := h.categoryFromParam(ctx, ctx.Param("id"))
 	if h.HandleDbError(ctx, err, "Error getting business-category") {
 		return
 	}
 
+	businessCategory.Ancestors, err = h.UseCase.BusinessCategoryRepo.GetAncestors(ctx, entity.Id{ID: businessCategory.ID})
+	if h.HandleDbError(ctx, err, "Error getting business-category ancestors") {
+		return
+	}
+
 	ctx.JSON(200, businessCategory)
 }
 
@@ -82,6 +131,7 @@ func (h *Handler) GetBusinessCategory(ctx *gin.Context) {
 // @Param        page query number true "page"
 // @Param        limit query number true "limit"
 // @Param        search query string false "search"
+// @Param        parent_id query string false "only the children of this category, root for the top level"
 // @Success      200 {object} entity.BusinessCategoryList
 // @Failure      400 {object} entity.ErrorResponse
 func (h *Handler) GetBusinessCategories(ctx *gin.Context) {
@@ -103,6 +153,25 @@ func (h *Handler) GetBusinessCategories(ctx *gin.Context) {
 		},
 	)
 
+	switch parentID := ctx.Query("parent_id"); parentID {
+	case "":
+	case "root":
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "parent_id",
+			Type:   "isnull",
+		})
+	default:
+		if _, err := uuid.Parse(parentID); err != nil {
+			h.ReturnError(ctx, config.ErrorBadRequest, "Invalid parent_id", http.StatusBadRequest)
+			return
+		}
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "parent_id",
+			Type:   "eq",
+			Value:  parentID,
+		})
+	}
+
 	req.OrderBy = append(req.OrderBy, entity.OrderBy{
 		Column: "created_at",
 		Order:  "desc",
@@ -116,10 +185,29 @@ func (h *Handler) GetBusinessCategories(ctx *gin.Context) {
 	ctx.JSON(200, users)
 }
 
+// GetBusinessCategoryTree godoc
+// @Router       /business-category/tree [get]
+// @Summary      Get the category tree
+// @Description  Every top level category with the categories below it as children
+// @Security     BearerAuth
+// @Tags         business-category
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} []entity.BusinessCategory
+// @Failure      400 {object} entity.ErrorResponse
+func (h *Handler) GetBusinessCategoryTree(ctx *gin.Context) {
+	tree, err := h.UseCase.BusinessCategoryRepo.GetTree(ctx)
+	if h.HandleDbError(ctx, err, "Error getting business-category tree") {
+		return
+	}
+
+	ctx.JSON(200, tree)
+}
+
 // UpdateBusinessCategory godoc
 // @Router       /business-category [put]
 // @Summary      Update a business-category
-// @Description  Update a business-category
+// @Description  Update a business-category, a category can't be moved below itself
 // @Security     BearerAuth
 // @Tags         business-category
 // @Accept       json
@@ -138,12 +226,22 @@ func (h *Handler) UpdateBusinessCategory(ctx *gin.Context) {
 		return
 	}
 
-	if ctx.GetHeader("user_type") != "admin" {
+	if GetUserType(ctx) != "admin" {
 		h.ReturnError(ctx, config.ErrorForbidden, "Access denied, only admin can update business-category", 403)
 		return
 	}
 
+	err = validateCategory(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	businessCategory, err := h.UseCase.BusinessCategoryRepo.Update(ctx, body)
+	if errors.Is(err, entity.ErrCategoryCycle) {
+		h.ReturnError(ctx, config.ErrorBadRequest, "A category can't be moved below itself", http.StatusBadRequest)
+		return
+	}
 	if h.HandleDbError(ctx, err, "Error updating business-category") {
 		return
 	}
@@ -154,7 +252,7 @@ func (h *Handler) UpdateBusinessCategory(ctx *gin.Context) {
 // DeleteBusinessCategory godoc
 // @Router       /business-category/{id} [delete]
 // @Summary      Delete a business-category
-// @Description  Delete a business-category
+// @Description  Delete a business-category, categories that still have children can't be deleted
 // @Security     BearerAuth
 // @Tags         business-category
 // @Accept       json
@@ -169,7 +267,7 @@ func (h *Handler) DeleteBusinessCategory(ctx *gin.Context) {
 
 	req.ID = ctx.Param("id")
 
-	if ctx.GetHeader("user_type") != "admin" {
+	if GetUserType(ctx) != "admin" {
 		h.ReturnError(ctx, config.ErrorForbidden, "Access denied, only admin can delete business-category", 403)
 		return
 	}
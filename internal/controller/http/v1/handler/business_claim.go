@@ -0,0 +1,287 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/Akorm0181/yelp/pkg/firebase"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+)
+
+const maxClaimEvidenceFiles = 10
+
+// CreateBusinessClaim godoc
+// @Router /business-claim [post]
+// @Summary Claim a business
+// @Description Asks an admin to hand the business over to you as its verified owner. Owners of a claimed business use it to get verified. Upload evidence to the claim afterwards.
+// @Security BearerAuth
+// @Tags business-claim
+// @Accept  json
+// @Produce  json
+// @Param body body entity.CreateBusinessClaim true "Claim"
+// @Success 201 {object} entity.BusinessClaim
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateBusinessClaim(ctx *gin.Context) {
+	var (
+		body entity.CreateBusinessClaim
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || body.BusinessID == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	business, err := h.UseCase.BusinessRepo.GetSingle(ctx, entity.BusinessSingleRequest{ID: body.BusinessID})
+	if h.HandleDbError(ctx, err, "Error getting business") {
+		return
+	}
+
+	if business.OwnerID == GetUserID(ctx) && business.ClaimState == "verified" {
+		h.ReturnError(ctx, config.ErrorConflict, "You are already the verified owner of this business", http.StatusBadRequest)
+		return
+	}
+
+	claim, err := h.UseCase.BusinessClaimRepo.Create(ctx, entity.BusinessClaim{
+		BusinessID: business.ID,
+		UserID:     GetUserID(ctx),
+		Phone:      body.Phone,
+		Note:       body.Note,
+	})
+	if h.HandleDbError(ctx, err, "Error creating business claim") {
+		return
+	}
+	claim.Evidence = []entity.BusinessClaimEvidence{}
+
+	ctx.JSON(201, claim)
+}
+
+// UploadBusinessClaimEvidence godoc
+// @Router /business-claim/{id}/evidence [post]
+// @Summary Upload evidence for a claim
+// @Description Documents that show you own the business, e.g. a business license or a utility bill
+// @Security BearerAuth
+// @Tags business-claim
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Claim ID"
+// @Param file formData []file true "Files to upload"
+// @Success 200 {object} entity.BusinessClaim
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) UploadBusinessClaimEvidence(ctx *gin.Context) {
+	claim, err := h.UseCase.BusinessClaimRepo.GetSingle(ctx, entity.Id{ID: ctx.Param("id")})
+	if h.HandleDbError(ctx, err, "Error getting business claim") {
+		return
+	}
+
+	if claim.UserID != GetUserID(ctx) {
+		h.ReturnError(ctx, config.ErrorNotFound, "Business claim not found", http.StatusNotFound)
+		return
+	}
+
+	if claim.Status != "pending" {
+		h.ReturnError(ctx, config.ErrorConflict, "Business claim was already reviewed", http.StatusBadRequest)
+		return
+	}
+
+	form, err := ctx.MultipartForm()
+	if err != nil || len(form.File["file"]) == 0 || len(form.File["file"]) > maxClaimEvidenceFiles {
+		h.ReturnError(ctx, config.ErrorBadRequest, fmt.Sprintf("Upload 1 to %d files", maxClaimEvidenceFiles), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := firebase.UploadFiles(form)
+	if err != nil {
+		h.Logger.Error(err, "Error uploading claim evidence")
+		h.ReturnError(ctx, config.ErrorInternalServer, "Error uploading files", http.StatusInternalServerError)
+		return
+	}
+
+	var filePaths []string
+	for _, url := range resp.Url {
+		filePaths = append(filePaths, url.Url)
+	}
+
+	evidence, err := h.UseCase.BusinessClaimRepo.AddEvidence(ctx, claim.ID, filePaths)
+	if h.HandleDbError(ctx, err, "Error saving claim evidence") {
+		return
+	}
+	claim.Evidence = append(claim.Evidence, evidence...)
+
+	ctx.JSON(200, claim)
+}
+
+// GetBusinessClaim godoc
+// @Router /business-claim/{id} [get]
+// @Summary Get a business claim by ID
+// @Description Get a business claim by ID with its evidence
+// @Security BearerAuth
+// @Tags business-claim
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Claim ID"
+// @Success 200 {object} entity.BusinessClaim
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetBusinessClaim(ctx *gin.Context) {
+	claim, err := h.UseCase.BusinessClaimRepo.GetSingle(ctx, entity.Id{ID: ctx.Param("id")})
+	if h.HandleDbError(ctx, err, "Error getting business claim") {
+		return
+	}
+
+	if !isAdmin(ctx) && claim.UserID != GetUserID(ctx) {
+		h.ReturnError(ctx, config.ErrorNotFound, "Business claim not found", http.StatusNotFound)
+		return
+	}
+
+	ctx.JSON(200, claim)
+}
+
+// GetBusinessClaims godoc
+// @Router /business-claim/list [get]
+// @Summary Get a list of business claims
+// @Description Admins see every claim, business owners only their own. Evidence is only included by GET /business-claim/{id}.
+// @Security BearerAuth
+// @Tags business-claim
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param status query string false "pending, approved or rejected"
+// @Param business_id query string false "business"
+// @Success 200 {object} entity.BusinessClaimList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetBusinessClaims(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	status := ctx.DefaultQuery("status", "")
+	businessID := ctx.DefaultQuery("business_id", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if status != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "status",
+			Type:   "eq",
+			Value:  status,
+		})
+	}
+
+	if businessID != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "business_id",
+			Type:   "eq",
+			Value:  businessID,
+		})
+	}
+
+	if !isAdmin(ctx) {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "user_id",
+			Type:   "eq",
+			Value:  GetUserID(ctx),
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "created_at",
+		Order:  "desc",
+	})
+
+	claims, err := h.UseCase.BusinessClaimRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting business claims") {
+		return
+	}
+
+	ctx.JSON(200, claims)
+}
+
+// ApproveBusinessClaim godoc
+// @Router /business-claim/{id}/approve [post]
+// @Summary Approve a business claim
+// @Description Transfers the business to the claimant as its verified owner, rejects the other pending claims for it and notifies the previous owner
+// @Security BearerAuth
+// @Tags business-claim
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Claim ID"
+// @Param body body entity.ReviewBusinessClaim false "Review"
+// @Success 200 {object} entity.BusinessClaim
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) ApproveBusinessClaim(ctx *gin.Context) {
+	h.reviewBusinessClaim(ctx, "approved")
+}
+
+// RejectBusinessClaim godoc
+// @Router /business-claim/{id}/reject [post]
+// @Summary Reject a business claim
+// @Description Reject a business claim
+// @Security BearerAuth
+// @Tags business-claim
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Claim ID"
+// @Param body body entity.ReviewBusinessClaim false "Review"
+// @Success 200 {object} entity.BusinessClaim
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) RejectBusinessClaim(ctx *gin.Context) {
+	h.reviewBusinessClaim(ctx, "rejected")
+}
+
+func (h *Handler) reviewBusinessClaim(ctx *gin.Context, status string) {
+	var (
+		body            entity.ReviewBusinessClaim
+		claim           entity.BusinessClaim
+		previousOwnerID string
+		err             error
+	)
+
+	// the review note is optional
+	_ = ctx.ShouldBindJSON(&body)
+
+	review := entity.BusinessClaim{
+		ID:         ctx.Param("id"),
+		ReviewedBy: GetUserID(ctx),
+		ReviewNote: body.ReviewNote,
+	}
+
+	if status == "approved" {
+		var approval entity.BusinessClaimApproval
+		approval, err = h.UseCase.BusinessClaimRepo.Approve(ctx, review)
+		claim, previousOwnerID = approval.Claim, approval.PreviousOwnerID
+	} else {
+		claim, err = h.UseCase.BusinessClaimRepo.Reject(ctx, review)
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		h.ReturnError(ctx, config.ErrorConflict, "Business claim not found or already reviewed", http.StatusBadRequest)
+		return
+	}
+	if h.HandleDbError(ctx, err, "Error reviewing business claim") {
+		return
+	}
+
+	business, err := h.UseCase.BusinessRepo.GetSingle(ctx, entity.BusinessSingleRequest{ID: claim.BusinessID})
+	if h.HandleDbError(ctx, err, "Error getting business") {
+		return
+	}
+
+	if status == "approved" {
+		h.notifyUser(ctx, claim.UserID, fmt.Sprintf("Your claim for %s was approved, you are now its verified owner.", business.Name))
+		if previousOwnerID != "" && previousOwnerID != claim.UserID {
+			h.notifyUser(ctx, previousOwnerID, fmt.Sprintf("Ownership of %s was transferred to another owner after a verified claim. Contact support if this is a mistake.", business.Name))
+		}
+	} else {
+		h.notifyUser(ctx, claim.UserID, fmt.Sprintf("Your claim for %s was rejected. %s", business.Name, claim.ReviewNote))
+	}
+
+	ctx.JSON(200, claim)
+}
@@ -21,10 +21,11 @@ import (
 // @Param event body entity.Event true "Event object"
 // @Success 200 {object} entity.Event
 // @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
 func (h *Handler) CreateEvent(ctx *gin.Context) {
 	var req entity.Event
 
-	if ctx.GetHeader("user_role") != "business_owner" {
+	if GetUserRole(ctx) != "business_owner" {
 		ctx.JSON(404, gin.H{"error": "Only businessmen can create an event"})
 		return
 	}
@@ -34,6 +35,10 @@ func (h *Handler) CreateEvent(ctx *gin.Context) {
 		return
 	}
 
+	if !h.checkBusinessLive(ctx, req.BusinessID) {
+		return
+	}
+
 	res, err := h.UseCase.EventRepo.Create(ctx, req)
 	if h.HandleDbError(ctx, err, "Error creating event") {
 		return
@@ -147,7 +152,7 @@ func (h *Handler) UpdateEvent(ctx *gin.Context) {
 // DeleteEvent godoc
 // @Router /event/{id} [delete]
 // @Summary Delete an event
-// @Description Delete an event
+// @Description Delete an event, an admin can restore it until the retention period ends
 // @Security BearerAuth
 // @Tags event
 // @Accept  json
@@ -168,6 +173,33 @@ func (h *Handler) DeleteEvent(ctx *gin.Context) {
 	})
 }
 
+// RestoreEvent godoc
+// @Router /event/{id}/restore [post]
+// @Summary Restore a deleted event
+// @Description Brings back a deleted event that is not purged yet
+// @Security BearerAuth
+// @Tags event
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Event ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 404 {object} entity.ErrorResponse
+func (h *Handler) RestoreEvent(ctx *gin.Context) {
+	if GetUserType(ctx) != "admin" {
+		h.ReturnError(ctx, config.ErrorForbidden, "Access denied, only admin can restore event", 403)
+		return
+	}
+
+	err := h.UseCase.EventRepo.Restore(ctx, entity.Id{ID: ctx.Param("id")})
+	if h.HandleDbError(ctx, err, "Error restoring event") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Event restored successfully",
+	})
+}
+
 // AddParticipant godoc
 // @Router /event/add-participant [post]
 // @Summary Add a participant to an event
@@ -187,7 +219,7 @@ func (h *Handler) AddParticipant(ctx *gin.Context) {
 		return
 	}
 
-	req.UserID = ctx.GetHeader("sub")
+	req.UserID = GetUserID(ctx)
 	req.JoinedAt = time.Now().Format(time.RFC3339)
 	participant, err := h.UseCase.EventRepo.AddParticipant(ctx, req)
 	if h.HandleDbError(ctx, err, "Error adding participant") {
@@ -33,8 +33,8 @@ func (h *Handler) FollowUnfollow(ctx *gin.Context) {
 		return
 	}
 
-	if ctx.GetHeader("user_type") == "user" {
-		body.FollowerId = ctx.GetHeader("sub")
+	if GetUserType(ctx) == "user" {
+		body.FollowerId = GetUserID(ctx)
 	}
 
 	follower, err := h.UseCase.FollowerRepo.UpsertOrRemove(ctx, body)
@@ -124,8 +124,8 @@ func (h *Handler) GetFollowers(ctx *gin.Context) {
 	search := ctx.DefaultQuery("search", "")
 	following_id := ctx.DefaultQuery("following_id", "")
 
-	if ctx.GetHeader("user_type") == "user" {
-		following_id = ctx.GetHeader("sub")
+	if GetUserType(ctx) == "user" {
+		following_id = GetUserID(ctx)
 	}
 
 	if following_id == "" {
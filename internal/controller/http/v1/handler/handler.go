@@ -3,7 +3,10 @@ package handler
 import (
 	"github.com/Akorm0181/yelp/config"
 	"github.com/Akorm0181/yelp/internal/usecase"
+	"github.com/Akorm0181/yelp/pkg/jwt"
 	"github.com/Akorm0181/yelp/pkg/logger"
+	"github.com/Akorm0181/yelp/pkg/oidc"
+	"github.com/Akorm0181/yelp/pkg/ratelimit"
 	rediscache "github.com/golanguzb70/redis-cache"
 )
 
@@ -12,13 +15,31 @@ type Handler struct {
 	Config  *config.Config
 	UseCase *usecase.UseCase
 	Redis   rediscache.RedisCache
+	OIDC    *oidc.Verifier
+	// Limiter is required even with rate limiting turned off, it also counts code attempts
+	// and claims single-use tokens.
+	Limiter *ratelimit.Limiter
+	JWT     *jwt.KeySet
 }
 
-func NewHandler(l *logger.Logger, c *config.Config, useCase *usecase.UseCase, redis rediscache.RedisCache) *Handler {
+func NewHandler(l *logger.Logger, c *config.Config, useCase *usecase.UseCase, redis rediscache.RedisCache, limiter *ratelimit.Limiter, keys *jwt.KeySet) *Handler {
+	providers := make([]oidc.Provider, 0, len(c.OIDC.Providers))
+	for _, p := range c.OIDC.Providers {
+		providers = append(providers, oidc.Provider{
+			Name:      p.Name,
+			Issuer:    p.Issuer,
+			JWKSURL:   p.JWKSURL,
+			ClientIDs: p.ClientIDs,
+		})
+	}
+
 	return &Handler{
 		Logger:  l,
 		Config:  c,
 		UseCase: useCase,
 		Redis:   redis,
+		OIDC:    oidc.NewVerifier(providers...),
+		Limiter: limiter,
+		JWT:     keys,
 	}
 }
@@ -0,0 +1,12 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// GetJWKS serves the public keys other services use to verify our access tokens. It lives
+// outside of /v1 at the well-known path, so it is not part of the swagger spec.
+func (h *Handler) GetJWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(200, h.JWT.JWKS())
+}
@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// validateMenu checks a draft before it is saved and normalizes currencies and tags.
+func validateMenu(body *entity.MenuDraftRequest) error {
+	items := 0
+
+	for i := range body.Sections {
+		section := &body.Sections[i]
+
+		section.Name = strings.TrimSpace(section.Name)
+		if section.Name == "" || len(section.Name) > 255 {
+			return fmt.Errorf("section %d needs a name of at most 255 characters", i+1)
+		}
+
+		for j := range section.Items {
+			item := &section.Items[j]
+			items++
+
+			item.Name = strings.TrimSpace(item.Name)
+			if item.Name == "" || len(item.Name) > 255 {
+				return fmt.Errorf("%s: item %d needs a name of at most 255 characters", section.Name, j+1)
+			}
+
+			if item.Price < 0 || item.Price >= 1e10 || math.IsNaN(item.Price) {
+				return fmt.Errorf("%s: invalid price", item.Name)
+			}
+
+			item.Currency = strings.ToUpper(strings.TrimSpace(item.Currency))
+			if !currencyCode.MatchString(item.Currency) {
+				return fmt.Errorf("%s: currency must be an ISO 4217 code like USD", item.Name)
+			}
+
+			if len(item.PhotoURL) > 500 {
+				return fmt.Errorf("%s: photo_url is too long", item.Name)
+			}
+
+			var tags []string
+			for _, tag := range item.DietaryTags {
+				tag = strings.ToLower(strings.TrimSpace(tag))
+				if !slices.Contains(config.DietaryTags, tag) {
+					return fmt.Errorf("%s: unknown dietary tag %s, use one of %s", item.Name, tag, strings.Join(config.DietaryTags, ", "))
+				}
+				if !slices.Contains(tags, tag) {
+					tags = append(tags, tag)
+				}
+			}
+			item.DietaryTags = tags
+		}
+	}
+
+	if items > config.MaxMenuItems {
+		return fmt.Errorf("a menu can have at most %d items", config.MaxMenuItems)
+	}
+
+	return nil
+}
+
+// GetBusinessMenu godoc
+// @Router /business/{id}/menu [get]
+// @Summary Get the menu of a business
+// @Description Get the published menu of a business
+// @Security BearerAuth
+// @Tags menu
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Business ID"
+// @Success 200 {object} entity.Menu
+// @Failure 404 {object} entity.ErrorResponse
+func (h *Handler) GetBusinessMenu(ctx *gin.Context) {
+	menu, err := h.UseCase.MenuRepo.GetSingle(ctx, entity.MenuSingleRequest{
+		BusinessID: ctx.Param("id"),
+		Status:     "published",
+	})
+	if h.HandleDbError(ctx, err, "Error getting menu") {
+		return
+	}
+
+	ctx.JSON(200, menu)
+}
+
+// GetBusinessMenuVersions godoc
+// @Router /business/{id}/menu/versions [get]
+// @Summary Get the menu versions of a business
+// @Description Lists the draft, published and archived menus without their content, newest first
+// @Security BearerAuth
+// @Tags menu
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Business ID"
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Success 200 {object} entity.MenuList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetBusinessMenuVersions(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	if !h.canManageBusiness(ctx, ctx.Param("id")) {
+		return
+	}
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+	req.Filters = append(req.Filters, entity.Filter{
+		Column: "business_id",
+		Type:   "eq",
+		Value:  ctx.Param("id"),
+	})
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "version",
+		Order:  "desc",
+	})
+
+	menus, err := h.UseCase.MenuRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting menus") {
+		return
+	}
+
+	ctx.JSON(200, menus)
+}
+
+// GetBusinessMenuVersion godoc
+// @Router /business/{id}/menu/versions/{version} [get]
+// @Summary Get a menu version
+// @Description Get any version of the menu with its content
+// @Security BearerAuth
+// @Tags menu
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Business ID"
+// @Param version path int true "Version"
+// @Success 200 {object} entity.Menu
+// @Failure 404 {object} entity.ErrorResponse
+func (h *Handler) GetBusinessMenuVersion(ctx *gin.Context) {
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil || version < 1 {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid version", http.StatusBadRequest)
+		return
+	}
+
+	if !h.canManageBusiness(ctx, ctx.Param("id")) {
+		return
+	}
+
+	menu, err := h.UseCase.MenuRepo.GetSingle(ctx, entity.MenuSingleRequest{
+		BusinessID: ctx.Param("id"),
+		Version:    version,
+	})
+	if h.HandleDbError(ctx, err, "Error getting menu") {
+		return
+	}
+
+	ctx.JSON(200, menu)
+}
+
+// GetBusinessMenuDraft godoc
+// @Router /business/{id}/menu/draft [get]
+// @Summary Get the menu draft
+// @Description Get the unpublished draft of the menu
+// @Security BearerAuth
+// @Tags menu
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Business ID"
+// @Success 200 {object} entity.Menu
+// @Failure 404 {object} entity.ErrorResponse
+func (h *Handler) GetBusinessMenuDraft(ctx *gin.Context) {
+	if !h.canManageBusiness(ctx, ctx.Param("id")) {
+		return
+	}
+
+	menu, err := h.UseCase.MenuRepo.GetSingle(ctx, entity.MenuSingleRequest{
+		BusinessID: ctx.Param("id"),
+		Status:     "draft",
+	})
+	if h.HandleDbError(ctx, err, "Error getting menu draft") {
+		return
+	}
+
+	ctx.JSON(200, menu)
+}
+
+// SaveBusinessMenuDraft godoc
+// @Router /business/{id}/menu/draft [put]
+// @Summary Save the menu draft
+// @Description Replaces the content of the draft, a new version is started when there is no draft. Customers see it once it is published.
+// @Security BearerAuth
+// @Tags menu
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Business ID"
+// @Param body body entity.MenuDraftRequest true "Sections and items in menu order"
+// @Success 200 {object} entity.Menu
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) SaveBusinessMenuDraft(ctx *gin.Context) {
+	var (
+		body entity.MenuDraftRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = validateMenu(&body)
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorBadRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !h.canManageBusiness(ctx, ctx.Param("id")) {
+		return
+	}
+
+	menu, err := h.UseCase.MenuRepo.SaveDraft(ctx, entity.Menu{
+		BusinessID: ctx.Param("id"),
+		CreatedBy:  GetUserID(ctx),
+		Sections:   body.Sections,
+	})
+	if h.HandleDbError(ctx, err, "Error saving menu draft") {
+		return
+	}
+
+	ctx.JSON(200, menu)
+}
+
+// PublishBusinessMenu godoc
+// @Router /business/{id}/menu/draft/publish [post]
+// @Summary Publish the menu draft
+// @Description The draft becomes the menu customers see, the menu published before is archived
+// @Security BearerAuth
+// @Tags menu
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Business ID"
+// @Success 200 {object} entity.Menu
+// @Failure 404 {object} entity.ErrorResponse
+func (h *Handler) PublishBusinessMenu(ctx *gin.Context) {
+	if !h.canManageBusiness(ctx, ctx.Param("id")) {
+		return
+	}
+
+	menu, err := h.UseCase.MenuRepo.Publish(ctx, ctx.Param("id"), GetUserID(ctx))
+	if h.HandleDbError(ctx, err, "Error publishing menu") {
+		return
+	}
+
+	ctx.JSON(200, menu)
+}
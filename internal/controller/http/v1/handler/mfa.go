@@ -0,0 +1,448 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/Akorm0181/yelp/pkg/hash"
+	"github.com/Akorm0181/yelp/pkg/totp"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+// mfaCaller is the user a two-factor request is made for, either the logged in user or
+// the owner of an mfa token issued by Login.
+type mfaCaller struct {
+	UserID    string
+	Platform  string
+	TokenID   string
+	FromToken bool
+}
+
+// mfaEnforced reports whether the user's role must use two-factor authentication.
+func (h *Handler) mfaEnforced(user entity.User) bool {
+	return h.Config.MFA.Enforce && slices.Contains(config.MfaRequiredRoles, user.UserRole)
+}
+
+// getUserMfa returns the enrollment of the user, a zero value if there is none.
+func (h *Handler) getUserMfa(ctx *gin.Context, userID string) (entity.UserMfa, error) {
+	mfa, err := h.UseCase.UserMfaRepo.GetSingle(ctx, entity.Id{ID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.UserMfa{}, nil
+	}
+
+	return mfa, err
+}
+
+// mfaLoginChallenge returns the response for a password-verified login that still needs
+// a second factor, or nil if the user can get a session right away.
+func (h *Handler) mfaLoginChallenge(ctx *gin.Context, user entity.User, platform string) (*entity.MfaLoginResponse, error) {
+	mfa, err := h.getUserMfa(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !mfa.IsEnabled && !h.mfaEnforced(user) {
+		return nil, nil
+	}
+
+	token, err := h.JWT.Sign(map[string]interface{}{
+		"typ":         mfaTokenType,
+		"sub":         user.ID,
+		"platform":    platform,
+		"mfa_pending": true,
+		"jti":         uuid.NewString(),
+	}, config.MfaTokenExpireTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.MfaLoginResponse{
+		MfaRequired:      mfa.IsEnabled,
+		MfaSetupRequired: !mfa.IsEnabled,
+		MfaToken:         token,
+	}, nil
+}
+
+// getMfaCaller resolves who the request is for. Writes the error response itself and returns
+// false when neither a session nor a valid mfa token is present.
+func (h *Handler) getMfaCaller(ctx *gin.Context, mfaToken string) (mfaCaller, bool) {
+	if mfaToken == "" {
+		principal := GetPrincipal(ctx)
+		if principal.UserID == "" {
+			h.ReturnError(ctx, config.ErrorUnauthorized, "Login required", http.StatusUnauthorized)
+			return mfaCaller{}, false
+		}
+
+		return mfaCaller{UserID: principal.UserID, Platform: principal.Platform}, true
+	}
+
+	claims, err := h.JWT.Parse(mfaToken)
+	if err != nil || claims["typ"] != mfaTokenType || claims["mfa_pending"] != true {
+		h.ReturnError(ctx, config.ErrorInvalidToken, "Invalid or expired mfa token", http.StatusUnauthorized)
+		return mfaCaller{}, false
+	}
+
+	caller := mfaCaller{FromToken: true}
+	caller.UserID, _ = claims["sub"].(string)
+	caller.Platform, _ = claims["platform"].(string)
+	caller.TokenID, _ = claims["jti"].(string)
+
+	return caller, true
+}
+
+func mfaAttemptsKey(userID string) string {
+	return fmt.Sprintf("mfa-attempts-%s", userID)
+}
+
+// checkMfaCode validates a totp code or, when allowRecovery is set, a recovery code. Codes can be
+// guessed config.OtpMaxAttempts times per user within config.OtpLockoutTime, however many mfa
+// tokens are used for it, and a totp code can't be used twice. Every attempt is counted
+// atomically before the code is checked. Writes the error response itself and returns false
+// when the code is not accepted.
+func (h *Handler) checkMfaCode(ctx *gin.Context, caller mfaCaller, mfa entity.UserMfa, body entity.MfaCodeRequest, allowRecovery bool) bool {
+	attemptsKey := mfaAttemptsKey(caller.UserID)
+
+	attempts, err := h.Limiter.Incr(ctx, attemptsKey, config.OtpLockoutTime)
+	if err != nil {
+		h.Logger.Error(err, "Error counting mfa attempts")
+		h.ReturnError(ctx, config.ErrorInternalServer, "Oops, something went wrong!!!", http.StatusInternalServerError)
+		return false
+	}
+
+	if attempts > int64(config.OtpMaxAttempts) {
+		h.ReturnError(ctx, config.ErrorOtpLocked, "Too many incorrect attempts, try again later", http.StatusTooManyRequests)
+		return false
+	}
+
+	ok := false
+	switch {
+	case body.Code != "":
+		step, valid := totp.Validate(mfa.Secret, body.Code, time.Now())
+		stepKey := fmt.Sprintf("mfa-step-%s", caller.UserID)
+		lastStep, _ := h.Redis.Get(ctx, stepKey)
+		last, _ := strconv.ParseInt(lastStep, 10, 64)
+
+		if valid && step > last {
+			ok = true
+			err := h.Redis.Set(ctx, stepKey, strconv.FormatInt(step, 10), 2*(totp.Skew+1)*totp.Period)
+			if err != nil {
+				h.Logger.Error(err, "Error saving mfa step")
+			}
+		}
+	case body.RecoveryCode != "" && allowRecovery:
+		used, err := h.UseCase.UserMfaRepo.UseRecoveryCode(ctx, caller.UserID, hash.HashToken(body.RecoveryCode))
+		if h.HandleDbError(ctx, err, "Error using recovery code") {
+			return false
+		}
+		ok = used
+	}
+
+	if !ok {
+		h.ReturnError(ctx, config.ErrorInvalidMfaCode, "Incorrect two-factor code", http.StatusBadRequest)
+		return false
+	}
+
+	err = h.Limiter.Reset(ctx, attemptsKey)
+	if err != nil {
+		h.Logger.Error(err, "Error resetting mfa attempts")
+	}
+
+	return true
+}
+
+// finishMfaLogin creates the session for a login that was waiting for its second factor.
+func (h *Handler) finishMfaLogin(ctx *gin.Context, caller mfaCaller) {
+	// an mfa token can complete only one login, of concurrent requests only the first claims it
+	claimed, err := h.Limiter.Claim(ctx, mfaUsedKey(caller.TokenID), config.MfaTokenExpireTime)
+	if err != nil {
+		h.Logger.Error(err, "Error claiming mfa token")
+		h.ReturnError(ctx, config.ErrorInternalServer, "Oops, something went wrong!!!", http.StatusInternalServerError)
+		return
+	}
+
+	if !claimed {
+		h.ReturnError(ctx, config.ErrorInvalidToken, "Mfa token has already been used", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: caller.UserID})
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+
+	if !h.checkAccountStatus(ctx, &user) {
+		return
+	}
+
+	h.completeLogin(ctx, user, caller.Platform)
+}
+
+func mfaUsedKey(tokenID string) string {
+	return fmt.Sprintf("mfa-used-%s", tokenID)
+}
+
+// mfaTokenUsed turns away tokens that completed a login before a code is checked, finishMfaLogin
+// makes the final decision.
+func (h *Handler) mfaTokenUsed(ctx *gin.Context, caller mfaCaller) bool {
+	if !caller.FromToken {
+		return false
+	}
+
+	used, err := h.Limiter.Claimed(ctx, mfaUsedKey(caller.TokenID))
+	if err == nil && used {
+		h.ReturnError(ctx, config.ErrorInvalidToken, "Mfa token has already been used", http.StatusUnauthorized)
+		return true
+	}
+
+	return false
+}
+
+// SetupMfa godoc
+// @Router /auth/2fa/setup [post]
+// @Summary Start two-factor enrollment
+// @Description Generates a TOTP secret and recovery codes. Called with a Bearer token, or with the mfa_token from Login when enrollment is mandatory. 2FA is active only after /auth/2fa/enable.
+// @Security BearerAuth
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param body body entity.MfaSetupRequest false "Mfa token"
+// @Success 200 {object} entity.MfaSetupResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+func (h *Handler) SetupMfa(ctx *gin.Context) {
+	var (
+		body entity.MfaSetupRequest
+	)
+
+	_ = ctx.ShouldBindJSON(&body)
+
+	caller, ok := h.getMfaCaller(ctx, body.MfaToken)
+	if !ok || h.mfaTokenUsed(ctx, caller) {
+		return
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: caller.UserID})
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+
+	mfa, err := h.getUserMfa(ctx, user.ID)
+	if h.HandleDbError(ctx, err, "Error getting two-factor settings") {
+		return
+	}
+
+	if mfa.IsEnabled {
+		h.ReturnError(ctx, config.ErrorConflict, "Two-factor authentication is already enabled", http.StatusConflict)
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorInternalServer, "Oops, something went wrong!!!", http.StatusInternalServerError)
+		return
+	}
+
+	response := entity.MfaSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(h.Config.MFA.Issuer, user.Email, secret),
+	}
+
+	hashes := make([]string, 0, config.MfaRecoveryCodes)
+	for i := 0; i < config.MfaRecoveryCodes; i++ {
+		code, err := hash.GenerateToken(8)
+		if err != nil {
+			h.ReturnError(ctx, config.ErrorInternalServer, "Oops, something went wrong!!!", http.StatusInternalServerError)
+			return
+		}
+
+		response.RecoveryCodes = append(response.RecoveryCodes, code)
+		hashes = append(hashes, hash.HashToken(code))
+	}
+
+	_, err = h.UseCase.UserMfaRepo.Upsert(ctx, entity.UserMfa{
+		UserID:        user.ID,
+		Secret:        secret,
+		RecoveryCodes: hashes,
+	})
+	if h.HandleDbError(ctx, err, "Error saving two-factor settings") {
+		return
+	}
+
+	ctx.JSON(200, response)
+}
+
+// EnableMfa godoc
+// @Router /auth/2fa/enable [post]
+// @Summary Confirm two-factor enrollment
+// @Description Turns on 2FA after checking a code from the authenticator app. When called with an mfa_token from Login it also completes the login.
+// @Security BearerAuth
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param body body entity.MfaCodeRequest true "Code"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+func (h *Handler) EnableMfa(ctx *gin.Context) {
+	var (
+		body entity.MfaCodeRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || body.Code == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	caller, ok := h.getMfaCaller(ctx, body.MfaToken)
+	if !ok || h.mfaTokenUsed(ctx, caller) {
+		return
+	}
+
+	mfa, err := h.UseCase.UserMfaRepo.GetSingle(ctx, entity.Id{ID: caller.UserID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Two-factor setup was not started", http.StatusBadRequest)
+		return
+	}
+	if h.HandleDbError(ctx, err, "Error getting two-factor settings") {
+		return
+	}
+
+	if mfa.IsEnabled {
+		h.ReturnError(ctx, config.ErrorConflict, "Two-factor authentication is already enabled", http.StatusConflict)
+		return
+	}
+
+	if !h.checkMfaCode(ctx, caller, mfa, body, false) {
+		return
+	}
+
+	err = h.UseCase.UserMfaRepo.Enable(ctx, entity.Id{ID: caller.UserID})
+	if h.HandleDbError(ctx, err, "Error enabling two-factor authentication") {
+		return
+	}
+
+	if caller.FromToken {
+		h.finishMfaLogin(ctx, caller)
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Two-factor authentication enabled",
+	})
+}
+
+// VerifyMfa godoc
+// @Router /auth/2fa/verify [post]
+// @Summary Second login step
+// @Description Completes a login that returned mfa_required, using a code from the authenticator app or a recovery code
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param body body entity.MfaCodeRequest true "Code"
+// @Success 200 {object} entity.User
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+func (h *Handler) VerifyMfa(ctx *gin.Context) {
+	var (
+		body entity.MfaCodeRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || body.MfaToken == "" || (body.Code == "" && body.RecoveryCode == "") {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	caller, ok := h.getMfaCaller(ctx, body.MfaToken)
+	if !ok || h.mfaTokenUsed(ctx, caller) {
+		return
+	}
+
+	mfa, err := h.getUserMfa(ctx, caller.UserID)
+	if h.HandleDbError(ctx, err, "Error getting two-factor settings") {
+		return
+	}
+
+	if !mfa.IsEnabled {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Two-factor authentication is not enabled", http.StatusBadRequest)
+		return
+	}
+
+	if !h.checkMfaCode(ctx, caller, mfa, body, true) {
+		return
+	}
+
+	h.finishMfaLogin(ctx, caller)
+}
+
+// DisableMfa godoc
+// @Router /auth/2fa/disable [post]
+// @Summary Turn off two-factor authentication
+// @Description Turns off 2FA for the current user. Not allowed for roles where 2FA is mandatory.
+// @Security BearerAuth
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param body body entity.MfaCodeRequest true "Code"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+func (h *Handler) DisableMfa(ctx *gin.Context) {
+	var (
+		body entity.MfaCodeRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || (body.Code == "" && body.RecoveryCode == "") {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	// only a logged in user can turn 2FA off, never an mfa token
+	caller, ok := h.getMfaCaller(ctx, "")
+	if !ok {
+		return
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: caller.UserID})
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+
+	if h.mfaEnforced(user) {
+		h.ReturnError(ctx, config.ErrorMfaRequired, "Two-factor authentication is mandatory for your role", http.StatusForbidden)
+		return
+	}
+
+	mfa, err := h.getUserMfa(ctx, user.ID)
+	if h.HandleDbError(ctx, err, "Error getting two-factor settings") {
+		return
+	}
+
+	if !mfa.IsEnabled {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Two-factor authentication is not enabled", http.StatusBadRequest)
+		return
+	}
+
+	if !h.checkMfaCode(ctx, caller, mfa, body, true) {
+		return
+	}
+
+	err = h.UseCase.UserMfaRepo.Delete(ctx, entity.Id{ID: user.ID})
+	if h.HandleDbError(ctx, err, "Error disabling two-factor authentication") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Two-factor authentication disabled",
+	})
+}
@@ -29,7 +29,7 @@ func (h *Handler) CreateNotification(ctx *gin.Context) {
 		return
 	}
 
-	body.OwnerId = ctx.GetHeader("sub")
+	body.OwnerId = GetUserID(ctx)
 	body.Status = "unread"
 	resUser, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: body.UserID})
 	if h.HandleDbError(ctx, err, "Error getting notification") {
@@ -132,8 +132,8 @@ func (h *Handler) GetNotifications(ctx *gin.Context) {
 	limit := ctx.DefaultQuery("limit", "10")
 	userId := ctx.DefaultQuery("user_id", "")
 
-	if ctx.GetHeader("user_type") == "user" {
-		userId = ctx.GetHeader("sub")
+	if GetUserType(ctx) == "user" {
+		userId = GetUserID(ctx)
 	}
 
 	req.Page, _ = strconv.Atoi(page)
@@ -184,7 +184,7 @@ func (h *Handler) UpdateNotification(ctx *gin.Context) {
 		body entity.Notification
 	)
 
-	body.OwnerRole = ctx.GetHeader("user_role")
+	body.OwnerRole = GetUserRole(ctx)
 	err := ctx.ShouldBindJSON(&body)
 	if err != nil {
 		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
@@ -256,3 +256,35 @@ func (h *Handler) UpdateNotificationStatus(ctx *gin.Context) {
 
 	ctx.JSON(200, notification)
 }
+
+// notifyUser stores a notification from the caller to userID and emails it. It is a side
+// effect of another action, so failures are only logged.
+func (h *Handler) notifyUser(ctx *gin.Context, userID, message string) {
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: userID})
+	if err != nil {
+		h.Logger.Error(err, "Error getting user to notify")
+		return
+	}
+
+	_, err = h.UseCase.NotificationRepo.Create(ctx, entity.Notification{
+		OwnerId: GetUserID(ctx),
+		UserID:  user.ID,
+		Email:   user.Email,
+		Message: message,
+		Status:  "unread",
+	})
+	if err != nil {
+		h.Logger.Error(err, "Error creating notification")
+	}
+
+	emailBody, err := etc.GenerateNotificationEmailBody(message)
+	if err != nil {
+		h.Logger.Error(err, "Error generating notification email")
+		return
+	}
+
+	err = etc.SendEmail(h.Config.Gmail.Host, h.Config.Gmail.Port, h.Config.Gmail.Email, h.Config.Gmail.EmailPass, user.Email, emailBody)
+	if err != nil {
+		h.Logger.Error(err, "Error sending notification email")
+	}
+}
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/Akorm0181/yelp/pkg/hash"
+	"github.com/Akorm0181/yelp/pkg/oidc"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+)
+
+// OIDCLogin godoc
+// @Router /auth/oidc [post]
+// @Summary Social login
+// @Description Login with an OpenID Connect ID token (Sign in with Google/Apple). Links the identity to the account with the same verified email or creates a new active account, then returns the same response as /auth/login.
+// @Tags auth
+// @Accept  json
+// @Produce  json
+// @Param body body entity.OIDCLoginRequest true "ID token"
+// @Success 200 {object} entity.User
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+func (h *Handler) OIDCLogin(ctx *gin.Context) {
+	var (
+		body entity.OIDCLoginRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || body.Provider == "" || body.IDToken == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	claims, err := h.OIDC.Verify(ctx, body.Provider, body.IDToken)
+	if errors.Is(err, oidc.ErrUnknownProvider) {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Unsupported identity provider", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.Logger.Error(err, "Error verifying id token")
+		h.ReturnError(ctx, config.ErrorInvalidToken, "Invalid id token", http.StatusUnauthorized)
+		return
+	}
+
+	if body.Nonce != "" && body.Nonce != claims.Nonce {
+		h.ReturnError(ctx, config.ErrorInvalidToken, "Invalid id token nonce", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.userForIdentity(ctx, body.Provider, claims)
+	if errors.Is(err, errEmailNotVerified) {
+		h.ReturnError(ctx, config.ErrorInvalidEmail, "Email of the identity provider account is not verified", http.StatusBadRequest)
+		return
+	}
+	if h.HandleDbError(ctx, err, "Error linking identity") {
+		return
+	}
+
+	if !h.checkLoginPlatform(ctx, user, body.Platform) {
+		return
+	}
+
+	if !h.checkAccountStatus(ctx, &user) {
+		return
+	}
+
+	mfaResponse, err := h.mfaLoginChallenge(ctx, user, body.Platform)
+	if h.HandleDbError(ctx, err, "Error checking two-factor authentication") {
+		return
+	}
+
+	if mfaResponse != nil {
+		ctx.JSON(200, mfaResponse)
+		return
+	}
+
+	h.completeLogin(ctx, user, body.Platform)
+}
+
+var errEmailNotVerified = errors.New("identity email is not verified")
+
+// userForIdentity returns the user linked to the external identity. On first login the identity
+// is linked to the account with the same email, or a new active account is created. Emails are
+// only trusted when the provider verified them.
+func (h *Handler) userForIdentity(ctx *gin.Context, provider string, claims oidc.Claims) (entity.User, error) {
+	identity, err := h.UseCase.UserIdentityRepo.GetSingle(ctx, entity.UserIdentitySingleRequest{
+		Provider: provider,
+		Subject:  claims.Subject,
+	})
+	if err == nil {
+		return h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: identity.UserID})
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return entity.User{}, err
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return entity.User{}, errEmailNotVerified
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{Email: claims.Email})
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		user, err = h.createOIDCUser(ctx, claims)
+		if err != nil {
+			return entity.User{}, err
+		}
+	case err != nil:
+		return entity.User{}, err
+	case user.Status == "inverify":
+		// the provider has verified the email for us. Whoever registered it unverified may not
+		// own it, so their password and sessions don't carry over to the activated account.
+		password, err := randomPasswordHash()
+		if err != nil {
+			return entity.User{}, err
+		}
+
+		_, err = h.UseCase.UserRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+			Filter: []entity.Filter{{Column: "id", Type: "eq", Value: user.ID}},
+			Items: []entity.UpdateFieldItem{
+				{Column: "status", Value: "active"},
+				{Column: "password", Value: password},
+				{Column: "updated_at", Value: "now()"},
+			},
+		})
+		if err != nil {
+			return entity.User{}, err
+		}
+
+		_, err = h.UseCase.SessionRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+			Filter: []entity.Filter{{Column: "user_id", Type: "eq", Value: user.ID}},
+			Items: []entity.UpdateFieldItem{
+				{Column: "is_active", Value: "false"},
+				{Column: "updated_at", Value: "now()"},
+			},
+		})
+		if err != nil {
+			return entity.User{}, err
+		}
+		user.Status = "active"
+	}
+
+	_, err = h.UseCase.UserIdentityRepo.Create(ctx, entity.UserIdentity{
+		UserID:   user.ID,
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	})
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	return user, nil
+}
+
+// randomPasswordHash hashes a password nobody knows, the account can't be logged into with a
+// password until the user resets it.
+func randomPasswordHash() (string, error) {
+	randomPassword, err := hash.GenerateToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	return hash.HashPassword(randomPassword)
+}
+
+func (h *Handler) createOIDCUser(ctx *gin.Context, claims oidc.Claims) (entity.User, error) {
+	password, err := randomPasswordHash()
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	suffix, err := hash.GenerateToken(4)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	localPart := strings.Split(claims.Email, "@")[0]
+	if len(localPart) > 40 {
+		localPart = localPart[:40]
+	}
+
+	fullName := claims.Name
+	if fullName == "" {
+		fullName = localPart
+	}
+	if len(fullName) > 50 {
+		fullName = fullName[:50]
+	}
+
+	return h.UseCase.UserRepo.Create(ctx, entity.User{
+		FullName: fullName,
+		UserType: "user",
+		UserRole: "user",
+		UserName: localPart + "_" + strings.ToLower(suffix),
+		Email:    claims.Email,
+		Status:   "active",
+		Password: password,
+	})
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/pkg/etc"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errOtpCooldown = errors.New("otp was sent recently")
+	errOtpLocked   = errors.New("otp is locked for this email")
+)
+
+// Purposes of a code. A code is only accepted by the flow it was sent for, so a password reset
+// code can't verify an email or confirm an email change.
+const (
+	otpVerify      = "verify"
+	otpReset       = "reset"
+	otpEmailChange = "email-change"
+)
+
+func otpKey(purpose, email string) string {
+	return fmt.Sprintf("otp-%s-%s", purpose, email)
+}
+
+func otpAttemptsKey(purpose, email string) string {
+	return fmt.Sprintf("otp-attempts-%s-%s", purpose, email)
+}
+
+func otpCooldownKey(purpose, email string) string {
+	return fmt.Sprintf("otp-cooldown-%s-%s", purpose, email)
+}
+
+func otpLockKey(email string) string {
+	return fmt.Sprintf("otp-lock-%s", email)
+}
+
+func (h *Handler) otpLocked(ctx *gin.Context, email string) bool {
+	locked, err := h.Redis.Get(ctx, otpLockKey(email))
+	return err == nil && locked != ""
+}
+
+// sendOtp stores a fresh code for the email and purpose, resets its attempt counter and mails
+// the code using the given email template. Returns errOtpLocked while the email is locked out
+// and errOtpCooldown if a code for the purpose was sent less than config.OtpResendCooldown ago.
+func (h *Handler) sendOtp(ctx *gin.Context, purpose, email string, template func(otp string) (string, error)) error {
+	if h.otpLocked(ctx, email) {
+		return errOtpLocked
+	}
+
+	cooldown, err := h.Redis.Get(ctx, otpCooldownKey(purpose, email))
+	if err == nil && cooldown != "" {
+		return errOtpCooldown
+	}
+
+	otp := etc.GenerateOTP(6)
+	ttl := int(config.OtpExpireTime.Seconds())
+
+	err = h.Redis.Set(ctx, otpKey(purpose, email), otp, ttl)
+	if err != nil {
+		return err
+	}
+
+	err = h.Limiter.Reset(ctx, otpAttemptsKey(purpose, email))
+	if err != nil {
+		return err
+	}
+
+	err = h.Redis.Set(ctx, otpCooldownKey(purpose, email), "1", int(config.OtpResendCooldown.Seconds()))
+	if err != nil {
+		return err
+	}
+
+	emailBody, err := template(otp)
+	if err != nil {
+		return err
+	}
+
+	return etc.SendEmail(h.Config.Gmail.Host, h.Config.Gmail.Port, h.Config.Gmail.Email, h.Config.Gmail.EmailPass, email, emailBody)
+}
+
+// HandleOtpSendError writes the response for an error returned by sendOtp.
+func (h *Handler) HandleOtpSendError(ctx *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, errOtpLocked):
+		h.ReturnError(ctx, config.ErrorOtpLocked, "Too many incorrect attempts, try again later", http.StatusTooManyRequests)
+	case errors.Is(err, errOtpCooldown):
+		ctx.Header("Retry-After", strconv.Itoa(int(config.OtpResendCooldown.Seconds())))
+		h.ReturnError(ctx, config.ErrorOtpCooldown, "Otp was sent recently, please wait before requesting a new one", http.StatusTooManyRequests)
+	default:
+		h.Logger.Error(err, "Error sending otp")
+		h.ReturnError(ctx, config.ErrorInternalServer, "Error sending OTP", http.StatusInternalServerError)
+	}
+
+	return true
+}
+
+// checkOtp compares the code with the one stored for the email and purpose. Every attempt is
+// counted atomically before the comparison, so parallel guesses can't share a count, and after
+// config.OtpMaxAttempts the code is burned and the email is locked for config.OtpLockoutTime.
+// Writes the error response itself and returns false when the code is not accepted.
+func (h *Handler) checkOtp(ctx *gin.Context, purpose, email, code string) bool {
+	if h.otpLocked(ctx, email) {
+		h.ReturnError(ctx, config.ErrorOtpLocked, "Too many incorrect attempts, try again later", http.StatusTooManyRequests)
+		return false
+	}
+
+	otp, err := h.Redis.Get(ctx, otpKey(purpose, email))
+	if err != nil || otp == "" {
+		h.ReturnError(ctx, config.ErrorOtpExpired, "Otp is expired or was never requested", http.StatusBadRequest)
+		return false
+	}
+
+	attempts, err := h.Limiter.Incr(ctx, otpAttemptsKey(purpose, email), config.OtpExpireTime)
+	if err != nil {
+		h.Logger.Error(err, "Error counting otp attempts")
+		h.ReturnError(ctx, config.ErrorInternalServer, "Oops, something went wrong!!!", http.StatusInternalServerError)
+		return false
+	}
+
+	// guesses sent in parallel with the one that used up the attempts
+	if attempts > int64(config.OtpMaxAttempts) {
+		h.ReturnError(ctx, config.ErrorOtpLocked, "Too many incorrect attempts, try again later", http.StatusTooManyRequests)
+		return false
+	}
+
+	if otp != code {
+		if attempts == int64(config.OtpMaxAttempts) {
+			_ = h.Redis.Del(ctx, otpKey(purpose, email))
+
+			err = h.Redis.Set(ctx, otpLockKey(email), "1", int(config.OtpLockoutTime.Seconds()))
+			if err != nil {
+				h.Logger.Error(err, "Error locking otp")
+			}
+
+			h.ReturnError(ctx, config.ErrorOtpLocked, "Too many incorrect attempts, try again later", http.StatusTooManyRequests)
+			return false
+		}
+
+		h.ReturnError(ctx, config.ErrorInvalidOtp, fmt.Sprintf("Incorrect otp, %d attempts left", int64(config.OtpMaxAttempts)-attempts), http.StatusBadRequest)
+		return false
+	}
+
+	_ = h.Redis.Del(ctx, otpKey(purpose, email))
+
+	err = h.Limiter.Reset(ctx, otpAttemptsKey(purpose, email))
+	if err != nil {
+		h.Logger.Error(err, "Error resetting otp attempts")
+	}
+
+	return true
+}
@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+)
+
+// isAdmin reports whether the caller may manage other users' data.
+func isAdmin(ctx *gin.Context) bool {
+	role := GetUserRole(ctx)
+	return role == "admin" || role == "superadmin"
+}
+
+// CreateOwnerRequest godoc
+// @Router /owner-request [post]
+// @Summary Apply to become a business owner
+// @Description The request is reviewed by an admin, after approval the account gets the business_owner role
+// @Security BearerAuth
+// @Tags owner-request
+// @Accept  json
+// @Produce  json
+// @Param body body entity.CreateOwnerRequest true "Owner request"
+// @Success 201 {object} entity.OwnerRequest
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) CreateOwnerRequest(ctx *gin.Context) {
+	var (
+		body entity.CreateOwnerRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || body.BusinessName == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	if GetUserRole(ctx) != "user" {
+		h.ReturnError(ctx, config.ErrorConflict, "Only regular users can apply to become a business owner", http.StatusBadRequest)
+		return
+	}
+
+	request, err := h.UseCase.OwnerRequestRepo.Create(ctx, entity.OwnerRequest{
+		UserID:       GetUserID(ctx),
+		BusinessName: body.BusinessName,
+		Phone:        body.Phone,
+		Note:         body.Note,
+	})
+	if h.HandleDbError(ctx, err, "Error creating owner request") {
+		return
+	}
+
+	ctx.JSON(201, request)
+}
+
+// GetOwnerRequest godoc
+// @Router /owner-request/{id} [get]
+// @Summary Get an owner request by ID
+// @Description Get an owner request by ID
+// @Security BearerAuth
+// @Tags owner-request
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Owner request ID"
+// @Success 200 {object} entity.OwnerRequest
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetOwnerRequest(ctx *gin.Context) {
+	request, err := h.UseCase.OwnerRequestRepo.GetSingle(ctx, entity.Id{ID: ctx.Param("id")})
+	if h.HandleDbError(ctx, err, "Error getting owner request") {
+		return
+	}
+
+	if !isAdmin(ctx) && request.UserID != GetUserID(ctx) {
+		h.ReturnError(ctx, config.ErrorNotFound, "Owner request not found", http.StatusNotFound)
+		return
+	}
+
+	ctx.JSON(200, request)
+}
+
+// GetOwnerRequests godoc
+// @Router /owner-request/list [get]
+// @Summary Get a list of owner requests
+// @Description Admins see every request, other users only their own
+// @Security BearerAuth
+// @Tags owner-request
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param status query string false "pending, approved or rejected"
+// @Success 200 {object} entity.OwnerRequestList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetOwnerRequests(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	status := ctx.DefaultQuery("status", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+
+	if status != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "status",
+			Type:   "eq",
+			Value:  status,
+		})
+	}
+
+	if !isAdmin(ctx) {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "user_id",
+			Type:   "eq",
+			Value:  GetUserID(ctx),
+		})
+	}
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "created_at",
+		Order:  "desc",
+	})
+
+	requests, err := h.UseCase.OwnerRequestRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting owner requests") {
+		return
+	}
+
+	ctx.JSON(200, requests)
+}
+
+// ApproveOwnerRequest godoc
+// @Router /owner-request/{id}/approve [post]
+// @Summary Approve an owner request
+// @Description Gives the applicant the business_owner role and signs out their sessions, they log in again on the business_owner platform
+// @Security BearerAuth
+// @Tags owner-request
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Owner request ID"
+// @Param body body entity.ReviewOwnerRequest false "Review"
+// @Success 200 {object} entity.OwnerRequest
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) ApproveOwnerRequest(ctx *gin.Context) {
+	h.reviewOwnerRequest(ctx, "approved")
+}
+
+// RejectOwnerRequest godoc
+// @Router /owner-request/{id}/reject [post]
+// @Summary Reject an owner request
+// @Description Reject an owner request
+// @Security BearerAuth
+// @Tags owner-request
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Owner request ID"
+// @Param body body entity.ReviewOwnerRequest false "Review"
+// @Success 200 {object} entity.OwnerRequest
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) RejectOwnerRequest(ctx *gin.Context) {
+	h.reviewOwnerRequest(ctx, "rejected")
+}
+
+func (h *Handler) reviewOwnerRequest(ctx *gin.Context, status string) {
+	var (
+		body entity.ReviewOwnerRequest
+	)
+
+	// the review note is optional
+	_ = ctx.ShouldBindJSON(&body)
+
+	request, err := h.UseCase.OwnerRequestRepo.GetSingle(ctx, entity.Id{ID: ctx.Param("id")})
+	if h.HandleDbError(ctx, err, "Error getting owner request") {
+		return
+	}
+
+	if request.Status != "pending" {
+		h.ReturnError(ctx, config.ErrorConflict, "Owner request was already reviewed", http.StatusBadRequest)
+		return
+	}
+
+	review := entity.OwnerRequest{
+		ID:         request.ID,
+		Status:     status,
+		ReviewedBy: GetUserID(ctx),
+		ReviewNote: body.ReviewNote,
+	}
+
+	// approving changes the role of the applicant together with the request
+	if status == "approved" {
+		request, err = h.UseCase.OwnerRequestRepo.Approve(ctx, review)
+	} else {
+		request, err = h.UseCase.OwnerRequestRepo.Review(ctx, review)
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		h.ReturnError(ctx, config.ErrorConflict, "Owner request was already reviewed", http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, entity.ErrOwnerApplicantIneligible) {
+		h.ReturnError(ctx, config.ErrorConflict, "Only an active user without another role can become a business owner", http.StatusBadRequest)
+		return
+	}
+	if h.HandleDbError(ctx, err, "Error reviewing owner request") {
+		return
+	}
+
+	if status == "approved" {
+		// tokens carry the role, the owner has to sign in again to get one with the new role
+		_, err = h.UseCase.SessionRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+			Filter: []entity.Filter{{Column: "user_id", Type: "eq", Value: request.UserID}},
+			Items:  []entity.UpdateFieldItem{{Column: "is_active", Value: "false"}},
+		})
+		if h.HandleDbError(ctx, err, "Error revoking sessions") {
+			return
+		}
+	}
+
+	ctx.JSON(200, request)
+}
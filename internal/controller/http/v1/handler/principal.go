@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+const principalKey = "principal"
+
+// claimHeaders used to carry the token claims to handlers. They are removed from every incoming
+// request so nothing downstream can mistake a client supplied value for an authenticated one.
+var claimHeaders = []string{"sub", "user_role", "user_type", "session_id", "platform", "iat", "exp"}
+
+func principalFromClaims(claims map[string]interface{}) entity.Principal {
+	claim := func(key string) string {
+		v, _ := claims[key].(string)
+		return v
+	}
+
+	return entity.Principal{
+		UserID:    claim("sub"),
+		UserRole:  claim("user_role"),
+		UserType:  claim("user_type"),
+		SessionID: claim("session_id"),
+		Platform:  claim("platform"),
+	}
+}
+
+// GetPrincipal returns the authenticated caller set by AuthMiddleware,
+// or an empty principal for unauthenticated requests.
+func GetPrincipal(ctx *gin.Context) entity.Principal {
+	v, ok := ctx.Get(principalKey)
+	if !ok {
+		return entity.Principal{}
+	}
+
+	principal, _ := v.(entity.Principal)
+	return principal
+}
+
+// GetUserID returns the id of the authenticated user.
+func GetUserID(ctx *gin.Context) string {
+	return GetPrincipal(ctx).UserID
+}
+
+// GetUserType returns the user_type of the authenticated user.
+func GetUserType(ctx *gin.Context) string {
+	return GetPrincipal(ctx).UserType
+}
+
+// GetUserRole returns the user_role of the authenticated user.
+func GetUserRole(ctx *gin.Context) string {
+	return GetPrincipal(ctx).UserRole
+}
+
+// GetSessionID returns the session the access token belongs to.
+func GetSessionID(ctx *gin.Context) string {
+	return GetPrincipal(ctx).SessionID
+}
@@ -30,7 +30,7 @@ func (h *Handler) CreatePromotion(ctx *gin.Context) {
 		return
 	}
 
-	body.UserID = ctx.GetHeader("sub")
+	body.UserID = GetUserID(ctx)
 
 	if body.ExpiresAt.Before(body.StartedAt) {
 		h.ReturnError(ctx, config.ErrorBadRequest, "End date must be after start date", 400)
@@ -144,8 +144,8 @@ func (h *Handler) DeletePromotion(ctx *gin.Context) {
 
 	req.ID = ctx.Param("id")
 
-	if ctx.GetHeader("user_type") == "user" {
-		req.ID = ctx.GetHeader("sub")
+	if GetUserType(ctx) == "user" {
+		req.ID = GetUserID(ctx)
 	}
 
 	err := h.UseCase.UserRepo.Delete(ctx, req)
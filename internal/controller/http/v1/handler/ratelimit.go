@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimitBody holds the request body fields a limit can be keyed by.
+type rateLimitBody struct {
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+// RateLimit limits the routes of the named group from config.RateLimit.Groups. Every key of the
+// group (client ip, email or username from the json body) has its own sliding window, so one
+// address can't spray many accounts and many addresses can't hammer one account.
+// Redis errors let the request through: an outage must not lock everyone out.
+func (h *Handler) RateLimit(group string) gin.HandlerFunc {
+	var limits *config.RateLimitGroup
+	for i := range h.Config.RateLimit.Groups {
+		if h.Config.RateLimit.Groups[i].Name == group {
+			limits = &h.Config.RateLimit.Groups[i]
+		}
+	}
+
+	return func(ctx *gin.Context) {
+		if !h.Config.RateLimit.Enabled || limits == nil || limits.Limit <= 0 {
+			ctx.Next()
+			return
+		}
+
+		values := map[string]string{
+			"ip": ctx.ClientIP(),
+		}
+
+		body := peekRateLimitBody(ctx)
+		values["email"] = strings.ToLower(strings.TrimSpace(body.Email))
+		values["username"] = strings.ToLower(strings.TrimSpace(body.Username))
+
+		window := time.Duration(limits.WindowSeconds) * time.Second
+		for _, key := range limits.Keys {
+			if values[key] == "" {
+				continue
+			}
+
+			allowed, retryAfter, err := h.Limiter.Allow(ctx, fmt.Sprintf("%s-%s-%s", group, key, values[key]), limits.Limit, window)
+			if err != nil {
+				h.Logger.Error(err, "Error checking rate limit")
+				break
+			}
+
+			if !allowed {
+				ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				h.ReturnError(ctx, config.ErrorTooManyAttempt, "Too many requests, try again later", http.StatusTooManyRequests)
+				ctx.Abort()
+				return
+			}
+		}
+
+		ctx.Next()
+	}
+}
+
+// peekRateLimitBody decodes the json body without consuming it for the handler.
+func peekRateLimitBody(ctx *gin.Context) rateLimitBody {
+	var body rateLimitBody
+
+	if ctx.Request.Body == nil {
+		return body
+	}
+
+	raw, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return body
+	}
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(raw))
+
+	_ = json.Unmarshal(raw, &body)
+
+	return body
+}
+
+func loginFailuresKey(userID string) string {
+	return fmt.Sprintf("login-failures-%s", userID)
+}
+
+func loginLockoutsKey(userID string) string {
+	return fmt.Sprintf("login-lockouts-%s", userID)
+}
+
+func loginLockKey(userID string) string {
+	return fmt.Sprintf("login-lock-%s", userID)
+}
+
+// loginLocked returns how long the account stays locked, zero when it is not locked.
+func (h *Handler) loginLocked(ctx *gin.Context, userID string) time.Duration {
+	until, err := h.Redis.Get(ctx, loginLockKey(userID))
+	if err != nil || until == "" {
+		return 0
+	}
+
+	unix, err := strconv.ParseInt(until, 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return time.Until(time.Unix(unix, 0))
+}
+
+// recordLoginFailure counts a wrong password. After config.RateLimit.LoginMaxFailures in a row the
+// account is locked; every lockout within a day doubles the next one up to LoginMaxLockoutSeconds.
+// Returns the lockout duration when this failure locked the account. The counters are atomic, so
+// of concurrent failures exactly one reaches the limit and locks.
+func (h *Handler) recordLoginFailure(ctx *gin.Context, userID string) time.Duration {
+	cfg := h.Config.RateLimit
+	if cfg.LoginMaxFailures <= 0 {
+		return 0
+	}
+
+	failures, err := h.Limiter.Incr(ctx, loginFailuresKey(userID), config.LoginFailureWindow)
+	if err != nil {
+		h.Logger.Error(err, "Error counting login failures")
+		return 0
+	}
+
+	if failures != int64(cfg.LoginMaxFailures) {
+		return 0
+	}
+
+	lockouts, err := h.Limiter.Incr(ctx, loginLockoutsKey(userID), config.LoginLockoutMemory)
+	if err != nil {
+		h.Logger.Error(err, "Error counting login lockouts")
+		lockouts = 1
+	}
+
+	seconds := cfg.LoginLockoutSeconds << min(lockouts-1, 30)
+	if seconds > cfg.LoginMaxLockoutSeconds || seconds <= 0 {
+		seconds = cfg.LoginMaxLockoutSeconds
+	}
+	lockout := time.Duration(seconds) * time.Second
+
+	err = h.Redis.Set(ctx, loginLockKey(userID), strconv.FormatInt(time.Now().Add(lockout).Unix(), 10), seconds)
+	if err != nil {
+		h.Logger.Error(err, "Error locking login")
+	}
+
+	err = h.Limiter.Reset(ctx, loginFailuresKey(userID))
+	if err != nil {
+		h.Logger.Error(err, "Error resetting login failures")
+	}
+
+	return lockout
+}
+
+// resetLoginFailures forgets failed attempts and lockouts after a successful login.
+func (h *Handler) resetLoginFailures(ctx *gin.Context, userID string) {
+	err := h.Limiter.Reset(ctx, loginFailuresKey(userID), loginLockoutsKey(userID))
+	if err != nil {
+		h.Logger.Error(err, "Error resetting login failures")
+	}
+}
+
+func (h *Handler) returnLoginLocked(ctx *gin.Context, lockout time.Duration) {
+	ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(lockout.Seconds()))))
+	h.ReturnError(ctx, config.ErrorAccountLocked, "Too many incorrect passwords, try again later", http.StatusTooManyRequests)
+}
@@ -19,6 +19,7 @@ import (
 // @Param report body entity.Report true "Report object"
 // @Success 201 {object} entity.Report
 // @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
 func (h *Handler) CreateReport(ctx *gin.Context) {
 	var (
 		body entity.Report
@@ -30,7 +31,11 @@ func (h *Handler) CreateReport(ctx *gin.Context) {
 		return
 	}
 
-	body.UserID = ctx.GetHeader("sub")
+	body.UserID = GetUserID(ctx)
+
+	if !h.checkBusinessLive(ctx, body.BusinessID) {
+		return
+	}
 
 	report, err := h.UseCase.ReportRepo.Create(ctx, body)
 	if h.HandleDbError(ctx, err, "Error creating report") {
@@ -143,8 +148,8 @@ func (h *Handler) UpdateReport(ctx *gin.Context) {
 		return
 	}
 
-	if ctx.GetHeader("user_type") == "user" {
-		body.ID = ctx.GetHeader("sub")
+	if GetUserType(ctx) == "user" {
+		body.ID = GetUserID(ctx)
 	}
 
 	report, err := h.UseCase.ReportRepo.Update(ctx, body)
@@ -173,8 +178,8 @@ func (h *Handler) DeleteReport(ctx *gin.Context) {
 
 	req.ID = ctx.Param("id")
 
-	if ctx.GetHeader("user_type") == "user" {
-		req.ID = ctx.GetHeader("sub")
+	if GetUserType(ctx) == "user" {
+		req.ID = GetUserID(ctx)
 	}
 
 	err := h.UseCase.ReportRepo.Delete(ctx, req)
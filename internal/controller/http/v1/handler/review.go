@@ -20,6 +20,7 @@ import (
 // @Param review body entity.Review true "Review object"
 // @Success 201 {object} entity.Review
 // @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
 func (h *Handler) CreateReview(ctx *gin.Context) {
 	var (
 		body entity.Review
@@ -31,7 +32,11 @@ func (h *Handler) CreateReview(ctx *gin.Context) {
 		return
 	}
 
-	body.UserID = ctx.GetHeader("sub")
+	body.UserID = GetUserID(ctx)
+
+	if !h.checkBusinessLive(ctx, body.BusinessID) {
+		return
+	}
 
 	review, err := h.UseCase.ReviewRepo.Create(ctx, body)
 	if h.HandleDbError(ctx, err, "Error creating review") {
@@ -167,7 +172,7 @@ func (h *Handler) UpdateReview(ctx *gin.Context) {
 		return
 	}
 
-	if body.UserID != ctx.GetHeader("sub") || ctx.GetHeader("user_type") != "admin" {
+	if body.UserID != GetUserID(ctx) || GetUserType(ctx) != "admin" {
 		h.ReturnError(ctx, config.ErrorForbidden, "You have no access to the comment", http.StatusForbidden)
 		return
 	}
@@ -191,7 +196,7 @@ func (h *Handler) UpdateReview(ctx *gin.Context) {
 // DeleteReview godoc
 // @Router /review/{id} [delete]
 // @Summary Delete a review
-// @Description Delete a review
+// @Description Delete a review, an admin can restore it until the retention period ends
 // @Security BearerAuth
 // @Tags review
 // @Accept  json
@@ -211,7 +216,7 @@ func (h *Handler) DeleteReview(ctx *gin.Context) {
 		return
 	}
 
-	if body.UserID != ctx.GetHeader("sub") || ctx.GetHeader("user_type") != "admin" {
+	if body.UserID != GetUserID(ctx) || GetUserType(ctx) != "admin" {
 		h.ReturnError(ctx, config.ErrorForbidden, "You have no access to the comment", http.StatusForbidden)
 		return
 	}
@@ -225,3 +230,30 @@ func (h *Handler) DeleteReview(ctx *gin.Context) {
 		Message: "Review deleted successfully",
 	})
 }
+
+// RestoreReview godoc
+// @Router /review/{id}/restore [post]
+// @Summary Restore a deleted review
+// @Description Brings back a deleted review that is not purged yet, it counts in the business rating again
+// @Security BearerAuth
+// @Tags review
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Review ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 404 {object} entity.ErrorResponse
+func (h *Handler) RestoreReview(ctx *gin.Context) {
+	if GetUserType(ctx) != "admin" {
+		h.ReturnError(ctx, config.ErrorForbidden, "Access denied, only admin can restore review", http.StatusForbidden)
+		return
+	}
+
+	err := h.UseCase.ReviewRepo.Restore(ctx, entity.Id{ID: ctx.Param("id")})
+	if h.HandleDbError(ctx, err, "Error restoring review") {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Review restored successfully",
+	})
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+var searchTypes = []string{"business", "review", "event"}
+
+// Search godoc
+// @Router /search [get]
+// @Summary Search businesses, reviews and events
+// @Description Full-text search ranked by relevance. The last word also matches as a prefix, so it can back a typeahead. Snippets are HTML escaped with the matched words wrapped in <mark></mark>. Results can be paged up to the 500th.
+// @Security BearerAuth
+// @Tags search
+// @Accept  json
+// @Produce  json
+// @Param q query string true "search text"
+// @Param types query string false "comma separated: business,review,event, all by default"
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Success 200 {object} entity.SearchResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) Search(ctx *gin.Context) {
+	var (
+		req entity.SearchRequest
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	types := ctx.DefaultQuery("types", "")
+
+	req.Query = strings.TrimSpace(ctx.Query("q"))
+	if req.Query == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "q is required", http.StatusBadRequest)
+		return
+	}
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+	if req.Limit > 50 {
+		req.Limit = 50
+	}
+
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+
+	// every type ranks and highlights all rows up to the page, deep pages are turned away
+	if req.Page > config.MaxSearchResults/req.Limit {
+		h.ReturnError(ctx, config.ErrorBadRequest,
+			fmt.Sprintf("Only the first %d results can be paged through, refine the search", config.MaxSearchResults), http.StatusBadRequest)
+		return
+	}
+
+	req.Types = searchTypes
+	if types != "" {
+		req.Types = nil
+		for _, kind := range strings.Split(types, ",") {
+			if !slices.Contains(searchTypes, kind) {
+				h.ReturnError(ctx, config.ErrorBadRequest, "Unknown type "+kind, http.StatusBadRequest)
+				return
+			}
+			if !slices.Contains(req.Types, kind) {
+				req.Types = append(req.Types, kind)
+			}
+		}
+	}
+
+	results, err := h.UseCase.SearchRepo.Search(ctx, req)
+	if h.HandleDbError(ctx, err, "Error searching") {
+		return
+	}
+
+	ctx.JSON(200, results)
+}
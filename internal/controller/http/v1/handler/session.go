@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"net/http"
 	"strconv"
 
 	"github.com/Akorm0181/yelp/config"
@@ -56,8 +57,8 @@ func (h *Handler) GetSessions(ctx *gin.Context) {
 	limit := ctx.DefaultQuery("limit", "10")
 	userId := ctx.DefaultQuery("user_id", "")
 
-	if ctx.GetHeader("user_type") == "user" {
-		userId = ctx.GetHeader("sub")
+	if GetUserType(ctx) == "user" {
+		userId = GetUserID(ctx)
 	}
 
 	req.Page, _ = strconv.Atoi(page)
@@ -140,3 +141,121 @@ func (h *Handler) DeleteSession(ctx *gin.Context) {
 		Message: "Session deleted successfully",
 	})
 }
+
+// GetMySessions godoc
+// @Router /session/me [get]
+// @Summary List my sessions
+// @Description Lists active sessions (devices) of the current user, the session of this request is marked with is_current
+// @Security BearerAuth
+// @Tags session
+// @Accept  json
+// @Produce  json
+// @Param page query number false "page"
+// @Param limit query number false "limit"
+// @Success 200 {object} entity.SessionList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetMySessions(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+	req.Filters = append(req.Filters,
+		entity.Filter{
+			Column: "user_id",
+			Type:   "eq",
+			Value:  GetUserID(ctx),
+		},
+		entity.Filter{
+			Column: "is_active",
+			Type:   "eq",
+			Value:  "true",
+		},
+	)
+
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "last_active_at",
+		Order:  "desc NULLS LAST",
+	})
+
+	sessions, err := h.UseCase.SessionRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting sessions") {
+		return
+	}
+
+	for i := range sessions.Items {
+		sessions.Items[i].IsCurrent = sessions.Items[i].ID == GetSessionID(ctx)
+	}
+
+	ctx.JSON(200, sessions)
+}
+
+// RevokeMySession godoc
+// @Router /session/me/{id} [delete]
+// @Summary Revoke one of my sessions
+// @Description Logs out one session (device) of the current user
+// @Security BearerAuth
+// @Tags session
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Session ID"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 404 {object} entity.ErrorResponse
+func (h *Handler) RevokeMySession(ctx *gin.Context) {
+	res, err := h.UseCase.SessionRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{
+			{Column: "id", Type: "eq", Value: ctx.Param("id")},
+			{Column: "user_id", Type: "eq", Value: GetUserID(ctx)},
+			{Column: "is_active", Type: "eq", Value: "true"},
+		},
+		Items: []entity.UpdateFieldItem{
+			{Column: "is_active", Value: "false"},
+			{Column: "updated_at", Value: "now()"},
+		},
+	})
+	if h.HandleDbError(ctx, err, "Error revoking session") {
+		return
+	}
+
+	if res.RowsEffected == 0 {
+		h.ReturnError(ctx, config.ErrorNotFound, "Session not found", http.StatusNotFound)
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Session revoked successfully",
+	})
+}
+
+// RevokeMyOtherSessions godoc
+// @Router /session/me/others [delete]
+// @Summary Log out everywhere else
+// @Description Revokes every session of the current user except the one making this request
+// @Security BearerAuth
+// @Tags session
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} entity.RowsEffected
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) RevokeMyOtherSessions(ctx *gin.Context) {
+	res, err := h.UseCase.SessionRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{
+			{Column: "user_id", Type: "eq", Value: GetUserID(ctx)},
+			{Column: "id", Type: "neq", Value: GetSessionID(ctx)},
+			{Column: "is_active", Type: "eq", Value: "true"},
+		},
+		Items: []entity.UpdateFieldItem{
+			{Column: "is_active", Value: "false"},
+			{Column: "updated_at", Value: "now()"},
+		},
+	})
+	if h.HandleDbError(ctx, err, "Error revoking sessions") {
+		return
+	}
+
+	ctx.JSON(200, res)
+}
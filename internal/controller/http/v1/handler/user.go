@@ -151,8 +151,23 @@ func (h *Handler) UpdateUser(ctx *gin.Context) {
 		return
 	}
 
-	if ctx.GetHeader("user_type") == "user" {
-		body.ID = ctx.GetHeader("sub")
+	if GetUserType(ctx) == "user" {
+		body.ID = GetUserID(ctx)
+	}
+
+	current, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: body.ID})
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+
+	// status only changes through verification and block/unblock, which are audited,
+	// users can't hand themselves another role, and email and username have their own
+	// verified flows with history
+	body.Status = current.Status
+	if GetUserType(ctx) == "user" {
+		body.UserRole = current.UserRole
+		body.Email = current.Email
+		body.UserName = current.UserName
 	}
 
 	if body.Password != "" {
@@ -189,11 +204,23 @@ func (h *Handler) DeleteUser(ctx *gin.Context) {
 
 	req.ID = ctx.Param("id")
 
-	if ctx.GetHeader("user_type") == "user" {
-		req.ID = ctx.GetHeader("sub")
+	// users don't delete their account right away, it is scheduled with a grace period
+	if GetUserType(ctx) == "user" {
+		h.scheduleAccountDeletion(ctx, GetUserID(ctx))
+		return
+	}
+
+	// sessions are removed by ON DELETE CASCADE which bypasses the session cache,
+	// deactivate them through the repo first so they stop working right away
+	_, err := h.UseCase.SessionRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{{Column: "user_id", Type: "eq", Value: req.ID}},
+		Items:  []entity.UpdateFieldItem{{Column: "is_active", Value: "false"}},
+	})
+	if h.HandleDbError(ctx, err, "Error deactivating user sessions") {
+		return
 	}
 
-	err := h.UseCase.UserRepo.Delete(ctx, req)
+	err = h.UseCase.UserRepo.Delete(ctx, req)
 	if h.HandleDbError(ctx, err, "Error deleting user") {
 		return
 	}
@@ -227,8 +254,8 @@ func (h *Handler) UploadProfilePic(ctx *gin.Context) {
 		return
 	}
 
-	if ctx.GetHeader("user_type") == "user" || ctx.GetHeader("user_type") == "admin" {
-		id.ID = ctx.GetHeader("sub")
+	if GetUserType(ctx) == "user" || GetUserType(ctx) == "admin" {
+		id.ID = GetUserID(ctx)
 	}
 
 	user, err := h.UseCase.UserRepo.GetSingle(ctx, id)
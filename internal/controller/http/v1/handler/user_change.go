@@ -0,0 +1,332 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/Akorm0181/yelp/pkg/etc"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+)
+
+func emailChangeKey(userID string) string {
+	return fmt.Sprintf("email-change-%s", userID)
+}
+
+// ChangeMyEmail godoc
+// @Router /user/me/email [post]
+// @Summary Change my email address
+// @Description Sends a code to the new address, the change is applied once it is confirmed
+// @Security BearerAuth
+// @Tags user
+// @Accept  json
+// @Produce  json
+// @Param body body entity.EmailChangeRequest true "New email"
+// @Success 200 {object} entity.SuccessResponse
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) ChangeMyEmail(ctx *gin.Context) {
+	var (
+		body entity.EmailChangeRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	body.NewEmail = strings.TrimSpace(body.NewEmail)
+	if err != nil || !strings.Contains(body.NewEmail, "@") {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: GetUserID(ctx)})
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+
+	if strings.EqualFold(user.Email, body.NewEmail) {
+		h.ReturnError(ctx, config.ErrorBadRequest, "This is already your email address", http.StatusBadRequest)
+		return
+	}
+
+	if !h.emailAvailable(ctx, body.NewEmail, user.ID) {
+		return
+	}
+
+	err = h.Redis.Set(ctx, emailChangeKey(user.ID), body.NewEmail, int(config.OtpExpireTime.Seconds()))
+	if err != nil {
+		h.ReturnError(ctx, config.ErrorInternalServer, "Oops, something went wrong!!!", http.StatusInternalServerError)
+		return
+	}
+
+	err = h.sendOtp(ctx, otpEmailChange, body.NewEmail, etc.GenerateEmailChangeOtpBody)
+	if h.HandleOtpSendError(ctx, err) {
+		return
+	}
+
+	ctx.JSON(200, entity.SuccessResponse{
+		Message: "Code sent to the new email address",
+	})
+}
+
+// ConfirmMyEmail godoc
+// @Router /user/me/email/confirm [post]
+// @Summary Confirm my new email address
+// @Description Applies the pending email change and notifies the previous address
+// @Security BearerAuth
+// @Tags user
+// @Accept  json
+// @Produce  json
+// @Param body body entity.EmailChangeConfirmRequest true "Otp"
+// @Success 200 {object} entity.User
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) ConfirmMyEmail(ctx *gin.Context) {
+	var (
+		body entity.EmailChangeConfirmRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || body.Otp == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	userID := GetUserID(ctx)
+
+	newEmail, err := h.Redis.Get(ctx, emailChangeKey(userID))
+	if err != nil || newEmail == "" {
+		h.ReturnError(ctx, config.ErrorOtpExpired, "No pending email change, request a new one", http.StatusBadRequest)
+		return
+	}
+
+	if !h.checkOtp(ctx, otpEmailChange, newEmail, body.Otp) {
+		return
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: userID})
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+
+	// the address could have been taken while the code was on its way
+	if !h.emailAvailable(ctx, newEmail, userID) {
+		return
+	}
+
+	_, err = h.UseCase.UserRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{{Column: "id", Type: "eq", Value: userID}},
+		Items: []entity.UpdateFieldItem{
+			{Column: "email", Value: newEmail},
+			{Column: "updated_at", Value: "now()"},
+		},
+	})
+	if h.HandleDbError(ctx, err, "Error updating email") {
+		return
+	}
+
+	_ = h.Redis.Del(ctx, emailChangeKey(userID))
+
+	_, err = h.UseCase.UserChangeRepo.Create(ctx, entity.UserChange{
+		UserID:   userID,
+		Kind:     "email",
+		OldValue: user.Email,
+		NewValue: newEmail,
+	})
+	if err != nil {
+		h.Logger.Error(err, "Error saving email change history")
+	}
+
+	h.sendEmailChangedNotice(user.Email, newEmail)
+
+	user.Email = newEmail
+	user.Password = ""
+
+	ctx.JSON(200, user)
+}
+
+// emailAvailable writes a conflict response and returns false when another user has the email.
+func (h *Handler) emailAvailable(ctx *gin.Context, email, userID string) bool {
+	other, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{Email: email})
+	if err == nil && other.ID != userID {
+		h.ReturnError(ctx, config.ErrorConflict, "Email address is already in use", http.StatusBadRequest)
+		return false
+	}
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		h.HandleDbError(ctx, err, "Error checking email")
+		return false
+	}
+
+	return true
+}
+
+// sendEmailChangedNotice tells the previous address about the change. The change is already
+// applied, so a failure is only logged.
+func (h *Handler) sendEmailChangedNotice(oldEmail, newEmail string) {
+	emailBody, err := etc.GenerateEmailChangedNoticeBody(newEmail)
+	if err != nil {
+		h.Logger.Error(err, "Error generating email change notice")
+		return
+	}
+
+	err = etc.SendEmail(h.Config.Gmail.Host, h.Config.Gmail.Port, h.Config.Gmail.Email, h.Config.Gmail.EmailPass, oldEmail, emailBody)
+	if err != nil {
+		h.Logger.Error(err, "Error sending email change notice")
+	}
+}
+
+// ChangeMyUsername godoc
+// @Router /user/me/username [put]
+// @Summary Change my username
+// @Description Usernames can be changed once per cooldown period. The previous username stays reserved for you for a while before anyone else can take it.
+// @Security BearerAuth
+// @Tags user
+// @Accept  json
+// @Produce  json
+// @Param body body entity.UsernameChangeRequest true "New username"
+// @Success 200 {object} entity.User
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) ChangeMyUsername(ctx *gin.Context) {
+	var (
+		body entity.UsernameChangeRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	body.UserName = strings.TrimSpace(body.UserName)
+	if err != nil || body.UserName == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body", 400)
+		return
+	}
+
+	userID := GetUserID(ctx)
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: userID})
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return
+	}
+
+	if user.UserName == body.UserName {
+		h.ReturnError(ctx, config.ErrorBadRequest, "This is already your username", http.StatusBadRequest)
+		return
+	}
+
+	last, err := h.UseCase.UserChangeRepo.GetLast(ctx, userID, "username")
+	if err == nil {
+		changedAt, _ := time.Parse(time.RFC3339, last.CreatedAt)
+		if next := changedAt.Add(config.UsernameCooldown); time.Now().Before(next) {
+			h.ReturnError(ctx, config.ErrorChangeCooldown, "You can change your username again after "+next.Format(time.RFC3339), http.StatusTooManyRequests)
+			return
+		}
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		h.HandleDbError(ctx, err, "Error getting username history")
+		return
+	}
+
+	if !h.usernameAvailable(ctx, body.UserName, userID) {
+		return
+	}
+
+	_, err = h.UseCase.UserRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{{Column: "id", Type: "eq", Value: userID}},
+		Items: []entity.UpdateFieldItem{
+			{Column: "username", Value: body.UserName},
+			{Column: "updated_at", Value: "now()"},
+		},
+	})
+	if h.HandleDbError(ctx, err, "Error updating username") {
+		return
+	}
+
+	_, err = h.UseCase.UserChangeRepo.Create(ctx, entity.UserChange{
+		UserID:        userID,
+		Kind:          "username",
+		OldValue:      user.UserName,
+		NewValue:      body.UserName,
+		ReservedUntil: time.Now().Add(config.UsernameReservedTime).Format(time.RFC3339),
+	})
+	if err != nil {
+		h.Logger.Error(err, "Error saving username change history")
+	}
+
+	user.UserName = body.UserName
+	user.Password = ""
+
+	ctx.JSON(200, user)
+}
+
+// usernameAvailable writes a conflict response and returns false when the username belongs
+// to another user or is still reserved for its previous owner. userID may be empty.
+func (h *Handler) usernameAvailable(ctx *gin.Context, username, userID string) bool {
+	other, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{UserName: username})
+	if err == nil && other.ID != userID {
+		h.ReturnError(ctx, config.ErrorConflict, "Username is already taken", http.StatusBadRequest)
+		return false
+	}
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		h.HandleDbError(ctx, err, "Error checking username")
+		return false
+	}
+
+	reserved, err := h.UseCase.UserChangeRepo.UsernameReserved(ctx, username, userID)
+	if h.HandleDbError(ctx, err, "Error checking username") {
+		return false
+	}
+
+	if reserved {
+		h.ReturnError(ctx, config.ErrorConflict, "Username is not available yet", http.StatusBadRequest)
+		return false
+	}
+
+	return true
+}
+
+// GetMyChangeHistory godoc
+// @Router /user/me/change-history [get]
+// @Summary Get my email and username changes
+// @Description Get my email and username changes
+// @Security BearerAuth
+// @Tags user
+// @Accept  json
+// @Produce  json
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Param kind query string false "email or username"
+// @Success 200 {object} entity.UserChangeList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetMyChangeHistory(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+	kind := ctx.DefaultQuery("kind", "")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+	req.Filters = append(req.Filters, entity.Filter{
+		Column: "user_id",
+		Type:   "eq",
+		Value:  GetUserID(ctx),
+	})
+	if kind != "" {
+		req.Filters = append(req.Filters, entity.Filter{
+			Column: "kind",
+			Type:   "eq",
+			Value:  kind,
+		})
+	}
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "created_at",
+		Order:  "desc",
+	})
+
+	history, err := h.UseCase.UserChangeRepo.GetList(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting change history") {
+		return
+	}
+
+	ctx.JSON(200, history)
+}
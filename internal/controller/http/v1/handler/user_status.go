@@ -0,0 +1,255 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/gin-gonic/gin"
+)
+
+func userStatusKey(userID string) string {
+	return fmt.Sprintf("user-status-%s", userID)
+}
+
+// effectiveStatus treats a block whose expiry has passed as lifted.
+func effectiveStatus(user entity.User) string {
+	if user.Status == "blocked" && user.BlockedUntil != "" {
+		blockedUntil, err := time.Parse(time.RFC3339, user.BlockedUntil)
+		if err == nil && time.Now().After(blockedUntil) {
+			return "active"
+		}
+	}
+
+	return user.Status
+}
+
+// checkAccountStatus only lets active accounts sign in. An expired block is lifted here and
+// recorded in the audit log. Writes the error response itself and returns false otherwise.
+func (h *Handler) checkAccountStatus(ctx *gin.Context, user *entity.User) bool {
+	switch effectiveStatus(*user) {
+	case "active":
+		if user.Status == "blocked" {
+			_, err := h.UseCase.UserRepo.ChangeStatus(ctx, entity.UserStatusChange{
+				UserID: user.ID,
+				Status: "active",
+				Reason: "block expired",
+			})
+			if err != nil {
+				h.Logger.Error(err, "Error lifting expired block")
+			}
+			user.Status = "active"
+			_ = h.Redis.Del(ctx, userStatusKey(user.ID))
+		}
+		return true
+	case "blocked":
+		message := "Account is blocked"
+		if user.BlockedUntil != "" {
+			message += " until " + user.BlockedUntil
+		}
+		h.ReturnError(ctx, config.ErrorUserBlocked, message, http.StatusForbidden)
+	case "inverify":
+		h.ReturnError(ctx, config.ErrorNotVerified, "Email address is not verified yet", http.StatusForbidden)
+	default:
+		h.ReturnError(ctx, config.ErrorForbidden, "Account is not active", http.StatusForbidden)
+	}
+
+	return false
+}
+
+// accountActive is the per request status check of AuthMiddleware. The status is cached in
+// redis for config.UserStatusCacheTTL, block and unblock drop the cached value.
+func (h *Handler) accountActive(ctx *gin.Context, userID string) bool {
+	status, err := h.Redis.Get(ctx, userStatusKey(userID))
+	if err == nil && status != "" {
+		return status == "active"
+	}
+
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: userID})
+	if err != nil {
+		h.Logger.Error(err, "Error getting user status")
+		return false
+	}
+
+	status = effectiveStatus(user)
+
+	err = h.Redis.Set(ctx, userStatusKey(userID), status, int(config.UserStatusCacheTTL.Seconds()))
+	if err != nil {
+		h.Logger.Error(err, "Error caching user status")
+	}
+
+	return status == "active"
+}
+
+// BlockUser godoc
+// @Router /user/{id}/block [post]
+// @Summary Block a user
+// @Description Blocks the user until blocked_until, or for good when it is empty, and signs out all of their sessions
+// @Security BearerAuth
+// @Tags user
+// @Accept  json
+// @Produce  json
+// @Param id path string true "User ID"
+// @Param body body entity.UserStatusRequest true "Reason and optional expiry"
+// @Success 200 {object} entity.UserStatusAudit
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) BlockUser(ctx *gin.Context) {
+	var (
+		body entity.UserStatusRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || body.Reason == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body, reason is required", 400)
+		return
+	}
+
+	if body.BlockedUntil != "" {
+		blockedUntil, err := time.Parse(time.RFC3339, body.BlockedUntil)
+		if err != nil || blockedUntil.Before(time.Now()) {
+			h.ReturnError(ctx, config.ErrorBadRequest, "blocked_until must be a future RFC3339 time", http.StatusBadRequest)
+			return
+		}
+	}
+
+	user, ok := h.statusChangeTarget(ctx)
+	if !ok {
+		return
+	}
+
+	audit, err := h.UseCase.UserRepo.ChangeStatus(ctx, entity.UserStatusChange{
+		UserID:       user.ID,
+		ActorID:      GetUserID(ctx),
+		Status:       "blocked",
+		Reason:       body.Reason,
+		BlockedUntil: body.BlockedUntil,
+	})
+	if h.HandleDbError(ctx, err, "Error blocking user") {
+		return
+	}
+
+	_ = h.Redis.Del(ctx, userStatusKey(user.ID))
+
+	_, err = h.UseCase.SessionRepo.UpdateField(ctx, entity.UpdateFieldRequest{
+		Filter: []entity.Filter{{Column: "user_id", Type: "eq", Value: user.ID}},
+		Items:  []entity.UpdateFieldItem{{Column: "is_active", Value: "false"}},
+	})
+	if h.HandleDbError(ctx, err, "Error revoking sessions") {
+		return
+	}
+
+	ctx.JSON(200, audit)
+}
+
+// UnblockUser godoc
+// @Router /user/{id}/unblock [post]
+// @Summary Unblock a user
+// @Description Unblock a user
+// @Security BearerAuth
+// @Tags user
+// @Accept  json
+// @Produce  json
+// @Param id path string true "User ID"
+// @Param body body entity.UserStatusRequest true "Reason"
+// @Success 200 {object} entity.UserStatusAudit
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) UnblockUser(ctx *gin.Context) {
+	var (
+		body entity.UserStatusRequest
+	)
+
+	err := ctx.ShouldBindJSON(&body)
+	if err != nil || body.Reason == "" {
+		h.ReturnError(ctx, config.ErrorBadRequest, "Invalid request body, reason is required", 400)
+		return
+	}
+
+	user, ok := h.statusChangeTarget(ctx)
+	if !ok {
+		return
+	}
+
+	if user.Status != "blocked" {
+		h.ReturnError(ctx, config.ErrorConflict, "User is not blocked", http.StatusBadRequest)
+		return
+	}
+
+	audit, err := h.UseCase.UserRepo.ChangeStatus(ctx, entity.UserStatusChange{
+		UserID:  user.ID,
+		ActorID: GetUserID(ctx),
+		Status:  "active",
+		Reason:  body.Reason,
+	})
+	if h.HandleDbError(ctx, err, "Error unblocking user") {
+		return
+	}
+
+	_ = h.Redis.Del(ctx, userStatusKey(user.ID))
+
+	ctx.JSON(200, audit)
+}
+
+// statusChangeTarget loads the user from the path. Admins can't change their own status, and
+// only a superadmin can change the status of another admin.
+func (h *Handler) statusChangeTarget(ctx *gin.Context) (entity.User, bool) {
+	user, err := h.UseCase.UserRepo.GetSingle(ctx, entity.UserSingleRequest{ID: ctx.Param("id")})
+	if h.HandleDbError(ctx, err, "Error getting user") {
+		return entity.User{}, false
+	}
+
+	if user.ID == GetUserID(ctx) {
+		h.ReturnError(ctx, config.ErrorForbidden, "You can't change your own status", http.StatusForbidden)
+		return entity.User{}, false
+	}
+
+	if user.UserType == "admin" && GetUserRole(ctx) != "superadmin" {
+		h.ReturnError(ctx, config.ErrorForbidden, "Only a superadmin can change the status of an admin", http.StatusForbidden)
+		return entity.User{}, false
+	}
+
+	return user, true
+}
+
+// GetUserStatusHistory godoc
+// @Router /user/{id}/status-history [get]
+// @Summary Get the status changes of a user
+// @Description Get the status changes of a user
+// @Security BearerAuth
+// @Tags user
+// @Accept  json
+// @Produce  json
+// @Param id path string true "User ID"
+// @Param page query number true "page"
+// @Param limit query number true "limit"
+// @Success 200 {object} entity.UserStatusAuditList
+// @Failure 400 {object} entity.ErrorResponse
+func (h *Handler) GetUserStatusHistory(ctx *gin.Context) {
+	var (
+		req entity.GetListFilter
+	)
+
+	page := ctx.DefaultQuery("page", "1")
+	limit := ctx.DefaultQuery("limit", "10")
+
+	req.Page, _ = strconv.Atoi(page)
+	req.Limit, _ = strconv.Atoi(limit)
+	req.Filters = append(req.Filters, entity.Filter{
+		Column: "user_id",
+		Type:   "eq",
+		Value:  ctx.Param("id"),
+	})
+	req.OrderBy = append(req.OrderBy, entity.OrderBy{
+		Column: "created_at",
+		Order:  "desc",
+	})
+
+	history, err := h.UseCase.UserRepo.GetStatusHistory(ctx, req)
+	if h.HandleDbError(ctx, err, "Error getting status history") {
+		return
+	}
+
+	ctx.JSON(200, history)
+}
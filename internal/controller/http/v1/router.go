@@ -15,7 +15,9 @@ import (
 	_ "github.com/Akorm0181/yelp/docs"
 	"github.com/Akorm0181/yelp/internal/controller/http/v1/handler"
 	"github.com/Akorm0181/yelp/internal/usecase"
+	"github.com/Akorm0181/yelp/pkg/jwt"
 	"github.com/Akorm0181/yelp/pkg/logger"
+	"github.com/Akorm0181/yelp/pkg/ratelimit"
 	rediscache "github.com/golanguzb70/redis-cache"
 )
 
@@ -28,12 +30,12 @@ import (
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
-func NewRouter(engine *gin.Engine, l *logger.Logger, config *config.Config, useCase *usecase.UseCase, redis rediscache.RedisCache) {
+func NewRouter(engine *gin.Engine, l *logger.Logger, config *config.Config, useCase *usecase.UseCase, redis rediscache.RedisCache, limiter *ratelimit.Limiter, keys *jwt.KeySet) {
 	// Options
 	engine.Use(gin.Logger())
 	engine.Use(gin.Recovery())
 
-	handlerV1 := handler.NewHandler(l, config, useCase, redis)
+	handlerV1 := handler.NewHandler(l, config, useCase, redis, limiter, keys)
 
 	// Initialize Casbin enforcer
 	e := casbin.NewEnforcer("config/rbac.conf", "config/policy.csv")
@@ -46,6 +48,9 @@ func NewRouter(engine *gin.Engine, l *logger.Logger, config *config.Config, useC
 	// K8s probe
 	engine.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
 
+	// Public keys for verifying access tokens
+	engine.GET("/.well-known/jwks.json", handlerV1.GetJWKS)
+
 	// Prometheus metrics
 	engine.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
@@ -60,11 +65,23 @@ func NewRouter(engine *gin.Engine, l *logger.Logger, config *config.Config, useC
 		user.PUT("/", handlerV1.UpdateUser)
 		user.DELETE("/:id", handlerV1.DeleteUser)
 		user.POST("/upload", handlerV1.UploadProfilePic)
+		user.POST("/me/delete", handlerV1.RequestAccountDeletion)
+		user.GET("/me/export", handlerV1.ExportMyData)
+		user.POST("/me/email", handlerV1.RateLimit("otp"), handlerV1.ChangeMyEmail)
+		user.POST("/me/email/confirm", handlerV1.RateLimit("otp"), handlerV1.ConfirmMyEmail)
+		user.PUT("/me/username", handlerV1.ChangeMyUsername)
+		user.GET("/me/change-history", handlerV1.GetMyChangeHistory)
+		user.POST("/:id/block", handlerV1.BlockUser)
+		user.POST("/:id/unblock", handlerV1.UnblockUser)
+		user.GET("/:id/status-history", handlerV1.GetUserStatusHistory)
 	}
 
 	session := v1.Group("/session")
 	{
 		session.GET("/list", handlerV1.GetSessions)
+		session.GET("/me", handlerV1.GetMySessions)
+		session.DELETE("/me/others", handlerV1.RevokeMyOtherSessions)
+		session.DELETE("/me/:id", handlerV1.RevokeMySession)
 		session.GET("/:id", handlerV1.GetSession)
 		session.PUT("/", handlerV1.UpdateSession)
 		session.DELETE("/:id", handlerV1.DeleteSession)
@@ -73,9 +90,34 @@ func NewRouter(engine *gin.Engine, l *logger.Logger, config *config.Config, useC
 	auth := v1.Group("/auth")
 	{
 		auth.POST("/logout", handlerV1.Logout)
-		auth.POST("/register", handlerV1.Register)
-		auth.POST("/verify-email", handlerV1.VerifyEmail)
-		auth.POST("/login", handlerV1.Login)
+		auth.POST("/register", handlerV1.RateLimit("register"), handlerV1.Register)
+		auth.POST("/verify-email", handlerV1.RateLimit("otp"), handlerV1.VerifyEmail)
+		auth.POST("/resend-otp", handlerV1.RateLimit("otp"), handlerV1.ResendOtp)
+		auth.POST("/login", handlerV1.RateLimit("login"), handlerV1.Login)
+		auth.POST("/oidc", handlerV1.RateLimit("login"), handlerV1.OIDCLogin)
+		auth.POST("/refresh", handlerV1.RefreshToken)
+		auth.POST("/forgot-password", handlerV1.RateLimit("otp"), handlerV1.ForgotPassword)
+		auth.POST("/reset-password", handlerV1.RateLimit("otp"), handlerV1.ResetPassword)
+		auth.POST("/2fa/setup", handlerV1.SetupMfa)
+		auth.POST("/2fa/enable", handlerV1.EnableMfa)
+		auth.POST("/2fa/verify", handlerV1.RateLimit("otp"), handlerV1.VerifyMfa)
+		auth.POST("/2fa/disable", handlerV1.DisableMfa)
+	}
+
+	ownerRequest := v1.Group("/owner-request")
+	{
+		ownerRequest.POST("/", handlerV1.CreateOwnerRequest)
+		ownerRequest.GET("/list", handlerV1.GetOwnerRequests)
+		ownerRequest.GET("/:id", handlerV1.GetOwnerRequest)
+		ownerRequest.POST("/:id/approve", handlerV1.ApproveOwnerRequest)
+		ownerRequest.POST("/:id/reject", handlerV1.RejectOwnerRequest)
+	}
+
+	apiKey := v1.Group("/api-key")
+	{
+		apiKey.POST("/", handlerV1.CreateApiKey)
+		apiKey.GET("/list", handlerV1.GetMyApiKeys)
+		apiKey.DELETE("/:id", handlerV1.RevokeApiKey)
 	}
 
 	business := v1.Group("/business")
@@ -85,16 +127,50 @@ func NewRouter(engine *gin.Engine, l *logger.Logger, config *config.Config, useC
 		business.GET("/:id", handlerV1.GetBusiness)
 		business.PUT("/", handlerV1.UpdateBusiness)
 		business.DELETE("/:id", handlerV1.DeleteBusiness)
+		business.POST("/:id/restore", handlerV1.RestoreBusiness)
 		business.POST("/upload/:id", handlerV1.UploadBusinessPic)
+		business.POST("/:id/attachment", handlerV1.UploadBusinessAttachments)
+		business.PUT("/:id/attachment/order", handlerV1.ReorderBusinessAttachments)
+		business.PUT("/:id/attachment/:attachment_id", handlerV1.UpdateBusinessAttachment)
+		business.PUT("/:id/attachment/:attachment_id/cover", handlerV1.SetBusinessCover)
+		business.DELETE("/:id/attachment/:attachment_id", handlerV1.DeleteBusinessAttachment)
+		business.GET("/:id/menu", handlerV1.GetBusinessMenu)
+		business.GET("/:id/menu/versions", handlerV1.GetBusinessMenuVersions)
+		business.GET("/:id/menu/versions/:version", handlerV1.GetBusinessMenuVersion)
+		business.GET("/:id/menu/draft", handlerV1.GetBusinessMenuDraft)
+		business.PUT("/:id/menu/draft", handlerV1.SaveBusinessMenuDraft)
+		business.POST("/:id/menu/draft/publish", handlerV1.PublishBusinessMenu)
+	}
+
+	businessClaim := v1.Group("/business-claim")
+	{
+		businessClaim.POST("/", handlerV1.CreateBusinessClaim)
+		businessClaim.GET("/list", handlerV1.GetBusinessClaims)
+		businessClaim.GET("/:id", handlerV1.GetBusinessClaim)
+		businessClaim.POST("/:id/evidence", handlerV1.UploadBusinessClaimEvidence)
+		businessClaim.POST("/:id/approve", handlerV1.ApproveBusinessClaim)
+		businessClaim.POST("/:id/reject", handlerV1.RejectBusinessClaim)
 	}
 
 	business_cat := v1.Group("/business-category")
 	{
 		business_cat.POST("/", handlerV1.CreateBusinessCategory)
 		business_cat.GET("/list", handlerV1.GetBusinessCategories)
+		business_cat.GET("/tree", handlerV1.GetBusinessCategoryTree)
 		business_cat.GET("/:id", handlerV1.GetBusinessCategory)
 		business_cat.PUT("/", handlerV1.UpdateBusinessCategory)
 		business_cat.DELETE("/:id", handlerV1.DeleteBusinessCategory)
+		business_cat.GET("/:id/attributes", handlerV1.GetCategoryAttributes)
+		business_cat.PUT("/:id/attributes", handlerV1.SetCategoryAttributes)
+	}
+
+	businessAttribute := v1.Group("/business-attribute")
+	{
+		businessAttribute.POST("/", handlerV1.CreateBusinessAttribute)
+		businessAttribute.GET("/list", handlerV1.GetBusinessAttributes)
+		businessAttribute.GET("/:id", handlerV1.GetBusinessAttribute)
+		businessAttribute.PUT("/", handlerV1.UpdateBusinessAttribute)
+		businessAttribute.DELETE("/:id", handlerV1.DeleteBusinessAttribute)
 	}
 
 	review := v1.Group("/review")
@@ -104,6 +180,7 @@ func NewRouter(engine *gin.Engine, l *logger.Logger, config *config.Config, useC
 		review.GET("/:id", handlerV1.GetReview)
 		review.PUT("/", handlerV1.UpdateReview)
 		review.DELETE("/:id", handlerV1.DeleteReview)
+		review.POST("/:id/restore", handlerV1.RestoreReview)
 	}
 
 	report := v1.Group("/report")
@@ -138,6 +215,7 @@ func NewRouter(engine *gin.Engine, l *logger.Logger, config *config.Config, useC
 		event.GET("/list", handlerV1.GetEvents)
 		event.GET("/:id", handlerV1.GetEvent)
 		event.DELETE("/:id", handlerV1.DeleteEvent)
+		event.POST("/:id/restore", handlerV1.RestoreEvent)
 		event.POST("/add-participant", handlerV1.AddParticipant)
 		event.DELETE("/remove-participant", handlerV1.RemoveParticipant)
 		event.GET("/:id/participants", handlerV1.GetParticipants)
@@ -174,4 +252,6 @@ func NewRouter(engine *gin.Engine, l *logger.Logger, config *config.Config, useC
 		follower.POST("/", handlerV1.FollowUnfollow)
 		follower.GET("/list", handlerV1.GetFollowers)
 	}
+
+	v1.GET("/search", handlerV1.Search)
 }
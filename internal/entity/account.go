@@ -0,0 +1,25 @@
+package entity
+
+type AccountDeletionResponse struct {
+	Message     string `json:"message"`
+	DeleteAfter string `json:"delete_after"`
+}
+
+type AccountExportFollow struct {
+	UserID    string `json:"user_id"`
+	UserName  string `json:"user_name"`
+	FullName  string `json:"full_name"`
+	CreatedAt string `json:"created_at"`
+}
+
+// AccountExport is everything stored about a user, served by the "download my data" endpoint.
+type AccountExport struct {
+	ExportedAt    string                `json:"exported_at"`
+	Profile       User                  `json:"profile"`
+	Reviews       []Review              `json:"reviews"`
+	Bookmarks     []Bookmark            `json:"bookmarks"`
+	Following     []AccountExportFollow `json:"following"`
+	Followers     []AccountExportFollow `json:"followers"`
+	Events        []EventParticipant    `json:"events"`
+	Notifications []Notification        `json:"notifications"`
+}
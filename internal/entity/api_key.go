@@ -0,0 +1,39 @@
+package entity
+
+// ApiKey is a long lived credential for integrations. Only the hash of the key is stored,
+// the key itself is returned once when it is created.
+type ApiKey struct {
+	ID          string   `json:"id"`
+	UserID      string   `json:"user_id"`
+	Name        string   `json:"name"`
+	Prefix      string   `json:"prefix"`
+	KeyHash     string   `json:"-"`
+	Scopes      []string `json:"scopes"`
+	LastUsedAt  string   `json:"last_used_at"`
+	ExpiresAt   string   `json:"expires_at"`
+	RevokedAt   string   `json:"revoked_at"`
+	SuspendedAt string   `json:"suspended_at"` // while the account of the owner is scheduled for deletion
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+}
+
+type ApiKeySingleRequest struct {
+	ID      string `json:"id"`
+	KeyHash string `json:"key_hash"`
+}
+
+type ApiKeyList struct {
+	Items []ApiKey `json:"items"`
+	Count int      `json:"count"`
+}
+
+type CreateApiKeyRequest struct {
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes"`
+	ExpiresAt string   `json:"expires_at"` // optional, RFC3339
+}
+
+type CreateApiKeyResponse struct {
+	ApiKey
+	Key string `json:"key"`
+}
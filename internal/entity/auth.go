@@ -20,3 +20,62 @@ type VerifyEmail struct {
 	Otp      string `json:"otp"`
 	Platform string `json:"platform"`
 }
+
+type ResendOtpRequest struct {
+	Email string `json:"email"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type TokenResponse struct {
+	AccessToken  string  `json:"access_token"`
+	RefreshToken string  `json:"refresh_token"`
+	Session      Session `json:"session"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Email       string `json:"email"`
+	Otp         string `json:"otp"`
+	NewPassword string `json:"new_password"`
+}
+
+// Principal is the authenticated caller of a request, built from the access token claims
+// or, for integrations, from an api key.
+type Principal struct {
+	UserID    string   `json:"user_id"`
+	UserRole  string   `json:"user_role"`
+	UserType  string   `json:"user_type"`
+	SessionID string   `json:"session_id"`
+	Platform  string   `json:"platform"`
+	ApiKeyID  string   `json:"api_key_id"`
+	Scopes    []string `json:"scopes"`
+}
+
+type OIDCLoginRequest struct {
+	Provider string `json:"provider"`
+	IDToken  string `json:"id_token"`
+	Nonce    string `json:"nonce"`
+	Platform string `json:"platform"`
+}
+
+// UserIdentity links a user to an account at an external identity provider.
+type UserIdentity struct {
+	ID        string `json:"id"`
+	UserID    string `json:"user_id"`
+	Provider  string `json:"provider"`
+	Subject   string `json:"subject"`
+	Email     string `json:"email"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type UserIdentitySingleRequest struct {
+	Provider string `json:"provider"`
+	Subject  string `json:"subject"`
+}
@@ -1,40 +1,94 @@
 package entity
 
+import "errors"
+
+// ErrCategoryCycle is returned when a category would be moved below itself or one of its children.
+var ErrCategoryCycle = errors.New("category can't be moved below itself")
+
 type ContactInfo struct {
 	Phone   string `json:"phone"`
 	Email   string `json:"email"`
 	Website string `json:"website"`
 }
 
-type HoursOfOperation struct {
-	Monday    string `json:"monday"`
-	Tuesday   string `json:"tuesday"`
-	Wednesday string `json:"wednesday"`
-	Thursday  string `json:"thursday"`
-	Friday    string `json:"friday"`
-	Saturday  string `json:"saturday"`
-	Sunday    string `json:"sunday"`
+// OpeningHours is one range of the weekly hours, a day can have several. A range whose
+// closes_at is at or before opens_at runs past midnight, 00:00-00:00 is open all day.
+type OpeningHours struct {
+	Weekday  int    `json:"weekday"`   // 0 is Sunday
+	OpensAt  string `json:"opens_at"`  // HH:MM in the business time zone
+	ClosesAt string `json:"closes_at"` // HH:MM
+}
+
+// SpecialHours replace the weekly hours on a date, e.g. a holiday. Either Closed is set or
+// the date has one or more ranges.
+type SpecialHours struct {
+	Date     string `json:"date"` // YYYY-MM-DD
+	Closed   bool   `json:"closed"`
+	OpensAt  string `json:"opens_at"`
+	ClosesAt string `json:"closes_at"`
+	Note     string `json:"note"`
 }
 
 type Business struct {
 	ID               string               `json:"id"`
 	Name             string               `json:"name"`
 	Description      string               `json:"description"`
-	CategoryID       string               `json:"category_id"`
+	CategoryIDs      []string             `json:"category_ids"` // the first one is the primary category
+	Categories       []BusinessCategory   `json:"categories"`
 	Address          string               `json:"address"`
 	Attachments      []BusinessAttachment `json:"attachments"`
 	Latitude         float64              `json:"latitude"`
 	Longitude        float64              `json:"longitude"`
 	ContactInfo      ContactInfo          `json:"contact_info"`
-	HoursOfOperation HoursOfOperation     `json:"hours_of_operation"`
+	PriceRange       string               `json:"price_range"` // $ to $$$$, empty when unknown
+	Attributes       map[string]string    `json:"attributes"`  // attribute key to value
+	TimeZone         string               `json:"time_zone"`   // IANA name, UTC by default
+	HoursOfOperation []OpeningHours       `json:"hours_of_operation"`
+	SpecialHours     []SpecialHours       `json:"special_hours"` // upcoming dates only
+	OpenNow          bool                 `json:"open_now"`
+	RatingAvg        float64              `json:"rating_avg"`
+	ReviewCount      int                  `json:"review_count"`
+	RatingHistogram  []int                `json:"rating_histogram"` // reviews per star, index 0 is 1 star
 	OwnerID          string               `json:"owner_id"`
+	ClaimState       string               `json:"claim_state"` // unclaimed, claimed, verified
+	VerifiedAt       string               `json:"verified_at"`
+	CreatedBy        string               `json:"created_by"`
+	DistanceKm       *float64             `json:"distance_km,omitempty"` // only set by a search around a point
 	CreatedAt        string               `json:"created_at"`
 	UpdatedAt        string               `json:"updated_at"`
 }
 
 type BusinessList struct {
-	Items []Business `json:"businesses"`
-	Count int        `json:"count"`
+	Items  []Business       `json:"businesses"`
+	Count  int              `json:"count"`
+	Facets []AttributeFacet `json:"facets"` // attribute values over the whole result, not just the page
+}
+
+// BusinessListRequest is a GetListFilter with the business specific search options.
+type BusinessListRequest struct {
+	GetListFilter
+	Near        *GeoRadius        `json:"near"`
+	Box         *GeoBox           `json:"box"`
+	OpenAt      string            `json:"open_at"`      // RFC3339, only businesses open at that time
+	PriceRanges []int             `json:"price_ranges"` // 1 ($) to 4 ($$$$), any of them matches
+	Attributes  map[string]string `json:"attributes"`   // attribute key to the value it must have
+	CategoryID  string            `json:"category_id"`  // the category or any category below it
+}
+
+// GeoRadius is a point to measure distances from. With RadiusKm 0 nothing is filtered out,
+// the distance is only reported and can be sorted by.
+type GeoRadius struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	RadiusKm  float64 `json:"radius_km"`
+}
+
+// GeoBox is a bounding box, MinLongitude > MaxLongitude is a box crossing the antimeridian.
+type GeoBox struct {
+	MinLatitude  float64 `json:"min_latitude"`
+	MinLongitude float64 `json:"min_longitude"`
+	MaxLatitude  float64 `json:"max_latitude"`
+	MaxLongitude float64 `json:"max_longitude"`
 }
 
 type BusinessSingleRequest struct {
@@ -43,11 +97,17 @@ type BusinessSingleRequest struct {
 	CategoryID string `json:"category_id"`
 }
 
+// BusinessCategory is a node of the category tree, a category without ParentID is a root.
 type BusinessCategory struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID        string             `json:"id"`
+	ParentID  string             `json:"parent_id"`
+	Name      string             `json:"name"`
+	Slug      string             `json:"slug"`
+	Icon      string             `json:"icon"`
+	Children  []BusinessCategory `json:"children,omitempty"`  // only filled in the tree
+	Ancestors []BusinessCategory `json:"ancestors,omitempty"` // the path from the root, only filled for a single category
+	CreatedAt string             `json:"created_at"`
+	UpdatedAt string             `json:"updated_at"`
 }
 
 type BusinessCategoryList struct {
@@ -58,6 +118,7 @@ type BusinessCategoryList struct {
 type BusinessCategorySingleRequest struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
+	Slug string `json:"slug"`
 }
 
 type BusinessAttachment struct {
@@ -65,6 +126,10 @@ type BusinessAttachment struct {
 	BusinessId  string `json:"-"`
 	FilePath    string `json:"filepath"`
 	ContentType string `json:"content_type"`
+	Position    int    `json:"position"`
+	IsCover     bool   `json:"is_cover"`
+	Caption     string `json:"caption"`
+	UploadedBy  string `json:"uploaded_by"`
 	CreatedAt   string `json:"created_at"`
 	UpdatedAt   string `json:"updated_at"`
 }
@@ -76,5 +141,14 @@ type BusinessAttachmentList struct {
 
 type BusinessAttachmentMultipleInsertRequest struct {
 	BusinessId  string               `json:"business_id"`
+	UploadedBy  string               `json:"-"`
 	Attachments []BusinessAttachment `json:"attachments"`
 }
+
+type BusinessAttachmentOrderRequest struct {
+	Ids []string `json:"ids"` // every attachment of the business, in the new order
+}
+
+type BusinessAttachmentCaptionRequest struct {
+	Caption string `json:"caption"`
+}
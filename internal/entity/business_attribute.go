@@ -0,0 +1,35 @@
+package entity
+
+// BusinessAttribute is an amenity like wifi or parking. A bool attribute is "true" or "false"
+// on a business, an enum attribute one of its options.
+type BusinessAttribute struct {
+	ID        string   `json:"id"`
+	Key       string   `json:"key"`
+	Name      string   `json:"name"`
+	ValueType string   `json:"value_type"` // bool or enum
+	Options   []string `json:"options"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+type BusinessAttributeList struct {
+	Items []BusinessAttribute `json:"business_attributes"`
+	Count int                 `json:"count"`
+}
+
+// CategoryAttributesRequest replaces the attributes businesses of a category can have.
+type CategoryAttributesRequest struct {
+	AttributeIDs []string `json:"attribute_ids"`
+}
+
+// AttributeFacet counts the businesses of a result set per value of an attribute.
+type AttributeFacet struct {
+	Key    string       `json:"key"`
+	Name   string       `json:"name"`
+	Values []FacetCount `json:"values"`
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
@@ -0,0 +1,47 @@
+package entity
+
+// BusinessClaim is a business owner's request to take over a listing, reviewed by an admin.
+// Approving it moves the business to the claimant and marks it verified.
+type BusinessClaim struct {
+	ID         string                  `json:"id"`
+	BusinessID string                  `json:"business_id"`
+	UserID     string                  `json:"user_id"`
+	Phone      string                  `json:"phone"`
+	Note       string                  `json:"note"`
+	Status     string                  `json:"status"` // pending, approved, rejected
+	ReviewedBy string                  `json:"reviewed_by"`
+	ReviewNote string                  `json:"review_note"`
+	ReviewedAt string                  `json:"reviewed_at"`
+	Evidence   []BusinessClaimEvidence `json:"evidence"`
+	CreatedAt  string                  `json:"created_at"`
+	UpdatedAt  string                  `json:"updated_at"`
+}
+
+type BusinessClaimEvidence struct {
+	ID        string `json:"id"`
+	ClaimID   string `json:"-"`
+	FilePath  string `json:"filepath"`
+	CreatedAt string `json:"created_at"`
+}
+
+type BusinessClaimList struct {
+	Items []BusinessClaim `json:"items"`
+	Count int             `json:"count"`
+}
+
+type CreateBusinessClaim struct {
+	BusinessID string `json:"business_id"`
+	Phone      string `json:"phone"`
+	Note       string `json:"note"`
+}
+
+type ReviewBusinessClaim struct {
+	ReviewNote string `json:"review_note"`
+}
+
+// BusinessClaimApproval is the result of an approved claim, PreviousOwnerID is empty when the
+// business was unclaimed.
+type BusinessClaimApproval struct {
+	Claim           BusinessClaim
+	PreviousOwnerID string
+}
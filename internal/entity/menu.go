@@ -0,0 +1,52 @@
+package entity
+
+// Menu is one version of a business menu. Owners edit the draft, publishing it replaces the
+// menu customers see and archives the previous one.
+type Menu struct {
+	ID          string        `json:"id"`
+	BusinessID  string        `json:"business_id"`
+	Version     int           `json:"version"`
+	Status      string        `json:"status"` // draft, published, archived
+	CreatedBy   string        `json:"created_by"`
+	PublishedBy string        `json:"published_by"`
+	PublishedAt string        `json:"published_at"`
+	Sections    []MenuSection `json:"sections"`
+	CreatedAt   string        `json:"created_at"`
+	UpdatedAt   string        `json:"updated_at"`
+}
+
+type MenuSection struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Items       []MenuItem `json:"items"`
+}
+
+type MenuItem struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Price       float64  `json:"price"`
+	Currency    string   `json:"currency"`     // ISO 4217 code, e.g. USD
+	DietaryTags []string `json:"dietary_tags"` // one of config.DietaryTags
+	PhotoURL    string   `json:"photo_url"`
+	SoldOut     bool     `json:"sold_out"`
+}
+
+// MenuSingleRequest finds a menu of a business either by status or by version.
+type MenuSingleRequest struct {
+	BusinessID string `json:"business_id"`
+	Status     string `json:"status"`
+	Version    int    `json:"version"`
+}
+
+type MenuList struct {
+	Items []Menu `json:"menus"`
+	Count int    `json:"count"`
+}
+
+// MenuDraftRequest replaces the whole content of the draft, sections and items keep the order
+// they are sent in.
+type MenuDraftRequest struct {
+	Sections []MenuSection `json:"sections"`
+}
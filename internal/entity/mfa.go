@@ -0,0 +1,33 @@
+package entity
+
+type UserMfa struct {
+	UserID        string   `json:"user_id"`
+	Secret        string   `json:"-"`
+	IsEnabled     bool     `json:"is_enabled"`
+	RecoveryCodes []string `json:"-"` // sha256 hashes, plain codes are shown only once at setup
+	EnabledAt     string   `json:"enabled_at"`
+	CreatedAt     string   `json:"created_at"`
+	UpdatedAt     string   `json:"updated_at"`
+}
+
+type MfaSetupRequest struct {
+	MfaToken string `json:"mfa_token"`
+}
+
+type MfaSetupResponse struct {
+	Secret          string   `json:"secret"`
+	ProvisioningURI string   `json:"provisioning_uri"`
+	RecoveryCodes   []string `json:"recovery_codes"`
+}
+
+type MfaCodeRequest struct {
+	MfaToken     string `json:"mfa_token"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+type MfaLoginResponse struct {
+	MfaRequired      bool   `json:"mfa_required"`
+	MfaSetupRequired bool   `json:"mfa_setup_required"`
+	MfaToken         string `json:"mfa_token"`
+}
@@ -12,7 +12,7 @@ type OrderBy struct {
 
 type Filter struct {
 	Column string `json:"column"`
-	Type   string `json:"type"` // eq, ne, gt, gte, lt, lte, search
+	Type   string `json:"type"` // eq, ne, gt, gte, lt, lte, isnull, search
 	Value  string `json:"value"`
 }
 
@@ -0,0 +1,37 @@
+package entity
+
+import "errors"
+
+// ErrOwnerApplicantIneligible is returned when approving a request of a user that is not an
+// active plain user anymore, like an admin or a blocked or deleted account.
+var ErrOwnerApplicantIneligible = errors.New("applicant can't become a business owner")
+
+// OwnerRequest is a user's application to become a business owner, reviewed by an admin.
+type OwnerRequest struct {
+	ID           string `json:"id"`
+	UserID       string `json:"user_id"`
+	BusinessName string `json:"business_name"`
+	Phone        string `json:"phone"`
+	Note         string `json:"note"`
+	Status       string `json:"status"` // pending, approved, rejected
+	ReviewedBy   string `json:"reviewed_by"`
+	ReviewNote   string `json:"review_note"`
+	ReviewedAt   string `json:"reviewed_at"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+type OwnerRequestList struct {
+	Items []OwnerRequest `json:"items"`
+	Count int            `json:"count"`
+}
+
+type CreateOwnerRequest struct {
+	BusinessName string `json:"business_name"`
+	Phone        string `json:"phone"`
+	Note         string `json:"note"`
+}
+
+type ReviewOwnerRequest struct {
+	ReviewNote string `json:"review_note"`
+}
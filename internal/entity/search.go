@@ -0,0 +1,25 @@
+package entity
+
+type SearchRequest struct {
+	Query string   `json:"q"`
+	Types []string `json:"types"` // business, review, event
+	Page  int      `json:"page"`
+	Limit int      `json:"limit"`
+}
+
+// SearchResult is one business, review or event. Snippet is an HTML escaped excerpt with the
+// matched words wrapped in <mark></mark>.
+type SearchResult struct {
+	Type       string  `json:"type"`
+	ID         string  `json:"id"`
+	BusinessID string  `json:"business_id"`
+	Title      string  `json:"title"`
+	Snippet    string  `json:"snippet"`
+	Rank       float64 `json:"rank"`
+}
+
+type SearchResponse struct {
+	Items  []SearchResult `json:"items"`
+	Count  int            `json:"count"`
+	Counts map[string]int `json:"counts"` // matches per type
+}
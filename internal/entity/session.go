@@ -9,6 +9,7 @@ type Session struct {
 	ExpiresAt    string `json:"expires_at"`
 	LastActiveAt string `json:"last_active_at"`
 	Platform     string `json:"platform"`
+	IsCurrent    bool   `json:"is_current"`
 	CreatedAt    string `json:"created_at"`
 	UpdatedAt    string `json:"updated_at"`
 }
@@ -17,3 +18,18 @@ type SessionList struct {
 	Items []Session `json:"sessions"`
 	Count int       `json:"count"`
 }
+
+type RefreshToken struct {
+	ID        string `json:"id"`
+	SessionID string `json:"session_id"`
+	TokenHash string `json:"-"`
+	IsRevoked bool   `json:"is_revoked"`
+	UsedAt    string `json:"used_at"`
+	ExpiresAt string `json:"expires_at"`
+	CreatedAt string `json:"created_at"`
+}
+
+type RefreshTokenSingleRequest struct {
+	ID        string `json:"id"`
+	TokenHash string `json:"token_hash"`
+}
@@ -1,20 +1,23 @@
 package entity
 
 type User struct {
-	ID          string `json:"id"`
-	FullName    string `json:"full_name"`
-	UserName    string `json:"user_name"`
-	Email       string `json:"email"`
-	Password    string `json:"password"`
-	UserType    string `json:"user_type"`
-	UserRole    string `json:"user_role"`
-	Status      string `json:"status"`
-	AccessToken string `json:"access_token"`
-	ProfilePic  string `json:"profile_picture"`
-	Gender      string `json:"gender"`
-	Bio         string `json:"bio"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
+	ID           string `json:"id"`
+	FullName     string `json:"full_name"`
+	UserName     string `json:"user_name"`
+	Email        string `json:"email"`
+	Password     string `json:"password"`
+	UserType     string `json:"user_type"`
+	UserRole     string `json:"user_role"`
+	Status       string `json:"status"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ProfilePic   string `json:"profile_picture"`
+	Gender       string `json:"gender"`
+	Bio          string `json:"bio"`
+	BlockedUntil string `json:"blocked_until"`
+	BlockReason  string `json:"block_reason"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
 }
 
 type UserSingleRequest struct {
@@ -27,3 +30,63 @@ type UserList struct {
 	Items []User `json:"users"`
 	Count int    `json:"count"`
 }
+
+type UserStatusRequest struct {
+	Reason       string `json:"reason"`
+	BlockedUntil string `json:"blocked_until"` // optional, RFC3339, blocks for good when empty
+}
+
+type UserStatusChange struct {
+	UserID       string
+	ActorID      string // empty when the system made the change
+	Status       string
+	Reason       string
+	BlockedUntil string
+	FromStatus   string // when set the change only applies to a user in this status
+}
+
+// UserStatusAudit records one change of a user's status.
+type UserStatusAudit struct {
+	ID           string `json:"id"`
+	UserID       string `json:"user_id"`
+	ActorID      string `json:"actor_id"`
+	OldStatus    string `json:"old_status"`
+	NewStatus    string `json:"new_status"`
+	Reason       string `json:"reason"`
+	BlockedUntil string `json:"blocked_until"`
+	CreatedAt    string `json:"created_at"`
+}
+
+type UserStatusAuditList struct {
+	Items []UserStatusAudit `json:"items"`
+	Count int               `json:"count"`
+}
+
+// UserChange records an email or username change. A freed username stays reserved for its
+// previous owner until ReservedUntil.
+type UserChange struct {
+	ID            string `json:"id"`
+	UserID        string `json:"user_id"`
+	Kind          string `json:"kind"` // email, username
+	OldValue      string `json:"old_value"`
+	NewValue      string `json:"new_value"`
+	ReservedUntil string `json:"reserved_until"`
+	CreatedAt     string `json:"created_at"`
+}
+
+type UserChangeList struct {
+	Items []UserChange `json:"items"`
+	Count int          `json:"count"`
+}
+
+type EmailChangeRequest struct {
+	NewEmail string `json:"new_email"`
+}
+
+type EmailChangeConfirmRequest struct {
+	Otp string `json:"otp"`
+}
+
+type UsernameChangeRequest struct {
+	UserName string `json:"username"`
+}
@@ -3,6 +3,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/Akorm0181/yelp/internal/entity"
 )
@@ -18,6 +19,8 @@ type (
 		Update(ctx context.Context, req entity.User) (entity.User, error)
 		Delete(ctx context.Context, req entity.Id) error
 		UpdateField(ctx context.Context, req entity.UpdateFieldRequest) (entity.RowsEffected, error)
+		ChangeStatus(ctx context.Context, req entity.UserStatusChange) (entity.UserStatusAudit, error)
+		GetStatusHistory(ctx context.Context, req entity.GetListFilter) (entity.UserStatusAuditList, error)
 	}
 
 	// SessionRepo -.
@@ -30,14 +33,108 @@ type (
 		UpdateField(ctx context.Context, req entity.UpdateFieldRequest) (entity.RowsEffected, error)
 	}
 
+	// RefreshTokenRepo -.
+	RefreshTokenRepoI interface {
+		Create(ctx context.Context, req entity.RefreshToken) (entity.RefreshToken, error)
+		GetSingle(ctx context.Context, req entity.RefreshTokenSingleRequest) (entity.RefreshToken, error)
+		UpdateField(ctx context.Context, req entity.UpdateFieldRequest) (entity.RowsEffected, error)
+	}
+
+	// UserMfaRepo -.
+	UserMfaRepoI interface {
+		Upsert(ctx context.Context, req entity.UserMfa) (entity.UserMfa, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.UserMfa, error)
+		Enable(ctx context.Context, req entity.Id) error
+		UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error)
+		Delete(ctx context.Context, req entity.Id) error
+	}
+
+	// UserIdentityRepo -.
+	UserIdentityRepoI interface {
+		Create(ctx context.Context, req entity.UserIdentity) (entity.UserIdentity, error)
+		GetSingle(ctx context.Context, req entity.UserIdentitySingleRequest) (entity.UserIdentity, error)
+	}
+
+	// OwnerRequestRepo -.
+	OwnerRequestRepoI interface {
+		Create(ctx context.Context, req entity.OwnerRequest) (entity.OwnerRequest, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.OwnerRequest, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.OwnerRequestList, error)
+		Review(ctx context.Context, req entity.OwnerRequest) (entity.OwnerRequest, error)
+		Approve(ctx context.Context, req entity.OwnerRequest) (entity.OwnerRequest, error)
+	}
+
+	// ApiKeyRepo -.
+	ApiKeyRepoI interface {
+		Create(ctx context.Context, req entity.ApiKey) (entity.ApiKey, error)
+		GetSingle(ctx context.Context, req entity.ApiKeySingleRequest) (entity.ApiKey, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.ApiKeyList, error)
+		UpdateField(ctx context.Context, req entity.UpdateFieldRequest) (entity.RowsEffected, error)
+	}
+
+	// AccountRepo -.
+	AccountRepoI interface {
+		RequestDeletion(ctx context.Context, userID string, deleteAfter time.Time) error
+		CancelDeletion(ctx context.Context, userID string) (bool, error)
+		GetDueDeletions(ctx context.Context, limit int) ([]string, error)
+		Purge(ctx context.Context, userID string) (bool, error)
+		Export(ctx context.Context, userID string) (entity.AccountExport, error)
+	}
+
+	// UserChangeRepo -.
+	UserChangeRepoI interface {
+		Create(ctx context.Context, req entity.UserChange) (entity.UserChange, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.UserChangeList, error)
+		GetLast(ctx context.Context, userID, kind string) (entity.UserChange, error)
+		UsernameReserved(ctx context.Context, username, exceptUserID string) (bool, error)
+	}
+
+	// SearchRepo -.
+	SearchRepoI interface {
+		Search(ctx context.Context, req entity.SearchRequest) (entity.SearchResponse, error)
+	}
+
 	// BusinessRepo -.
 	BusinessRepoI interface {
 		Create(ctx context.Context, req entity.Business) (entity.Business, error)
 		GetSingle(ctx context.Context, req entity.BusinessSingleRequest) (entity.Business, error)
-		GetList(ctx context.Context, req entity.GetListFilter) (entity.BusinessList, error)
+		GetList(ctx context.Context, req entity.BusinessListRequest) (entity.BusinessList, error)
 		Update(ctx context.Context, req entity.Business) (entity.Business, error)
 		Delete(ctx context.Context, req entity.Id) error
 		UpdateField(ctx context.Context, req entity.UpdateFieldRequest) (entity.RowsEffected, error)
+		RecalculateRatings(ctx context.Context) (int, error)
+		Restore(ctx context.Context, req entity.Id) error
+		Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+		TimeZoneExists(ctx context.Context, name string) (bool, error)
+	}
+
+	// BusinessClaimRepo -.
+	BusinessClaimRepoI interface {
+		Create(ctx context.Context, req entity.BusinessClaim) (entity.BusinessClaim, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.BusinessClaim, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.BusinessClaimList, error)
+		AddEvidence(ctx context.Context, claimID string, filePaths []string) ([]entity.BusinessClaimEvidence, error)
+		Approve(ctx context.Context, req entity.BusinessClaim) (entity.BusinessClaimApproval, error)
+		Reject(ctx context.Context, req entity.BusinessClaim) (entity.BusinessClaim, error)
+	}
+
+	// BusinessAttributeRepo -.
+	BusinessAttributeRepoI interface {
+		Create(ctx context.Context, req entity.BusinessAttribute) (entity.BusinessAttribute, error)
+		GetSingle(ctx context.Context, req entity.Id) (entity.BusinessAttribute, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.BusinessAttributeList, error)
+		GetByCategories(ctx context.Context, categoryIDs []string) ([]entity.BusinessAttribute, error)
+		Update(ctx context.Context, req entity.BusinessAttribute) (entity.BusinessAttribute, error)
+		Delete(ctx context.Context, req entity.Id) error
+		SetCategoryAttributes(ctx context.Context, categoryID string, attributeIDs []string) ([]entity.BusinessAttribute, error)
+	}
+
+	// MenuRepo -.
+	MenuRepoI interface {
+		GetSingle(ctx context.Context, req entity.MenuSingleRequest) (entity.Menu, error)
+		GetList(ctx context.Context, req entity.GetListFilter) (entity.MenuList, error)
+		SaveDraft(ctx context.Context, req entity.Menu) (entity.Menu, error)
+		Publish(ctx context.Context, businessID, userID string) (entity.Menu, error)
 	}
 
 	// BusinessCategoryRepo -.
@@ -45,6 +142,8 @@ type (
 		Create(ctx context.Context, req entity.BusinessCategory) (entity.BusinessCategory, error)
 		GetSingle(ctx context.Context, req entity.BusinessCategorySingleRequest) (entity.BusinessCategory, error)
 		GetList(ctx context.Context, req entity.GetListFilter) (entity.BusinessCategoryList, error)
+		GetTree(ctx context.Context) ([]entity.BusinessCategory, error)
+		GetAncestors(ctx context.Context, req entity.Id) ([]entity.BusinessCategory, error)
 		Update(ctx context.Context, req entity.BusinessCategory) (entity.BusinessCategory, error)
 		Delete(ctx context.Context, req entity.Id) error
 	}
@@ -57,6 +156,9 @@ type (
 		GetList(ctx context.Context, req entity.GetListFilter) (entity.BusinessAttachmentList, error)
 		Delete(ctx context.Context, req entity.Id) error
 		Update(ctx context.Context, req entity.BusinessAttachment) (entity.BusinessAttachment, error)
+		UpdateCaption(ctx context.Context, req entity.BusinessAttachment) (entity.BusinessAttachment, error)
+		Reorder(ctx context.Context, businessID string, ids []string) error
+		SetCover(ctx context.Context, businessID, attachmentID string) error
 	}
 
 	// ReviewRepo -.
@@ -66,6 +168,8 @@ type (
 		GetList(ctx context.Context, req entity.GetListFilter) (entity.ReviewList, error)
 		Update(ctx context.Context, req entity.Review) (entity.Review, error)
 		Delete(ctx context.Context, req entity.Id) error
+		Restore(ctx context.Context, req entity.Id) error
+		Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
 	}
 
 	// ReviewAttachmentRepo -.
@@ -103,6 +207,8 @@ type (
 		GetList(ctx context.Context, req entity.GetListFilter) (entity.EventList, error)
 		Update(ctx context.Context, req entity.Event) (entity.Event, error)
 		Delete(ctx context.Context, req entity.Id) error
+		Restore(ctx context.Context, req entity.Id) error
+		Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
 		AddParticipant(ctx context.Context, req entity.EventParticipant) (entity.EventParticipant, error)
 		RemoveParticipant(ctx context.Context, req entity.EventParticipant) error
 		GetParticipants(ctx context.Context, req entity.GetListFilter) (entity.EventParticipantList, error)
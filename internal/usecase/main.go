@@ -5,14 +5,26 @@ import (
 	"github.com/Akorm0181/yelp/internal/usecase/repo"
 	"github.com/Akorm0181/yelp/pkg/logger"
 	"github.com/Akorm0181/yelp/pkg/postgres"
+	rediscache "github.com/golanguzb70/redis-cache"
 )
 
 // UseCase -.
 type UseCase struct {
 	UserRepo               UserRepoI
 	SessionRepo            SessionRepoI
+	RefreshTokenRepo       RefreshTokenRepoI
+	UserMfaRepo            UserMfaRepoI
+	UserIdentityRepo       UserIdentityRepoI
+	OwnerRequestRepo       OwnerRequestRepoI
+	ApiKeyRepo             ApiKeyRepoI
+	AccountRepo            AccountRepoI
+	UserChangeRepo         UserChangeRepoI
+	SearchRepo             SearchRepoI
 	BookmarkRepo           BookmarkRepoI
 	BusinessRepo           BusinessRepoI
+	BusinessClaimRepo      BusinessClaimRepoI
+	MenuRepo               MenuRepoI
+	BusinessAttributeRepo  BusinessAttributeRepoI
 	BusinessCategoryRepo   BusinessCategoryRepoI
 	BusinessAttachmentRepo BusinessAttachmentRepoI
 	ReviewRepo             ReviewRepoI
@@ -27,12 +39,23 @@ type UseCase struct {
 }
 
 // New -.
-func New(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *UseCase {
+func New(pg *postgres.Postgres, config *config.Config, logger *logger.Logger, redis rediscache.RedisCache) *UseCase {
 	return &UseCase{
 		UserRepo:               repo.NewUserRepo(pg, config, logger),
 		BookmarkRepo:           repo.NewBookmarkRepo(pg, config, logger),
-		SessionRepo:            repo.NewSessionRepo(pg, config, logger),
+		SessionRepo:            repo.NewSessionRepo(pg, config, logger, redis),
+		RefreshTokenRepo:       repo.NewRefreshTokenRepo(pg, config, logger),
+		UserMfaRepo:            repo.NewUserMfaRepo(pg, config, logger),
+		UserIdentityRepo:       repo.NewUserIdentityRepo(pg, config, logger),
+		OwnerRequestRepo:       repo.NewOwnerRequestRepo(pg, config, logger),
+		ApiKeyRepo:             repo.NewApiKeyRepo(pg, config, logger),
+		AccountRepo:            repo.NewAccountRepo(pg, config, logger),
+		UserChangeRepo:         repo.NewUserChangeRepo(pg, config, logger),
+		SearchRepo:             repo.NewSearchRepo(pg, config, logger),
 		BusinessRepo:           repo.NewBusinessRepo(pg, config, logger),
+		BusinessClaimRepo:      repo.NewBusinessClaimRepo(pg, config, logger),
+		MenuRepo:               repo.NewMenuRepo(pg, config, logger),
+		BusinessAttributeRepo:  repo.NewBusinessAttributeRepo(pg, config, logger),
 		BusinessCategoryRepo:   repo.NewBusinessCategoryRepo(pg, config, logger),
 		BusinessAttachmentRepo: repo.NewBusinessAttachmentRepo(pg, config, logger),
 		ReviewRepo:             repo.NewReviewRepo(pg, config, logger),
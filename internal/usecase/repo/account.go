@@ -0,0 +1,461 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/Akorm0181/yelp/pkg/logger"
+	"github.com/Akorm0181/yelp/pkg/postgres"
+)
+
+// AccountRepo covers the account lifecycle that spans several tables: scheduled deletion
+// and the data export.
+type AccountRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewAccountRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *AccountRepo {
+	return &AccountRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+// RequestDeletion schedules the deletion of the user and suspends their api keys until it is
+// cancelled.
+func (r *AccountRepo) RequestDeletion(ctx context.Context, userID string, deleteAfter time.Time) error {
+	tx, err := r.pg.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qeury, args, err := r.pg.Builder.Update("users").
+		SetMap(map[string]interface{}{
+			"deletion_requested_at": "now()",
+			"delete_after":          deleteAfter,
+			"updated_at":            "now()",
+		}).
+		Where("id = ?", userID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	qeury, args, err = r.pg.Builder.Update("api_key").
+		SetMap(map[string]interface{}{
+			"suspended_at": "now()",
+			"updated_at":   "now()",
+		}).
+		Where("user_id = ? AND revoked_at IS NULL AND suspended_at IS NULL", userID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// CancelDeletion clears a scheduled deletion and lifts the suspension of the api keys, reports
+// whether one was scheduled.
+func (r *AccountRepo) CancelDeletion(ctx context.Context, userID string) (bool, error) {
+	tx, err := r.pg.Pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	qeury, args, err := r.pg.Builder.Update("users").
+		SetMap(map[string]interface{}{
+			"deletion_requested_at": nil,
+			"delete_after":          nil,
+			"updated_at":            "now()",
+		}).
+		Where("id = ? AND delete_after IS NOT NULL", userID).ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	n, err := tx.Exec(ctx, qeury, args...)
+	if err != nil {
+		return false, err
+	}
+
+	if n.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	qeury, args, err = r.pg.Builder.Update("api_key").
+		SetMap(map[string]interface{}{
+			"suspended_at": nil,
+			"updated_at":   "now()",
+		}).
+		Where("user_id = ? AND suspended_at IS NOT NULL", userID).ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(ctx, qeury, args...)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
+
+// GetDueDeletions returns up to limit users whose grace period is over.
+func (r *AccountRepo) GetDueDeletions(ctx context.Context, limit int) ([]string, error) {
+	qeury, args, err := r.pg.Builder.Select("id").From("users").
+		Where("delete_after <= now()").
+		OrderBy("delete_after").
+		Limit(uint64(limit)).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// Purge deletes the user with everything that references it. The grace period is checked
+// again so a deletion cancelled in the meantime is not carried out. Username reservations
+// that are still running are kept without the user until they end.
+func (r *AccountRepo) Purge(ctx context.Context, userID string) (bool, error) {
+	tx, err := r.pg.Pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	qeury, args, err := r.pg.Builder.Delete("users").
+		Where("id = ? AND delete_after <= now()", userID).ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	n, err := tx.Exec(ctx, qeury, args...)
+	if err != nil {
+		return false, err
+	}
+
+	if n.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	// ON DELETE SET NULL has left the history of the user and earlier purged users behind
+	qeury, args, err = r.pg.Builder.Delete("user_change_history").
+		Where("user_id IS NULL AND (kind <> 'username' OR reserved_until IS NULL OR reserved_until <= now())").ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(ctx, qeury, args...)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
+
+// Export collects the user's data. The profile is filled by the caller.
+func (r *AccountRepo) Export(ctx context.Context, userID string) (entity.AccountExport, error) {
+	var (
+		response = entity.AccountExport{
+			ExportedAt:    time.Now().Format(time.RFC3339),
+			Reviews:       []entity.Review{},
+			Bookmarks:     []entity.Bookmark{},
+			Following:     []entity.AccountExportFollow{},
+			Followers:     []entity.AccountExportFollow{},
+			Events:        []entity.EventParticipant{},
+			Notifications: []entity.Notification{},
+		}
+		err error
+	)
+
+	response.Reviews, err = r.exportReviews(ctx, userID)
+	if err != nil {
+		return response, err
+	}
+
+	response.Bookmarks, err = r.exportBookmarks(ctx, userID)
+	if err != nil {
+		return response, err
+	}
+
+	response.Following, err = r.exportFollows(ctx, "follower_id", "following_id", userID)
+	if err != nil {
+		return response, err
+	}
+
+	response.Followers, err = r.exportFollows(ctx, "following_id", "follower_id", userID)
+	if err != nil {
+		return response, err
+	}
+
+	response.Events, err = r.exportEvents(ctx, userID)
+	if err != nil {
+		return response, err
+	}
+
+	response.Notifications, err = r.exportNotifications(ctx, userID)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+func (r *AccountRepo) exportReviews(ctx context.Context, userID string) ([]entity.Review, error) {
+	var (
+		reviews              = []entity.Review{}
+		index                = map[string]int{}
+		createdAt, updatedAt time.Time
+		comment              sql.NullString
+	)
+
+	qeury, args, err := r.pg.Builder.
+		Select(`id, business_id, rating, comment, created_at, updated_at`).
+		From("reviews").Where("user_id = ?", userID).OrderBy("created_at").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := entity.Review{UserID: userID, Attachment: []entity.ReviewAttachment{}}
+		err = rows.Scan(&item.ID, &item.BusinessID, &item.Rating, &comment, &createdAt, &updatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		item.Comment = comment.String
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+		index[item.ID] = len(reviews)
+		reviews = append(reviews, item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	qeury, args, err = r.pg.Builder.
+		Select(`a.id, a.review_id, a.filepath, a.content_type, a.created_at, a.updated_at`).
+		From("reviews_attachments a").
+		Join("reviews r ON r.id = a.review_id").
+		Where("r.user_id = ?", userID).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	attachmentRows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer attachmentRows.Close()
+
+	for attachmentRows.Next() {
+		var item entity.ReviewAttachment
+		err = attachmentRows.Scan(&item.Id, &item.ReviewId, &item.FilePath, &item.ContentType, &createdAt, &updatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+		if i, ok := index[item.ReviewId]; ok {
+			reviews[i].Attachment = append(reviews[i].Attachment, item)
+		}
+	}
+
+	return reviews, attachmentRows.Err()
+}
+
+func (r *AccountRepo) exportBookmarks(ctx context.Context, userID string) ([]entity.Bookmark, error) {
+	var (
+		bookmarks            = []entity.Bookmark{}
+		createdAt, updatedAt sql.NullTime
+	)
+
+	qeury, args, err := r.pg.Builder.
+		Select(`id, business_id, created_at, updated_at`).
+		From("bookmarks").Where("user_id = ?", userID).OrderBy("created_at").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := entity.Bookmark{UserID: userID}
+		err = rows.Scan(&item.ID, &item.BusinessID, &createdAt, &updatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if createdAt.Valid {
+			item.CreatedAt = createdAt.Time.Format(time.RFC3339)
+		}
+		if updatedAt.Valid {
+			item.UpdatedAt = updatedAt.Time.Format(time.RFC3339)
+		}
+
+		bookmarks = append(bookmarks, item)
+	}
+
+	return bookmarks, rows.Err()
+}
+
+// exportFollows lists the users on the other side of the user's follow relations,
+// matchColumn holds the user and otherColumn the other party.
+func (r *AccountRepo) exportFollows(ctx context.Context, matchColumn, otherColumn, userID string) ([]entity.AccountExportFollow, error) {
+	var (
+		follows   = []entity.AccountExportFollow{}
+		createdAt time.Time
+	)
+
+	qeury, args, err := r.pg.Builder.
+		Select(`u.id, u.username, u.full_name, f.created_at`).
+		From("follower f").
+		Join("users u ON u.id = f."+otherColumn).
+		Where("f."+matchColumn+" = ?", userID).OrderBy("f.created_at").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.AccountExportFollow
+		err = rows.Scan(&item.UserID, &item.UserName, &item.FullName, &createdAt)
+		if err != nil {
+			return nil, err
+		}
+
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		follows = append(follows, item)
+	}
+
+	return follows, rows.Err()
+}
+
+func (r *AccountRepo) exportEvents(ctx context.Context, userID string) ([]entity.EventParticipant, error) {
+	var (
+		events   = []entity.EventParticipant{}
+		joinedAt sql.NullTime
+	)
+
+	qeury, args, err := r.pg.Builder.
+		Select(`id, event_id, joined_at`).
+		From("event_participants").Where("user_id = ?", userID).OrderBy("joined_at").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := entity.EventParticipant{UserID: userID}
+		err = rows.Scan(&item.ID, &item.EventID, &joinedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if joinedAt.Valid {
+			item.JoinedAt = joinedAt.Time.Format(time.RFC3339)
+		}
+
+		events = append(events, item)
+	}
+
+	return events, rows.Err()
+}
+
+func (r *AccountRepo) exportNotifications(ctx context.Context, userID string) ([]entity.Notification, error) {
+	var (
+		notifications           = []entity.Notification{}
+		ownerID, email, message sql.NullString
+		status                  sql.NullString
+		createdAt               sql.NullTime
+	)
+
+	qeury, args, err := r.pg.Builder.
+		Select(`id, owner_id, email, message, status, created_at`).
+		From("notifications").Where("user_id = ?", userID).OrderBy("created_at").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := entity.Notification{UserID: userID}
+		err = rows.Scan(&item.ID, &ownerID, &email, &message, &status, &createdAt)
+		if err != nil {
+			return nil, err
+		}
+
+		item.OwnerId = ownerID.String
+		item.Email = email.String
+		item.Message = message.String
+		item.Status = status.String
+		if createdAt.Valid {
+			item.CreatedAt = createdAt.Time.Format(time.RFC3339)
+		}
+
+		notifications = append(notifications, item)
+	}
+
+	return notifications, rows.Err()
+}
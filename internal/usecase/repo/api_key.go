@@ -0,0 +1,169 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/Akorm0181/yelp/pkg/logger"
+	"github.com/Akorm0181/yelp/pkg/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+type ApiKeyRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewApiKeyRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *ApiKeyRepo {
+	return &ApiKeyRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+const apiKeyColumns = `id, user_id, name, prefix, key_hash, scopes, last_used_at, expires_at, revoked_at, suspended_at, created_at, updated_at`
+
+func scanApiKey(row pgx.Row) (entity.ApiKey, error) {
+	var (
+		item                                          entity.ApiKey
+		lastUsedAt, expiresAt, revokedAt, suspendedAt sql.NullTime
+		createdAt, updatedAt                          time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.UserID, &item.Name, &item.Prefix, &item.KeyHash, &item.Scopes,
+		&lastUsedAt, &expiresAt, &revokedAt, &suspendedAt, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.ApiKey{}, err
+	}
+
+	if lastUsedAt.Valid {
+		item.LastUsedAt = lastUsedAt.Time.Format(time.RFC3339)
+	}
+	if expiresAt.Valid {
+		item.ExpiresAt = expiresAt.Time.Format(time.RFC3339)
+	}
+	if revokedAt.Valid {
+		item.RevokedAt = revokedAt.Time.Format(time.RFC3339)
+	}
+	if suspendedAt.Valid {
+		item.SuspendedAt = suspendedAt.Time.Format(time.RFC3339)
+	}
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return item, nil
+}
+
+func (r *ApiKeyRepo) Create(ctx context.Context, req entity.ApiKey) (entity.ApiKey, error) {
+	req.ID = uuid.NewString()
+
+	var expiresAt interface{}
+	if req.ExpiresAt != "" {
+		t, err := time.Parse(time.RFC3339, req.ExpiresAt)
+		if err != nil {
+			return entity.ApiKey{}, err
+		}
+		expiresAt = t
+	}
+
+	qeury, args, err := r.pg.Builder.Insert("api_key").
+		Columns(`id, user_id, name, prefix, key_hash, scopes, expires_at`).
+		Values(req.ID, req.UserID, req.Name, req.Prefix, req.KeyHash, req.Scopes, expiresAt).
+		Suffix("RETURNING " + apiKeyColumns).ToSql()
+	if err != nil {
+		return entity.ApiKey{}, err
+	}
+
+	return scanApiKey(r.pg.Pool.QueryRow(ctx, qeury, args...))
+}
+
+func (r *ApiKeyRepo) GetSingle(ctx context.Context, req entity.ApiKeySingleRequest) (entity.ApiKey, error) {
+	qeuryBuilder := r.pg.Builder.Select(apiKeyColumns).From("api_key")
+
+	switch {
+	case req.ID != "":
+		qeuryBuilder = qeuryBuilder.Where("id = ?", req.ID)
+	case req.KeyHash != "":
+		qeuryBuilder = qeuryBuilder.Where("key_hash = ?", req.KeyHash)
+	default:
+		return entity.ApiKey{}, fmt.Errorf("GetSingle - invalid request")
+	}
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return entity.ApiKey{}, err
+	}
+
+	return scanApiKey(r.pg.Pool.QueryRow(ctx, qeury, args...))
+}
+
+func (r *ApiKeyRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.ApiKeyList, error) {
+	var response = entity.ApiKeyList{}
+
+	qeuryBuilder := r.pg.Builder.Select(apiKeyColumns).From("api_key")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanApiKey(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items = append(response.Items, item)
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("api_key").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.Pool.QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+func (r *ApiKeyRepo) UpdateField(ctx context.Context, req entity.UpdateFieldRequest) (entity.RowsEffected, error) {
+	mp := map[string]interface{}{}
+	response := entity.RowsEffected{}
+
+	for _, item := range req.Items {
+		mp[item.Column] = item.Value
+	}
+
+	qeury, args, err := r.pg.Builder.Update("api_key").SetMap(mp).Where(PrepareFilter(req.Filter)).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	n, err := r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+
+	response.RowsEffected = int(n.RowsAffected())
+
+	return response, nil
+}
@@ -52,7 +52,7 @@ func (r *BookmarkRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Boo
 
 	queryBuilder := r.pg.Builder.
 		Select(`id, user_id, business_id, created_at, updated_at`).
-		From("bookmarks").Where("id = ?", req.ID)
+		From("bookmarks").Where("id = ?", req.ID).Where(liveBusiness)
 
 	query, args, err := queryBuilder.ToSql()
 	if err != nil {
@@ -79,7 +79,8 @@ func (r *BookmarkRepo) GetList(ctx context.Context, req entity.GetListFilter) (e
 
 	queryBuilder := r.pg.Builder.
 		Select(`id, user_id, business_id, created_at, updated_at`).
-		From("bookmarks")
+		From("bookmarks").
+		Where(liveBusiness)
 
 	queryBuilder, where := PrepareGetListQuery(queryBuilder, req)
 
@@ -107,7 +108,7 @@ func (r *BookmarkRepo) GetList(ctx context.Context, req entity.GetListFilter) (e
 		response.Items = append(response.Items, item)
 	}
 
-	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("bookmarks").Where(where).ToSql()
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("bookmarks").Where(liveBusiness).Where(where).ToSql()
 	if err != nil {
 		return response, err
 	}
@@ -5,13 +5,16 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Akorm0181/yelp/config"
 	"github.com/Akorm0181/yelp/internal/entity"
 	"github.com/Akorm0181/yelp/pkg/logger"
 	"github.com/Akorm0181/yelp/pkg/postgres"
+	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 )
 
 type BusinessRepo struct {
@@ -32,32 +35,252 @@ func NewBusinessRepo(pg *postgres.Postgres, config *config.Config, logger *logge
 func (r *BusinessRepo) Create(ctx context.Context, req entity.Business) (entity.Business, error) {
 	req.ID = uuid.NewString()
 
+	tx, err := r.pg.Pool.Begin(ctx)
+	if err != nil {
+		return entity.Business{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	// listings added by users have no owner until someone claims them
+	var ownerID interface{}
+	if req.OwnerID != "" {
+		ownerID = req.OwnerID
+	}
+
 	qeury, args, err := r.pg.Builder.Insert("businesses").
-		Columns(`id, name, description, category_id, address, owner_id, latitude, longitude, contact_info, hours_of_operation`).
-		Values(req.ID, req.Name, req.Description, req.CategoryID, req.Address, req.OwnerID, req.Latitude, req.Longitude, req.ContactInfo, req.HoursOfOperation).ToSql()
+		Columns(`id, name, description, address, owner_id, latitude, longitude, contact_info, time_zone, price_range, claim_state, created_by`).
+		Values(req.ID, req.Name, req.Description, req.Address, ownerID, req.Latitude, req.Longitude, req.ContactInfo, req.TimeZone, priceRangeValue(req.PriceRange), req.ClaimState, req.CreatedBy).ToSql()
+	if err != nil {
+		return entity.Business{}, err
+	}
+
+	_, err = tx.Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.Business{}, err
+	}
+
+	err = r.replaceHours(ctx, tx, req)
+	if err != nil {
+		return entity.Business{}, err
+	}
+
+	err = r.replaceAttributes(ctx, tx, req)
 	if err != nil {
 		return entity.Business{}, err
 	}
 
-	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	err = r.replaceCategories(ctx, tx, req)
 	if err != nil {
 		return entity.Business{}, err
 	}
 
-	return req, nil
+	return req, tx.Commit(ctx)
+}
+
+// businessHoursColumns selects the hours of b as JSON arrays, special hours only from the
+// current local date on, and whether b is open right now.
+const businessHoursColumns = `
+	(SELECT COALESCE(JSON_AGG(JSON_BUILD_OBJECT(
+		'weekday', h.weekday,
+		'opens_at', TO_CHAR(h.opens_at, 'HH24:MI'),
+		'closes_at', TO_CHAR(h.closes_at, 'HH24:MI')
+	) ORDER BY h.weekday, h.opens_at), '[]') FROM business_hours h WHERE h.business_id = b.id) AS hours,
+	(SELECT COALESCE(JSON_AGG(JSON_BUILD_OBJECT(
+		'date', s.date,
+		'closed', s.closed,
+		'opens_at', COALESCE(TO_CHAR(s.opens_at, 'HH24:MI'), ''),
+		'closes_at', COALESCE(TO_CHAR(s.closes_at, 'HH24:MI'), ''),
+		'note', COALESCE(s.note, '')
+	) ORDER BY s.date, s.opens_at), '[]') FROM business_special_hours s
+		WHERE s.business_id = b.id AND s.date >= (now() AT TIME ZONE b.time_zone)::date) AS special_hours,
+	business_open_at(b.id, b.time_zone, now()) AS open_now`
+
+// businessCategoriesColumn selects the categories of b as a JSON array, the primary one first.
+const businessCategoriesColumn = `
+	(SELECT COALESCE(JSON_AGG(JSON_BUILD_OBJECT(
+		'id', c.id,
+		'parent_id', COALESCE(c.parent_id::text, ''),
+		'name', c.name,
+		'slug', c.slug,
+		'icon', COALESCE(c.icon, '')
+	) ORDER BY l.is_primary DESC, c.name), '[]') FROM business_category_link l
+		JOIN business_categories c ON c.id = l.category_id WHERE l.business_id = b.id) AS categories`
+
+// replaceCategories swaps the categories of the business for req.CategoryIDs, the first one
+// becomes the primary category.
+func (r *BusinessRepo) replaceCategories(ctx context.Context, tx pgx.Tx, req entity.Business) error {
+	qeury, args, err := r.pg.Builder.Delete("business_category_link").Where("business_id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	if len(req.CategoryIDs) == 0 {
+		return nil
+	}
+
+	insert := r.pg.Builder.Insert("business_category_link").Columns(`business_id, category_id, is_primary`)
+	for i, categoryID := range req.CategoryIDs {
+		insert = insert.Values(req.ID, categoryID, i == 0)
+	}
+
+	qeury, args, err = insert.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, qeury, args...)
+	return err
+}
+
+// scanCategories fills the categories and category ids of a business from the JSON array
+// of businessCategoriesColumn.
+func scanCategories(business *entity.Business, categoriesRaw []byte) error {
+	err := json.Unmarshal(categoriesRaw, &business.Categories)
+	if err != nil {
+		return err
+	}
+
+	business.CategoryIDs = make([]string, 0, len(business.Categories))
+	for _, category := range business.Categories {
+		business.CategoryIDs = append(business.CategoryIDs, category.ID)
+	}
+
+	return nil
+}
+
+// businessAttributesColumn selects the attributes of b as a JSON object of key to value.
+const businessAttributesColumn = `
+	(SELECT COALESCE(JSON_OBJECT_AGG(a.key, av.value), '{}') FROM business_attribute_value av
+		JOIN business_attribute a ON a.id = av.attribute_id WHERE av.business_id = b.id) AS attributes`
+
+// replaceAttributes swaps the attribute values of the business for the ones in req, the keys
+// are expected to be checked against the category already.
+func (r *BusinessRepo) replaceAttributes(ctx context.Context, tx pgx.Tx, req entity.Business) error {
+	qeury, args, err := r.pg.Builder.Delete("business_attribute_value").Where("business_id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range req.Attributes {
+		qeury, args, err = r.pg.Builder.Insert("business_attribute_value").
+			Columns(`business_id, attribute_id, value`).
+			Select(r.pg.Builder.Select().Column("?::uuid, id, ?", req.ID, value).From("business_attribute").Where("key = ?", key)).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// replaceHours swaps the weekly and special hours of the business for the ones in req.
+func (r *BusinessRepo) replaceHours(ctx context.Context, tx pgx.Tx, req entity.Business) error {
+	for _, table := range []string{"business_hours", "business_special_hours"} {
+		qeury, args, err := r.pg.Builder.Delete(table).Where("business_id = ?", req.ID).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(req.HoursOfOperation) > 0 {
+		insert := r.pg.Builder.Insert("business_hours").Columns(`id, business_id, weekday, opens_at, closes_at`)
+		for _, item := range req.HoursOfOperation {
+			insert = insert.Values(uuid.NewString(), req.ID, item.Weekday, item.OpensAt, item.ClosesAt)
+		}
+
+		qeury, args, err := insert.ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(req.SpecialHours) > 0 {
+		insert := r.pg.Builder.Insert("business_special_hours").Columns(`id, business_id, date, closed, opens_at, closes_at, note`)
+		for _, item := range req.SpecialHours {
+			var opensAt, closesAt interface{}
+			if !item.Closed {
+				opensAt, closesAt = item.OpensAt, item.ClosesAt
+			}
+			insert = insert.Values(uuid.NewString(), req.ID, item.Date, item.Closed, opensAt, closesAt, item.Note)
+		}
+
+		qeury, args, err := insert.ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scanHours fills the columns of businessHoursColumns into item.
+func scanHours(item *entity.Business, hoursRaw, specialHoursRaw []byte, openNow bool) error {
+	err := json.Unmarshal(hoursRaw, &item.HoursOfOperation)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(specialHoursRaw, &item.SpecialHours)
+	if err != nil {
+		return err
+	}
+
+	item.OpenNow = openNow
+
+	return nil
 }
 
 func (r *BusinessRepo) GetSingle(ctx context.Context, req entity.BusinessSingleRequest) (entity.Business, error) {
 	response := entity.Business{}
 	var (
-		createdAt, updatedAt                       time.Time
-		description, contactInfo, hoursOfOperation sql.NullString
-		latitude, longitude                        sql.NullFloat64
+		createdAt, updatedAt      time.Time
+		description, contactInfo  sql.NullString
+		ownerID, createdBy        sql.NullString
+		verifiedAt                sql.NullTime
+		latitude, longitude       sql.NullFloat64
+		priceRange                sql.NullInt16
+		hoursRaw, specialHoursRaw []byte
+		categoriesRaw             []byte
+		attributesRaw             []byte
+		openNow                   bool
 	)
 
 	qeuryBuilder := r.pg.Builder.
-		Select(`id, name, description, category_id, address, latitude, longitude, contact_info, hours_of_operation, owner_id, created_at, updated_at`).
-		From("businesses")
+		Select(`id, name, description, address, latitude, longitude, contact_info, time_zone, price_range, owner_id, created_at, updated_at,
+			rating_avg, review_count, rating_histogram, claim_state, verified_at, created_by, ` +
+			businessCategoriesColumn + `, ` + businessAttributesColumn + `, ` + businessHoursColumns).
+		From("businesses b")
 
 	switch {
 	case req.ID != "":
@@ -65,19 +288,44 @@ func (r *BusinessRepo) GetSingle(ctx context.Context, req entity.BusinessSingleR
 	case req.OwnerID != "":
 		qeuryBuilder = qeuryBuilder.Where("owner_id = ?", req.OwnerID)
 	case req.CategoryID != "":
-		qeuryBuilder = qeuryBuilder.Where("category_id = ?", req.CategoryID)
+		qeuryBuilder = qeuryBuilder.Where("id IN (SELECT business_id FROM business_category_link WHERE category_id = ?)", req.CategoryID).Limit(1)
 	default:
 		return entity.Business{}, fmt.Errorf("GetSingle - invalid request")
 	}
 
-	qeury, args, err := qeuryBuilder.ToSql()
+	qeury, args, err := qeuryBuilder.Where("deleted_at IS NULL").ToSql()
 	if err != nil {
 		return entity.Business{}, err
 	}
 
 	err = r.pg.Pool.QueryRow(ctx, qeury, args...).
-		Scan(&response.ID, &response.Name, &description, &response.CategoryID, &response.Address,
-			&latitude, &longitude, &contactInfo, &hoursOfOperation, &response.OwnerID, &createdAt, &updatedAt)
+		Scan(&response.ID, &response.Name, &description, &response.Address,
+			&latitude, &longitude, &contactInfo, &response.TimeZone, &priceRange, &ownerID, &createdAt, &updatedAt,
+			&response.RatingAvg, &response.ReviewCount, &response.RatingHistogram,
+			&response.ClaimState, &verifiedAt, &createdBy, &categoriesRaw, &attributesRaw,
+			&hoursRaw, &specialHoursRaw, &openNow)
+	if err != nil {
+		return entity.Business{}, err
+	}
+
+	response.OwnerID = ownerID.String
+	response.CreatedBy = createdBy.String
+	response.PriceRange = strings.Repeat("$", int(priceRange.Int16))
+	if verifiedAt.Valid {
+		response.VerifiedAt = verifiedAt.Time.Format(time.RFC3339)
+	}
+
+	err = scanHours(&response, hoursRaw, specialHoursRaw, openNow)
+	if err != nil {
+		return entity.Business{}, err
+	}
+
+	err = json.Unmarshal(attributesRaw, &response.Attributes)
+	if err != nil {
+		return entity.Business{}, err
+	}
+
+	err = scanCategories(&response, categoriesRaw)
 	if err != nil {
 		return entity.Business{}, err
 	}
@@ -98,14 +346,6 @@ func (r *BusinessRepo) GetSingle(ctx context.Context, req entity.BusinessSingleR
 		}
 		response.ContactInfo = contactInfoStruct
 	}
-	if hoursOfOperation.Valid {
-		var hoursOfOperationStruct entity.HoursOfOperation
-		err := json.Unmarshal([]byte(hoursOfOperation.String), &hoursOfOperationStruct)
-		if err != nil {
-			return response, err
-		}
-		response.HoursOfOperation = hoursOfOperationStruct
-	}
 	if description.Valid {
 		response.Description = description.String
 	}
@@ -113,26 +353,44 @@ func (r *BusinessRepo) GetSingle(ctx context.Context, req entity.BusinessSingleR
 	return response, nil
 }
 
-func (r *BusinessRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.BusinessList, error) {
+func (r *BusinessRepo) GetList(ctx context.Context, req entity.BusinessListRequest) (entity.BusinessList, error) {
 	var (
-		response                                   = entity.BusinessList{}
-		createdAt, updatedAt                       time.Time
-		description, contactInfo, hoursOfOperation sql.NullString
-		latitude, longitude                        sql.NullFloat64
+		response                        = entity.BusinessList{}
+		createdAt, updatedAt            time.Time
+		description, contactInfo        sql.NullString
+		ownerID, createdBy              sql.NullString
+		verifiedAt                      sql.NullTime
+		latitude, longitude, distanceKm sql.NullFloat64
+		priceRange                      sql.NullInt16
 	)
 
 	// Fully qualify column names to avoid ambiguity
 	queryBuilder := r.pg.Builder.
 		Select(`
-			b.id AS business_id, b.name, b.description, b.category_id, b.address, 
-			b.latitude, b.longitude, b.contact_info, b.hours_of_operation, 
+			b.id AS business_id, b.name, b.description, b.address,
+			b.latitude, b.longitude, b.contact_info, b.time_zone, b.price_range,
 			b.owner_id, b.created_at, b.updated_at, 
-			COALESCE(JSON_AGG(ba) FILTER (WHERE ba.id IS NOT NULL), '[]') AS attachments
-		`).
+			b.rating_avg, b.review_count, b.rating_histogram,
+			b.claim_state, b.verified_at, b.created_by,
+			COALESCE(JSON_AGG(ba ORDER BY ba.position, ba.created_at) FILTER (WHERE ba.id IS NOT NULL), '[]') AS attachments,
+		` + businessCategoriesColumn + `, ` + businessAttributesColumn + `, ` + businessHoursColumns).
 		From("businesses b").
 		LeftJoin("business_attachment AS ba ON b.id = ba.business_id")
 
-	queryBuilder, where := PrepareGetListQuery(queryBuilder, req)
+	if req.Near != nil {
+		queryBuilder = queryBuilder.Column(squirrel.Expr(
+			"earth_distance(ll_to_earth(?, ?), ll_to_earth(b.latitude, b.longitude)) / 1000 AS distance_km",
+			req.Near.Latitude, req.Near.Longitude))
+	} else {
+		queryBuilder = queryBuilder.Column("NULL::float8 AS distance_km")
+	}
+
+	listWhere, err := businessListFilter(req)
+	if err != nil {
+		return response, err
+	}
+
+	queryBuilder, where := PrepareGetListQuery(queryBuilder.Where(listWhere), req.GetListFilter)
 
 	query, args, err := queryBuilder.GroupBy("b.id").ToSql() // Group by business ID for proper aggregation
 	if err != nil {
@@ -147,19 +405,38 @@ func (r *BusinessRepo) GetList(ctx context.Context, req entity.GetListFilter) (e
 
 	for rows.Next() {
 		var (
-			item           entity.Business
-			attachmentsRaw []byte // To hold the aggregated JSON array of attachments
+			item                      entity.Business
+			attachmentsRaw            []byte // To hold the aggregated JSON array of attachments
+			categoriesRaw             []byte
+			attributesRaw             []byte
+			hoursRaw, specialHoursRaw []byte
+			openNow                   bool
 		)
 
 		err = rows.Scan(
-			&item.ID, &item.Name, &description, &item.CategoryID, &item.Address,
-			&latitude, &longitude, &contactInfo, &hoursOfOperation,
-			&item.OwnerID, &createdAt, &updatedAt, &attachmentsRaw,
+			&item.ID, &item.Name, &description, &item.Address,
+			&latitude, &longitude, &contactInfo, &item.TimeZone, &priceRange,
+			&ownerID, &createdAt, &updatedAt,
+			&item.RatingAvg, &item.ReviewCount, &item.RatingHistogram,
+			&item.ClaimState, &verifiedAt, &createdBy, &attachmentsRaw, &categoriesRaw, &attributesRaw,
+			&hoursRaw, &specialHoursRaw, &openNow, &distanceKm,
 		)
 		if err != nil {
 			return response, err
 		}
 
+		err = scanHours(&item, hoursRaw, specialHoursRaw, openNow)
+		if err != nil {
+			return response, err
+		}
+
+		item.OwnerID = ownerID.String
+		item.CreatedBy = createdBy.String
+		item.PriceRange = strings.Repeat("$", int(priceRange.Int16))
+		if verifiedAt.Valid {
+			item.VerifiedAt = verifiedAt.Time.Format(time.RFC3339)
+		}
+
 		item.CreatedAt = createdAt.Format(time.RFC3339)
 		item.UpdatedAt = updatedAt.Format(time.RFC3339)
 		if latitude.Valid {
@@ -168,6 +445,10 @@ func (r *BusinessRepo) GetList(ctx context.Context, req entity.GetListFilter) (e
 		if longitude.Valid {
 			item.Longitude = longitude.Float64
 		}
+		if distanceKm.Valid {
+			distance := distanceKm.Float64
+			item.DistanceKm = &distance
+		}
 		if contactInfo.Valid {
 			var contactInfoStruct entity.ContactInfo
 			err := json.Unmarshal([]byte(contactInfo.String), &contactInfoStruct)
@@ -176,14 +457,6 @@ func (r *BusinessRepo) GetList(ctx context.Context, req entity.GetListFilter) (e
 			}
 			item.ContactInfo = contactInfoStruct
 		}
-		if hoursOfOperation.Valid {
-			var hoursOfOperationStruct entity.HoursOfOperation
-			err := json.Unmarshal([]byte(hoursOfOperation.String), &hoursOfOperationStruct)
-			if err != nil {
-				return response, err
-			}
-			item.HoursOfOperation = hoursOfOperationStruct
-		}
 		if description.Valid {
 			item.Description = description.String
 		}
@@ -193,11 +466,21 @@ func (r *BusinessRepo) GetList(ctx context.Context, req entity.GetListFilter) (e
 			return response, err
 		}
 
+		err = json.Unmarshal(attributesRaw, &item.Attributes)
+		if err != nil {
+			return response, err
+		}
+
+		err = scanCategories(&item, categoriesRaw)
+		if err != nil {
+			return response, err
+		}
+
 		response.Items = append(response.Items, item)
 	}
 
 	// Count query
-	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("businesses b").Where(where).ToSql()
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("businesses b").Where(listWhere).Where(where).ToSql()
 	if err != nil {
 		return response, err
 	}
@@ -207,50 +490,201 @@ func (r *BusinessRepo) GetList(ctx context.Context, req entity.GetListFilter) (e
 		return response, err
 	}
 
+	response.Facets, err = r.attributeFacets(ctx, squirrel.And{listWhere, where})
+	if err != nil {
+		return response, err
+	}
+
 	return response, nil
 }
 
+// attributeFacets counts the businesses matching where per attribute value.
+func (r *BusinessRepo) attributeFacets(ctx context.Context, where squirrel.And) ([]entity.AttributeFacet, error) {
+	facets := []entity.AttributeFacet{}
+
+	qeury, args, err := r.pg.Builder.
+		Select("a.key, a.name, av.value, COUNT(1)").
+		From("businesses b").
+		Join("business_attribute_value av ON av.business_id = b.id").
+		Join("business_attribute a ON a.id = av.attribute_id").
+		Where(where).
+		GroupBy("a.key", "a.name", "av.value").
+		OrderBy("a.name", "a.key", "COUNT(1) DESC", "av.value").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			key, name string
+			value     entity.FacetCount
+		)
+
+		err = rows.Scan(&key, &name, &value.Value, &value.Count)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(facets) == 0 || facets[len(facets)-1].Key != key {
+			facets = append(facets, entity.AttributeFacet{Key: key, Name: name})
+		}
+		facets[len(facets)-1].Values = append(facets[len(facets)-1].Values, value)
+	}
+
+	return facets, rows.Err()
+}
+
+// businessListFilter turns the business specific options of the request into conditions. The
+// radius is checked with earth_box first so the gist index on ll_to_earth(latitude, longitude)
+// is used, earth_distance then drops the corners of the box.
+func businessListFilter(req entity.BusinessListRequest) (squirrel.And, error) {
+	where := squirrel.And{squirrel.Expr("b.deleted_at IS NULL")}
+
+	if req.OpenAt != "" {
+		openAt, err := time.Parse(time.RFC3339, req.OpenAt)
+		if err != nil {
+			return nil, err
+		}
+		where = append(where, squirrel.Expr("business_open_at(b.id, b.time_zone, ?)", openAt))
+	}
+
+	if req.Near != nil && req.Near.RadiusKm > 0 {
+		radius := req.Near.RadiusKm * 1000
+		where = append(where,
+			squirrel.Expr("b.latitude IS NOT NULL AND b.longitude IS NOT NULL"),
+			squirrel.Expr("earth_box(ll_to_earth(?, ?), ?) @> ll_to_earth(b.latitude, b.longitude)",
+				req.Near.Latitude, req.Near.Longitude, radius),
+			squirrel.Expr("earth_distance(ll_to_earth(?, ?), ll_to_earth(b.latitude, b.longitude)) <= ?",
+				req.Near.Latitude, req.Near.Longitude, radius),
+		)
+	}
+
+	if req.CategoryID != "" {
+		where = append(where, squirrel.Expr(`EXISTS (SELECT 1 FROM business_category_link l
+			WHERE l.business_id = b.id AND l.category_id IN (`+categoryDescendants+`))`, req.CategoryID))
+	}
+
+	for key, value := range req.Attributes {
+		where = append(where, squirrel.Expr(`EXISTS (SELECT 1 FROM business_attribute_value av
+			JOIN business_attribute a ON a.id = av.attribute_id
+			WHERE av.business_id = b.id AND a.key = ? AND av.value = ?)`, key, value))
+	}
+
+	if len(req.PriceRanges) > 0 {
+		where = append(where, squirrel.Eq{"b.price_range": req.PriceRanges})
+	}
+
+	if req.Box != nil {
+		where = append(where, squirrel.Expr("b.latitude BETWEEN ? AND ?", req.Box.MinLatitude, req.Box.MaxLatitude))
+		if req.Box.MinLongitude <= req.Box.MaxLongitude {
+			where = append(where, squirrel.Expr("b.longitude BETWEEN ? AND ?", req.Box.MinLongitude, req.Box.MaxLongitude))
+		} else {
+			where = append(where, squirrel.Expr("(b.longitude >= ? OR b.longitude <= ?)", req.Box.MinLongitude, req.Box.MaxLongitude))
+		}
+	}
+
+	return where, nil
+}
+
+// priceRangeValue stores "$" to "$$$$" as 1 to 4, an empty price range as NULL.
+func priceRangeValue(priceRange string) interface{} {
+	if priceRange == "" {
+		return nil
+	}
+
+	return len(priceRange)
+}
+
+// Update saves the listing details, the owner only changes through BusinessClaimRepo.Approve.
 func (r *BusinessRepo) Update(ctx context.Context, req entity.Business) (entity.Business, error) {
 	mp := map[string]interface{}{
-		"name":               req.Name,
-		"description":        req.Description,
-		"category_id":        req.CategoryID,
-		"address":            req.Address,
-		"latitude":           req.Latitude,
-		"longitude":          req.Longitude,
-		"contact_info":       req.ContactInfo,
-		"hours_of_operation": req.HoursOfOperation,
-		"owner_id":           req.OwnerID,
-		"updated_at":         "now()",
+		"name":         req.Name,
+		"description":  req.Description,
+		"address":      req.Address,
+		"latitude":     req.Latitude,
+		"longitude":    req.Longitude,
+		"contact_info": req.ContactInfo,
+		"time_zone":    req.TimeZone,
+		"price_range":  priceRangeValue(req.PriceRange),
+		"updated_at":   "now()",
+	}
+
+	tx, err := r.pg.Pool.Begin(ctx)
+	if err != nil {
+		return entity.Business{}, err
 	}
+	defer tx.Rollback(ctx)
 
-	qeury, args, err := r.pg.Builder.Update("businesses").SetMap(mp).Where("id = ?", req.ID).ToSql()
+	qeury, args, err := r.pg.Builder.Update("businesses").SetMap(mp).Where("id = ? AND deleted_at IS NULL", req.ID).ToSql()
 	if err != nil {
 		return entity.Business{}, err
 	}
 
-	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	tag, err := tx.Exec(ctx, qeury, args...)
 	if err != nil {
 		return entity.Business{}, err
 	}
 
-	return req, nil
+	if tag.RowsAffected() == 0 {
+		return entity.Business{}, pgx.ErrNoRows
+	}
+
+	err = r.replaceHours(ctx, tx, req)
+	if err != nil {
+		return entity.Business{}, err
+	}
+
+	err = r.replaceAttributes(ctx, tx, req)
+	if err != nil {
+		return entity.Business{}, err
+	}
+
+	err = r.replaceCategories(ctx, tx, req)
+	if err != nil {
+		return entity.Business{}, err
+	}
+
+	return req, tx.Commit(ctx)
 }
 
+// Delete hides the business together with its reviews, events, bookmarks and reports until it
+// is restored or purged.
 func (r *BusinessRepo) Delete(ctx context.Context, req entity.Id) error {
-	qeury, args, err := r.pg.Builder.Delete("businesses").Where("id = ?", req.ID).ToSql()
+	qeury, args, err := r.pg.Builder.Update("businesses").Set("deleted_at", "now()").
+		Where("id = ? AND deleted_at IS NULL", req.ID).ToSql()
 	if err != nil {
 		return err
 	}
 
-	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	tag, err := r.pg.Pool.Exec(ctx, qeury, args...)
 	if err != nil {
 		return err
 	}
 
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
 	return nil
 }
 
+// Restore brings back a deleted business that is not purged yet.
+func (r *BusinessRepo) Restore(ctx context.Context, req entity.Id) error {
+	return restoreDeleted(ctx, r.pg, "businesses", req.ID)
+}
+
+// Purge removes the businesses deleted before the given time, ON DELETE CASCADE takes
+// everything that belongs to them.
+func (r *BusinessRepo) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	return purgeDeleted(ctx, r.pg, "businesses", deletedBefore)
+}
+
 func (r *BusinessRepo) UpdateField(ctx context.Context, req entity.UpdateFieldRequest) (entity.RowsEffected, error) {
 	mp := map[string]interface{}{}
 	response := entity.RowsEffected{}
@@ -273,3 +707,63 @@ func (r *BusinessRepo) UpdateField(ctx context.Context, req entity.UpdateFieldRe
 
 	return response, nil
 }
+
+// recalculateRatingsQuery rebuilds the aggregates kept by the reviews_rating_aggregate trigger
+// and only touches businesses where they are off.
+const recalculateRatingsQuery = `
+	UPDATE businesses b SET
+		review_count = s.review_count,
+		rating_sum = s.rating_sum,
+		rating_histogram = s.rating_histogram
+	FROM (
+		SELECT bs.id,
+			COUNT(r.id) AS review_count,
+			COALESCE(SUM(r.rating), 0) AS rating_sum,
+			ARRAY[
+				COUNT(r.id) FILTER (WHERE r.rating = 1), COUNT(r.id) FILTER (WHERE r.rating = 2),
+				COUNT(r.id) FILTER (WHERE r.rating = 3), COUNT(r.id) FILTER (WHERE r.rating = 4),
+				COUNT(r.id) FILTER (WHERE r.rating = 5)
+			]::integer[] AS rating_histogram
+		FROM businesses bs
+		LEFT JOIN reviews r ON r.business_id = bs.id AND r.deleted_at IS NULL
+		GROUP BY bs.id
+	) s
+	WHERE b.id = s.id
+		AND (b.review_count, b.rating_sum, b.rating_histogram) IS DISTINCT FROM (s.review_count, s.rating_sum, s.rating_histogram)`
+
+// RecalculateRatings rebuilds the rating aggregates from the reviews and returns how many
+// businesses were corrected. Writes to reviews wait until it is done.
+func (r *BusinessRepo) RecalculateRatings(ctx context.Context) (int, error) {
+	tx, err := r.pg.Pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, "LOCK TABLE reviews IN SHARE MODE")
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := tx.Exec(ctx, recalculateRatingsQuery)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(n.RowsAffected()), tx.Commit(ctx)
+}
+
+// TimeZoneExists reports whether postgres knows the time zone, the hours are evaluated with
+// AT TIME ZONE in every business list.
+func (r *BusinessRepo) TimeZoneExists(ctx context.Context, name string) (bool, error) {
+	var exists bool
+
+	qeury, args, err := r.pg.Builder.Select("EXISTS (SELECT 1 FROM pg_timezone_names WHERE name = ?)", name).ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	err = r.pg.Pool.QueryRow(ctx, qeury, args...).Scan(&exists)
+
+	return exists, err
+}
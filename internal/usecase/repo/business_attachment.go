@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -11,8 +12,32 @@ import (
 	"github.com/Akorm0181/yelp/pkg/postgres"
 	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 )
 
+const businessAttachmentColumns = `id, business_id, filepath, content_type, position, is_cover, caption, uploaded_by, created_at, updated_at`
+
+func scanBusinessAttachment(row pgx.Row) (entity.BusinessAttachment, error) {
+	var (
+		item                 entity.BusinessAttachment
+		caption, uploadedBy  sql.NullString
+		createdAt, updatedAt time.Time
+	)
+
+	err := row.Scan(&item.Id, &item.BusinessId, &item.FilePath, &item.ContentType, &item.Position, &item.IsCover,
+		&caption, &uploadedBy, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.BusinessAttachment{}, err
+	}
+
+	item.Caption = caption.String
+	item.UploadedBy = uploadedBy.String
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return item, nil
+}
+
 type BusinessAttachmentRepo struct {
 	pg     *postgres.Postgres
 	config *config.Config
@@ -31,19 +56,17 @@ func NewBusinessAttachmentRepo(pg *postgres.Postgres, config *config.Config, log
 func (r *BusinessAttachmentRepo) Create(ctx context.Context, req entity.BusinessAttachment) (entity.BusinessAttachment, error) {
 	req.Id = uuid.NewString()
 
+	// new attachments go to the end of the gallery
 	qeury, args, err := r.pg.Builder.Insert("business_attachment").
-		Columns(`id, business_id, filepath, content_type`).
-		Values(req.Id, req.BusinessId, req.FilePath, req.ContentType).ToSql()
+		Columns(`id, business_id, filepath, content_type, caption, uploaded_by, position`).
+		Values(req.Id, req.BusinessId, req.FilePath, req.ContentType, nullString(req.Caption), nullString(req.UploadedBy),
+			squirrel.Expr("(SELECT COALESCE(MAX(position) + 1, 0) FROM business_attachment WHERE business_id = ?)", req.BusinessId)).
+		Suffix("RETURNING " + businessAttachmentColumns).ToSql()
 	if err != nil {
 		return entity.BusinessAttachment{}, err
 	}
 
-	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
-	if err != nil {
-		return entity.BusinessAttachment{}, err
-	}
-
-	return req, nil
+	return scanBusinessAttachment(r.pg.Pool.QueryRow(ctx, qeury, args...))
 }
 
 func (r *BusinessAttachmentRepo) MultipleUpsert(ctx context.Context, req entity.BusinessAttachmentMultipleInsertRequest) ([]entity.BusinessAttachment, error) {
@@ -56,15 +79,30 @@ func (r *BusinessAttachmentRepo) MultipleUpsert(ctx context.Context, req entity.
 	defer tx.Rollback(ctx)
 
 	insertQuery := r.pg.Builder.Insert("business_attachment").
-		Columns(`id, business_id, filepath, content_type`)
+		Columns(`id, business_id, filepath, content_type, caption, uploaded_by, position`)
 
+	// the order of req.Attachments becomes the gallery order
 	for i, attachment := range req.Attachments {
 		if attachment.Id == "" {
 			hasNewAttachment = true
 
 			attachment.Id = uuid.NewString()
 			req.Attachments[i].Id = attachment.Id
-			insertQuery = insertQuery.Values(attachment.Id, req.BusinessId, attachment.FilePath, attachment.ContentType)
+			insertQuery = insertQuery.Values(attachment.Id, req.BusinessId, attachment.FilePath, attachment.ContentType,
+				nullString(attachment.Caption), nullString(req.UploadedBy), i)
+			continue
+		}
+
+		query, args, err := r.pg.Builder.Update("business_attachment").Set("position", i).
+			Where(squirrel.Eq{"id": attachment.Id, "business_id": req.BusinessId}).ToSql()
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.Exec(ctx, query, args...)
+		if err != nil {
+			r.logger.Error("error while ordering business_attachments", err)
+			return nil, err
 		}
 	}
 
@@ -136,7 +174,7 @@ func (r *BusinessAttachmentRepo) MultipleUpsert(ctx context.Context, req entity.
 
 	attachments, err := r.GetList(ctx, entity.GetListFilter{
 		Page:  1,
-		Limit: 10,
+		Limit: config.MaxGalleryAttachments,
 		Filters: []entity.Filter{
 			{
 				Column: "business_id",
@@ -144,6 +182,10 @@ func (r *BusinessAttachmentRepo) MultipleUpsert(ctx context.Context, req entity.
 				Value:  req.BusinessId,
 			},
 		},
+		OrderBy: []entity.OrderBy{
+			{Column: "position", Order: "ASC"},
+			{Column: "created_at", Order: "ASC"},
+		},
 	})
 	if err != nil {
 		r.logger.Error("error while getting business_attachments", err)
@@ -154,13 +196,8 @@ func (r *BusinessAttachmentRepo) MultipleUpsert(ctx context.Context, req entity.
 }
 
 func (r *BusinessAttachmentRepo) GetSingle(ctx context.Context, req entity.Id) (entity.BusinessAttachment, error) {
-	response := entity.BusinessAttachment{}
-	var (
-		createdAt, updatedAt time.Time
-	)
-
 	qeuryBuilder := r.pg.Builder.
-		Select(`id, business_id, filepath, content_type, created_at, updated_at`).
+		Select(businessAttachmentColumns).
 		From("business_attachment")
 
 	switch {
@@ -175,26 +212,14 @@ func (r *BusinessAttachmentRepo) GetSingle(ctx context.Context, req entity.Id) (
 		return entity.BusinessAttachment{}, err
 	}
 
-	err = r.pg.Pool.QueryRow(ctx, qeury, args...).
-		Scan(&response.Id, &response.BusinessId, &response.FilePath, &response.ContentType, &createdAt, &updatedAt)
-	if err != nil {
-		return entity.BusinessAttachment{}, err
-	}
-
-	response.CreatedAt = createdAt.Format(time.RFC3339)
-	response.UpdatedAt = updatedAt.Format(time.RFC3339)
-
-	return response, nil
+	return scanBusinessAttachment(r.pg.Pool.QueryRow(ctx, qeury, args...))
 }
 
 func (r *BusinessAttachmentRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.BusinessAttachmentList, error) {
-	var (
-		response             = entity.BusinessAttachmentList{}
-		createdAt, updatedAt time.Time
-	)
+	response := entity.BusinessAttachmentList{}
 
 	qeuryBuilder := r.pg.Builder.
-		Select(`id, business_id, filepath, content_type, created_at, updated_at`).
+		Select(businessAttachmentColumns).
 		From("business_attachment")
 
 	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
@@ -211,15 +236,11 @@ func (r *BusinessAttachmentRepo) GetList(ctx context.Context, req entity.GetList
 	defer rows.Close()
 
 	for rows.Next() {
-		var item entity.BusinessAttachment
-		err = rows.Scan(&item.Id, &item.BusinessId, &item.FilePath, &item.ContentType, &createdAt, &updatedAt)
+		item, err := scanBusinessAttachment(rows)
 		if err != nil {
 			return response, err
 		}
 
-		item.CreatedAt = createdAt.Format(time.RFC3339)
-		item.UpdatedAt = updatedAt.Format(time.RFC3339)
-
 		response.Items = append(response.Items, item)
 	}
 
@@ -252,8 +273,9 @@ func (r *BusinessAttachmentRepo) Delete(ctx context.Context, req entity.Id) erro
 
 func (r *BusinessAttachmentRepo) Update(ctx context.Context, req entity.BusinessAttachment) (entity.BusinessAttachment, error) {
 	mp := map[string]interface{}{
-		"filepath":   req.FilePath,
-		"updated_at": "now()",
+		"filepath":    req.FilePath,
+		"uploaded_by": nullString(req.UploadedBy),
+		"updated_at":  "now()",
 	}
 
 	qeury, args, err := r.pg.Builder.Update("business_attachment").SetMap(mp).Where("id = ?", req.Id).ToSql()
@@ -268,3 +290,84 @@ func (r *BusinessAttachmentRepo) Update(ctx context.Context, req entity.Business
 
 	return req, nil
 }
+
+// UpdateCaption sets the caption of a single attachment, an empty caption removes it.
+func (r *BusinessAttachmentRepo) UpdateCaption(ctx context.Context, req entity.BusinessAttachment) (entity.BusinessAttachment, error) {
+	qeury, args, err := r.pg.Builder.Update("business_attachment").
+		SetMap(map[string]interface{}{
+			"caption":    nullString(req.Caption),
+			"updated_at": "now()",
+		}).
+		Where(squirrel.Eq{"id": req.Id, "business_id": req.BusinessId}).
+		Suffix("RETURNING " + businessAttachmentColumns).ToSql()
+	if err != nil {
+		return entity.BusinessAttachment{}, err
+	}
+
+	return scanBusinessAttachment(r.pg.Pool.QueryRow(ctx, qeury, args...))
+}
+
+// Reorder gives the attachments of a business the positions of ids. The caller makes sure
+// ids holds every attachment of the business exactly once.
+func (r *BusinessAttachmentRepo) Reorder(ctx context.Context, businessID string, ids []string) error {
+	tx, err := r.pg.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for i, id := range ids {
+		qeury, args, err := r.pg.Builder.Update("business_attachment").
+			Set("position", i).
+			Where(squirrel.Eq{"id": id, "business_id": businessID}).ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, qeury, args...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// SetCover makes an image the cover of its business, replacing the previous cover.
+func (r *BusinessAttachmentRepo) SetCover(ctx context.Context, businessID, attachmentID string) error {
+	tx, err := r.pg.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qeury, args, err := r.pg.Builder.Update("business_attachment").
+		Set("is_cover", false).
+		Where(squirrel.Eq{"business_id": businessID, "is_cover": true}).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	qeury, args, err = r.pg.Builder.Update("business_attachment").
+		Set("is_cover", true).
+		Where(squirrel.Eq{"id": attachmentID, "business_id": businessID, "content_type": "image"}).ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,249 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/Akorm0181/yelp/pkg/logger"
+	"github.com/Akorm0181/yelp/pkg/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+type BusinessAttributeRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewBusinessAttributeRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *BusinessAttributeRepo {
+	return &BusinessAttributeRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+const businessAttributeColumns = `id, key, name, value_type, options, created_at, updated_at`
+
+func scanBusinessAttribute(row pgx.Row) (entity.BusinessAttribute, error) {
+	var (
+		item                 entity.BusinessAttribute
+		createdAt, updatedAt time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.Key, &item.Name, &item.ValueType, &item.Options, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.BusinessAttribute{}, err
+	}
+
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return item, nil
+}
+
+func (r *BusinessAttributeRepo) Create(ctx context.Context, req entity.BusinessAttribute) (entity.BusinessAttribute, error) {
+	req.ID = uuid.NewString()
+
+	qeury, args, err := r.pg.Builder.Insert("business_attribute").
+		Columns(`id, key, name, value_type, options`).
+		Values(req.ID, req.Key, req.Name, req.ValueType, req.Options).
+		Suffix("RETURNING " + businessAttributeColumns).ToSql()
+	if err != nil {
+		return entity.BusinessAttribute{}, err
+	}
+
+	return scanBusinessAttribute(r.pg.Pool.QueryRow(ctx, qeury, args...))
+}
+
+func (r *BusinessAttributeRepo) GetSingle(ctx context.Context, req entity.Id) (entity.BusinessAttribute, error) {
+	qeury, args, err := r.pg.Builder.Select(businessAttributeColumns).From("business_attribute").
+		Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.BusinessAttribute{}, err
+	}
+
+	return scanBusinessAttribute(r.pg.Pool.QueryRow(ctx, qeury, args...))
+}
+
+func (r *BusinessAttributeRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.BusinessAttributeList, error) {
+	var response = entity.BusinessAttributeList{}
+
+	qeuryBuilder := r.pg.Builder.Select(businessAttributeColumns).From("business_attribute")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanBusinessAttribute(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items = append(response.Items, item)
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("business_attribute").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.Pool.QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// GetByCategories returns the attributes businesses of the categories can have, including the
+// ones inherited from the categories above them.
+func (r *BusinessAttributeRepo) GetByCategories(ctx context.Context, categoryIDs []string) ([]entity.BusinessAttribute, error) {
+	var response = []entity.BusinessAttribute{}
+
+	qeury, args, err := r.pg.Builder.Select(businessAttributeColumns).From("business_attribute").
+		Where(`id IN (SELECT attribute_id FROM business_category_attribute WHERE category_id IN (
+			WITH RECURSIVE a AS (
+				SELECT id, parent_id FROM business_categories WHERE id = ANY(?)
+				UNION
+				SELECT c.id, c.parent_id FROM business_categories c JOIN a ON c.id = a.parent_id
+			) SELECT id FROM a))`, categoryIDs).
+		OrderBy("name").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanBusinessAttribute(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		response = append(response, item)
+	}
+
+	return response, rows.Err()
+}
+
+// Update changes the name and options, values that are no longer an option are removed
+// from the businesses. The key and the value type can't change.
+func (r *BusinessAttributeRepo) Update(ctx context.Context, req entity.BusinessAttribute) (entity.BusinessAttribute, error) {
+	tx, err := r.pg.Pool.Begin(ctx)
+	if err != nil {
+		return entity.BusinessAttribute{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qeury, args, err := r.pg.Builder.Update("business_attribute").
+		SetMap(map[string]interface{}{
+			"name":       req.Name,
+			"options":    req.Options,
+			"updated_at": "now()",
+		}).
+		Where("id = ?", req.ID).
+		Suffix("RETURNING " + businessAttributeColumns).ToSql()
+	if err != nil {
+		return entity.BusinessAttribute{}, err
+	}
+
+	attribute, err := scanBusinessAttribute(tx.QueryRow(ctx, qeury, args...))
+	if err != nil {
+		return entity.BusinessAttribute{}, err
+	}
+
+	if attribute.ValueType == "enum" {
+		qeury, args, err = r.pg.Builder.Delete("business_attribute_value").
+			Where("attribute_id = ? AND NOT value = ANY(?)", attribute.ID, attribute.Options).ToSql()
+		if err != nil {
+			return entity.BusinessAttribute{}, err
+		}
+
+		_, err = tx.Exec(ctx, qeury, args...)
+		if err != nil {
+			return entity.BusinessAttribute{}, err
+		}
+	}
+
+	return attribute, tx.Commit(ctx)
+}
+
+func (r *BusinessAttributeRepo) Delete(ctx context.Context, req entity.Id) error {
+	qeury, args, err := r.pg.Builder.Delete("business_attribute").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// SetCategoryAttributes replaces the attributes of a category.
+func (r *BusinessAttributeRepo) SetCategoryAttributes(ctx context.Context, categoryID string, attributeIDs []string) ([]entity.BusinessAttribute, error) {
+	tx, err := r.pg.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qeury, args, err := r.pg.Builder.Delete("business_category_attribute").Where("category_id = ?", categoryID).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(attributeIDs) > 0 {
+		insert := r.pg.Builder.Insert("business_category_attribute").Columns(`category_id, attribute_id`)
+		for _, id := range attributeIDs {
+			insert = insert.Values(categoryID, id)
+		}
+
+		qeury, args, err = insert.Suffix("ON CONFLICT DO NOTHING").ToSql()
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.Exec(ctx, qeury, args...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByCategories(ctx, []string{categoryID})
+}
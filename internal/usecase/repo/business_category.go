@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -9,7 +10,9 @@ import (
 	"github.com/Akorm0181/yelp/internal/entity"
 	"github.com/Akorm0181/yelp/pkg/logger"
 	"github.com/Akorm0181/yelp/pkg/postgres"
+	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 )
 
 type BusinessCategoryRepo struct {
@@ -27,39 +30,61 @@ func NewBusinessCategoryRepo(pg *postgres.Postgres, config *config.Config, logge
 	}
 }
 
-func (r *BusinessCategoryRepo) Create(ctx context.Context, req entity.BusinessCategory) (entity.BusinessCategory, error) {
-	req.ID = uuid.NewString()
+// categoryDescendants selects the category given as its only argument and every category below it.
+const categoryDescendants = `WITH RECURSIVE d AS (
+		SELECT id FROM business_categories WHERE id = ?
+		UNION
+		SELECT c.id FROM business_categories c JOIN d ON c.parent_id = d.id
+	) SELECT id FROM d`
 
-	qeury, args, err := r.pg.Builder.Insert("business_categories").
-		Columns(`id, name`).
-		Values(req.ID, req.Name).ToSql()
+const businessCategoryColumns = `id, parent_id, name, slug, icon, created_at, updated_at`
+
+func scanBusinessCategory(row pgx.Row) (entity.BusinessCategory, error) {
+	var (
+		item                 entity.BusinessCategory
+		parentID, icon       sql.NullString
+		createdAt, updatedAt sql.NullTime
+	)
+
+	err := row.Scan(&item.ID, &parentID, &item.Name, &item.Slug, &icon, &createdAt, &updatedAt)
 	if err != nil {
 		return entity.BusinessCategory{}, err
 	}
 
-	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	item.ParentID = parentID.String
+	item.Icon = icon.String
+	item.CreatedAt = createdAt.Time.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Time.Format(time.RFC3339)
+
+	return item, nil
+}
+
+func (r *BusinessCategoryRepo) Create(ctx context.Context, req entity.BusinessCategory) (entity.BusinessCategory, error) {
+	req.ID = uuid.NewString()
+
+	qeury, args, err := r.pg.Builder.Insert("business_categories").
+		Columns(`id, parent_id, name, slug, icon`).
+		Values(req.ID, nullString(req.ParentID), req.Name, req.Slug, nullString(req.Icon)).
+		Suffix("RETURNING " + businessCategoryColumns).ToSql()
 	if err != nil {
 		return entity.BusinessCategory{}, err
 	}
 
-	return req, nil
+	return scanBusinessCategory(r.pg.Pool.QueryRow(ctx, qeury, args...))
 }
 
 func (r *BusinessCategoryRepo) GetSingle(ctx context.Context, req entity.BusinessCategorySingleRequest) (entity.BusinessCategory, error) {
-	response := entity.BusinessCategory{}
-	var (
-		createdAt, updatedAt time.Time
-	)
-
 	qeuryBuilder := r.pg.Builder.
-		Select(`id, name, created_at, updated_at`).
+		Select(businessCategoryColumns).
 		From("business_categories")
 
 	switch {
 	case req.ID != "":
 		qeuryBuilder = qeuryBuilder.Where("id = ?", req.ID)
-	case req.ID != "":
-		qeuryBuilder = qeuryBuilder.Where("name = ?", req.Name)
+	case req.Slug != "":
+		qeuryBuilder = qeuryBuilder.Where("slug = ?", req.Slug)
+	case req.Name != "":
+		qeuryBuilder = qeuryBuilder.Where("name = ?", req.Name).OrderBy("parent_id NULLS FIRST").Limit(1)
 	default:
 		return entity.BusinessCategory{}, fmt.Errorf("GetSingle - invalid request")
 	}
@@ -69,26 +94,16 @@ func (r *BusinessCategoryRepo) GetSingle(ctx context.Context, req entity.Busines
 		return entity.BusinessCategory{}, err
 	}
 
-	err = r.pg.Pool.QueryRow(ctx, qeury, args...).
-		Scan(&response.ID, &response.Name, &createdAt, &updatedAt)
-	if err != nil {
-		return entity.BusinessCategory{}, err
-	}
-
-	response.CreatedAt = createdAt.Format(time.RFC3339)
-	response.UpdatedAt = updatedAt.Format(time.RFC3339)
-
-	return response, nil
+	return scanBusinessCategory(r.pg.Pool.QueryRow(ctx, qeury, args...))
 }
 
 func (r *BusinessCategoryRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.BusinessCategoryList, error) {
 	var (
-		response             = entity.BusinessCategoryList{}
-		createdAt, updatedAt time.Time
+		response = entity.BusinessCategoryList{}
 	)
 
 	qeuryBuilder := r.pg.Builder.
-		Select(`id, name, created_at, updated_at`).
+		Select(businessCategoryColumns).
 		From("business_categories")
 
 	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
@@ -105,15 +120,11 @@ func (r *BusinessCategoryRepo) GetList(ctx context.Context, req entity.GetListFi
 	defer rows.Close()
 
 	for rows.Next() {
-		var item entity.BusinessCategory
-		err = rows.Scan(&item.ID, &item.Name, &createdAt, &updatedAt)
+		item, err := scanBusinessCategory(rows)
 		if err != nil {
 			return response, err
 		}
 
-		item.CreatedAt = createdAt.Format(time.RFC3339)
-		item.UpdatedAt = updatedAt.Format(time.RFC3339)
-
 		response.Items = append(response.Items, item)
 	}
 
@@ -130,23 +141,143 @@ func (r *BusinessCategoryRepo) GetList(ctx context.Context, req entity.GetListFi
 	return response, nil
 }
 
+// GetTree returns every root category with the categories below it as children.
+func (r *BusinessCategoryRepo) GetTree(ctx context.Context) ([]entity.BusinessCategory, error) {
+	qeury, args, err := r.pg.Builder.Select(businessCategoryColumns).From("business_categories").
+		OrderBy("name").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	children := map[string][]entity.BusinessCategory{}
+	for rows.Next() {
+		item, err := scanBusinessCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		children[item.ParentID] = append(children[item.ParentID], item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var build func(parentID string) []entity.BusinessCategory
+	build = func(parentID string) []entity.BusinessCategory {
+		items := children[parentID]
+		for i := range items {
+			items[i].Children = build(items[i].ID)
+		}
+		return items
+	}
+
+	tree := build("")
+	if tree == nil {
+		tree = []entity.BusinessCategory{}
+	}
+
+	return tree, nil
+}
+
+// GetAncestors returns the categories above the given one, the root first.
+func (r *BusinessCategoryRepo) GetAncestors(ctx context.Context, req entity.Id) ([]entity.BusinessCategory, error) {
+	var response = []entity.BusinessCategory{}
+
+	qeury, args, err := r.pg.Builder.Select(businessCategoryColumns).
+		Prefix(`WITH RECURSIVE a AS (
+			SELECT parent_id AS id, ARRAY[id] AS path FROM business_categories WHERE id = ?
+			UNION ALL
+			SELECT c.parent_id, a.path || c.id FROM business_categories c JOIN a ON c.id = a.id
+			WHERE NOT c.id = ANY(a.path)
+		)`, req.ID).
+		From("business_categories").
+		Join("a USING (id)").
+		OrderBy("cardinality(a.path) DESC").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanBusinessCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		response = append(response, item)
+	}
+
+	return response, rows.Err()
+}
+
+// Update saves the category. Moves are serialized with a table lock and checked inside the
+// transaction, so two moves running at once can't build a loop. Returns entity.ErrCategoryCycle
+// when the new parent is the category itself or one of its children.
 func (r *BusinessCategoryRepo) Update(ctx context.Context, req entity.BusinessCategory) (entity.BusinessCategory, error) {
+	tx, err := r.pg.Pool.Begin(ctx)
+	if err != nil {
+		return entity.BusinessCategory{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	if req.ParentID != "" {
+		_, err = tx.Exec(ctx, "LOCK TABLE business_categories IN SHARE ROW EXCLUSIVE MODE")
+		if err != nil {
+			return entity.BusinessCategory{}, err
+		}
+
+		var cycle bool
+		qeury, args, err := r.pg.Builder.Select().Column(squirrel.Expr("EXISTS (SELECT 1 FROM a WHERE id = ?)", req.ID)).
+			Prefix(`WITH RECURSIVE a AS (
+				SELECT id, parent_id FROM business_categories WHERE id = ?
+				UNION
+				SELECT c.id, c.parent_id FROM business_categories c JOIN a ON c.id = a.parent_id
+			)`, req.ParentID).ToSql()
+		if err != nil {
+			return entity.BusinessCategory{}, err
+		}
+
+		err = tx.QueryRow(ctx, qeury, args...).Scan(&cycle)
+		if err != nil {
+			return entity.BusinessCategory{}, err
+		}
+
+		if cycle {
+			return entity.BusinessCategory{}, entity.ErrCategoryCycle
+		}
+	}
+
 	mp := map[string]interface{}{
+		"parent_id":  nullString(req.ParentID),
 		"name":       req.Name,
+		"slug":       req.Slug,
+		"icon":       nullString(req.Icon),
 		"updated_at": "now()",
 	}
 
-	qeury, args, err := r.pg.Builder.Update("business_categories").SetMap(mp).Where("id = ?", req.ID).ToSql()
+	qeury, args, err := r.pg.Builder.Update("business_categories").SetMap(mp).Where("id = ?", req.ID).
+		Suffix("RETURNING " + businessCategoryColumns).ToSql()
 	if err != nil {
 		return entity.BusinessCategory{}, err
 	}
 
-	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	category, err := scanBusinessCategory(tx.QueryRow(ctx, qeury, args...))
 	if err != nil {
 		return entity.BusinessCategory{}, err
 	}
 
-	return req, nil
+	return category, tx.Commit(ctx)
 }
 
 func (r *BusinessCategoryRepo) Delete(ctx context.Context, req entity.Id) error {
@@ -0,0 +1,283 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/Akorm0181/yelp/pkg/logger"
+	"github.com/Akorm0181/yelp/pkg/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+type BusinessClaimRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewBusinessClaimRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *BusinessClaimRepo {
+	return &BusinessClaimRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+const businessClaimColumns = `id, business_id, user_id, phone, note, status, reviewed_by, review_note, reviewed_at, created_at, updated_at`
+
+func scanBusinessClaim(row pgx.Row) (entity.BusinessClaim, error) {
+	var (
+		item                    entity.BusinessClaim
+		phone, note, reviewNote sql.NullString
+		reviewedBy              sql.NullString
+		reviewedAt              sql.NullTime
+		createdAt, updatedAt    time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.BusinessID, &item.UserID, &phone, &note, &item.Status,
+		&reviewedBy, &reviewNote, &reviewedAt, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.BusinessClaim{}, err
+	}
+
+	item.Phone = phone.String
+	item.Note = note.String
+	item.ReviewedBy = reviewedBy.String
+	item.ReviewNote = reviewNote.String
+	if reviewedAt.Valid {
+		item.ReviewedAt = reviewedAt.Time.Format(time.RFC3339)
+	}
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return item, nil
+}
+
+func (r *BusinessClaimRepo) Create(ctx context.Context, req entity.BusinessClaim) (entity.BusinessClaim, error) {
+	req.ID = uuid.NewString()
+
+	qeury, args, err := r.pg.Builder.Insert("business_claim").
+		Columns(`id, business_id, user_id, phone, note`).
+		Values(req.ID, req.BusinessID, req.UserID, req.Phone, req.Note).
+		Suffix("RETURNING " + businessClaimColumns).ToSql()
+	if err != nil {
+		return entity.BusinessClaim{}, err
+	}
+
+	return scanBusinessClaim(r.pg.Pool.QueryRow(ctx, qeury, args...))
+}
+
+// GetSingle returns the claim with its evidence.
+func (r *BusinessClaimRepo) GetSingle(ctx context.Context, req entity.Id) (entity.BusinessClaim, error) {
+	qeury, args, err := r.pg.Builder.Select(businessClaimColumns).
+		From("business_claim").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.BusinessClaim{}, err
+	}
+
+	claim, err := scanBusinessClaim(r.pg.Pool.QueryRow(ctx, qeury, args...))
+	if err != nil {
+		return entity.BusinessClaim{}, err
+	}
+
+	claim.Evidence = []entity.BusinessClaimEvidence{}
+
+	qeury, args, err = r.pg.Builder.Select(`id, claim_id, filepath, created_at`).
+		From("business_claim_evidence").Where("claim_id = ?", claim.ID).OrderBy("created_at").ToSql()
+	if err != nil {
+		return entity.BusinessClaim{}, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return entity.BusinessClaim{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			item      entity.BusinessClaimEvidence
+			createdAt time.Time
+		)
+
+		err = rows.Scan(&item.ID, &item.ClaimID, &item.FilePath, &createdAt)
+		if err != nil {
+			return entity.BusinessClaim{}, err
+		}
+
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+		claim.Evidence = append(claim.Evidence, item)
+	}
+
+	return claim, rows.Err()
+}
+
+func (r *BusinessClaimRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.BusinessClaimList, error) {
+	var response = entity.BusinessClaimList{}
+
+	qeuryBuilder := r.pg.Builder.Select(businessClaimColumns).From("business_claim")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanBusinessClaim(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items = append(response.Items, item)
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("business_claim").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.Pool.QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+func (r *BusinessClaimRepo) AddEvidence(ctx context.Context, claimID string, filePaths []string) ([]entity.BusinessClaimEvidence, error) {
+	var evidence []entity.BusinessClaimEvidence
+
+	insert := r.pg.Builder.Insert("business_claim_evidence").Columns(`id, claim_id, filepath`)
+	for _, filePath := range filePaths {
+		item := entity.BusinessClaimEvidence{
+			ID:        uuid.NewString(),
+			ClaimID:   claimID,
+			FilePath:  filePath,
+			CreatedAt: time.Now().Format(time.RFC3339),
+		}
+		insert = insert.Values(item.ID, item.ClaimID, item.FilePath)
+		evidence = append(evidence, item)
+	}
+
+	qeury, args, err := insert.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return evidence, nil
+}
+
+// Reject closes a pending claim. Returns pgx.ErrNoRows when the claim is not pending anymore.
+func (r *BusinessClaimRepo) Reject(ctx context.Context, req entity.BusinessClaim) (entity.BusinessClaim, error) {
+	return r.review(ctx, r.pg.Pool, req, "rejected")
+}
+
+// Approve closes a pending claim and hands the business to the claimant as a verified owner.
+// Other pending claims for the business are rejected. Returns pgx.ErrNoRows when the claim is
+// not pending anymore.
+func (r *BusinessClaimRepo) Approve(ctx context.Context, req entity.BusinessClaim) (entity.BusinessClaimApproval, error) {
+	var (
+		response      entity.BusinessClaimApproval
+		previousOwner sql.NullString
+	)
+
+	tx, err := r.pg.Pool.Begin(ctx)
+	if err != nil {
+		return response, err
+	}
+	defer tx.Rollback(ctx)
+
+	response.Claim, err = r.review(ctx, tx, req, "approved")
+	if err != nil {
+		return response, err
+	}
+
+	qeury, args, err := r.pg.Builder.Select("owner_id").From("businesses").
+		Where("id = ?", response.Claim.BusinessID).Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = tx.QueryRow(ctx, qeury, args...).Scan(&previousOwner)
+	if err != nil {
+		return response, err
+	}
+	response.PreviousOwnerID = previousOwner.String
+
+	qeury, args, err = r.pg.Builder.Update("businesses").
+		SetMap(map[string]interface{}{
+			"owner_id":    response.Claim.UserID,
+			"claim_state": "verified",
+			"verified_at": "now()",
+			"updated_at":  "now()",
+		}).
+		Where("id = ?", response.Claim.BusinessID).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	_, err = tx.Exec(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+
+	qeury, args, err = r.pg.Builder.Update("business_claim").
+		SetMap(map[string]interface{}{
+			"status":      "rejected",
+			"reviewed_by": req.ReviewedBy,
+			"review_note": "Another claim for this business was approved",
+			"reviewed_at": "now()",
+			"updated_at":  "now()",
+		}).
+		Where("business_id = ? AND status = 'pending'", response.Claim.BusinessID).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	_, err = tx.Exec(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+
+	return response, tx.Commit(ctx)
+}
+
+type queryRower interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+func (r *BusinessClaimRepo) review(ctx context.Context, db queryRower, req entity.BusinessClaim, status string) (entity.BusinessClaim, error) {
+	qeury, args, err := r.pg.Builder.Update("business_claim").
+		SetMap(map[string]interface{}{
+			"status":      status,
+			"reviewed_by": req.ReviewedBy,
+			"review_note": req.ReviewNote,
+			"reviewed_at": "now()",
+			"updated_at":  "now()",
+		}).
+		Where("id = ? AND status = 'pending'", req.ID).
+		Suffix("RETURNING " + businessClaimColumns).ToSql()
+	if err != nil {
+		return entity.BusinessClaim{}, err
+	}
+
+	return scanBusinessClaim(db.QueryRow(ctx, qeury, args...))
+}
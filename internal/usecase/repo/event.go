@@ -10,6 +10,7 @@ import (
 	"github.com/Akorm0181/yelp/pkg/logger"
 	"github.com/Akorm0181/yelp/pkg/postgres"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 )
 
 type EventRepo struct {
@@ -50,7 +51,8 @@ func (r *EventRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Event,
 	query, args, err := r.pg.Builder.
 		Select("id, business_id, name, description, date, location, created_at").
 		From("events").
-		Where("id = ?", req.ID).ToSql()
+		Where("id = ? AND deleted_at IS NULL", req.ID).
+		Where(liveBusiness).ToSql()
 	if err != nil {
 		return entity.Event{}, err
 	}
@@ -71,7 +73,9 @@ func (r *EventRepo) GetList(ctx context.Context, req entity.GetListFilter) (enti
 
 	queryBuilder := r.pg.Builder.
 		Select("id, business_id, name, description, date, location, created_at").
-		From("events")
+		From("events").
+		Where("deleted_at IS NULL").
+		Where(liveBusiness)
 
 	for _, filter := range req.Filters {
 		if filter.Column == "business_id" && filter.Type == "eq" && filter.Value != "" {
@@ -111,6 +115,8 @@ func (r *EventRepo) GetList(ctx context.Context, req entity.GetListFilter) (enti
 	countQuery, args, err := r.pg.Builder.
 		Select("COUNT(1)").
 		From("events").
+		Where("deleted_at IS NULL").
+		Where(liveBusiness).
 		ToSql()
 	if err != nil {
 		return response, err
@@ -145,7 +151,7 @@ func (r *EventRepo) Update(ctx context.Context, req entity.Event) (entity.Event,
 		return entity.Event{}, errors.New("no fields to update")
 	}
 
-	query, args, err := r.pg.Builder.Update("events").SetMap(mp).Where("id = ?", req.ID).ToSql()
+	query, args, err := r.pg.Builder.Update("events").SetMap(mp).Where("id = ? AND deleted_at IS NULL", req.ID).ToSql()
 	if err != nil {
 		return entity.Event{}, err
 	}
@@ -157,14 +163,34 @@ func (r *EventRepo) Update(ctx context.Context, req entity.Event) (entity.Event,
 	return r.GetSingle(ctx, entity.Id{ID: req.ID})
 }
 
+// Delete hides the event until it is restored or purged.
 func (r *EventRepo) Delete(ctx context.Context, id entity.Id) error {
-	query, args, err := r.pg.Builder.Delete("events").Where("id = ?", id).ToSql()
+	query, args, err := r.pg.Builder.Update("events").Set("deleted_at", "now()").
+		Where("id = ? AND deleted_at IS NULL", id.ID).ToSql()
 	if err != nil {
 		return err
 	}
 
-	_, err = r.pg.Pool.Exec(ctx, query, args...)
-	return err
+	tag, err := r.pg.Pool.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// Restore brings back a deleted event that is not purged yet.
+func (r *EventRepo) Restore(ctx context.Context, id entity.Id) error {
+	return restoreDeleted(ctx, r.pg, "events", id.ID)
+}
+
+// Purge removes the events deleted before the given time.
+func (r *EventRepo) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	return purgeDeleted(ctx, r.pg, "events", deletedBefore)
 }
 
 func (r *EventRepo) AddParticipant(ctx context.Context, req entity.EventParticipant) (entity.EventParticipant, error) {
@@ -1,10 +1,20 @@
 package repo
 
 import (
+	"context"
+	"strings"
+	"time"
+	"unicode"
+
 	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/Akorm0181/yelp/pkg/postgres"
 	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
 )
 
+// liveBusiness keeps the rows of deleted businesses out of tables with a business_id.
+const liveBusiness = "business_id IN (SELECT id FROM businesses WHERE deleted_at IS NULL)"
+
 func PrepareFilter(filters []entity.Filter) squirrel.And {
 	where := squirrel.And{}
 	or := squirrel.Or{}
@@ -23,8 +33,14 @@ func PrepareFilter(filters []entity.Filter) squirrel.And {
 			where = append(where, squirrel.Lt{e.Column: e.Value})
 		case "lte":
 			where = append(where, squirrel.LtOrEq{e.Column: e.Value})
+		case "isnull":
+			where = append(where, squirrel.Eq{e.Column: nil})
 		case "search":
 			or = append(or, squirrel.ILike{e.Column: "%" + e.Value + "%"})
+		case "fts":
+			if tsQuery := PrepareTsQuery(e.Value); tsQuery != "" {
+				where = append(where, squirrel.Expr(e.Column+" @@ to_tsquery('simple', ?)", tsQuery))
+			}
 		}
 	}
 
@@ -56,3 +72,63 @@ func PrepareGetListQuery(selectQuery squirrel.SelectBuilder, filterRequest entit
 
 	return selectQuery, where
 }
+
+// PrepareTsQuery turns user input into an argument for to_tsquery('simple', ...). Every word
+// has to match and the last one also matches as a prefix, so half typed input finds results.
+// Anything but letters and digits is dropped, an empty result means there is nothing to search.
+func PrepareTsQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+
+	words[len(words)-1] += ":*"
+
+	return strings.Join(words, " & ")
+}
+
+// nullString stores an empty string as NULL.
+func nullString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+
+	return value
+}
+
+// restoreDeleted clears deleted_at of a soft deleted row, pgx.ErrNoRows when there is none.
+func restoreDeleted(ctx context.Context, pg *postgres.Postgres, table, id string) error {
+	qeury, args, err := pg.Builder.Update(table).Set("deleted_at", nil).
+		Where("id = ? AND deleted_at IS NOT NULL", id).ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// purgeDeleted removes the rows that were soft deleted before the given time for good.
+func purgeDeleted(ctx context.Context, pg *postgres.Postgres, table string, deletedBefore time.Time) (int64, error) {
+	qeury, args, err := pg.Builder.Delete(table).Where("deleted_at < ?", deletedBefore).ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	tag, err := pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,354 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/Akorm0181/yelp/pkg/logger"
+	"github.com/Akorm0181/yelp/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+type MenuRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewMenuRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *MenuRepo {
+	return &MenuRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+const menuColumns = `id, business_id, version, status, created_by, published_by, published_at, created_at, updated_at`
+
+func scanMenu(row pgx.Row) (entity.Menu, error) {
+	var (
+		item                   entity.Menu
+		createdBy, publishedBy sql.NullString
+		publishedAt            sql.NullTime
+		createdAt, updatedAt   time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.BusinessID, &item.Version, &item.Status, &createdBy, &publishedBy, &publishedAt,
+		&createdAt, &updatedAt)
+	if err != nil {
+		return entity.Menu{}, err
+	}
+
+	item.CreatedBy = createdBy.String
+	item.PublishedBy = publishedBy.String
+	if publishedAt.Valid {
+		item.PublishedAt = publishedAt.Time.Format(time.RFC3339)
+	}
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return item, nil
+}
+
+// GetSingle returns a menu with its sections and items.
+func (r *MenuRepo) GetSingle(ctx context.Context, req entity.MenuSingleRequest) (entity.Menu, error) {
+	qeuryBuilder := r.pg.Builder.Select(menuColumns).From("business_menu").
+		Where("business_id = ?", req.BusinessID).Where(liveBusiness)
+
+	switch {
+	case req.Status != "":
+		qeuryBuilder = qeuryBuilder.Where("status = ?", req.Status)
+	case req.Version != 0:
+		qeuryBuilder = qeuryBuilder.Where("version = ?", req.Version)
+	default:
+		return entity.Menu{}, fmt.Errorf("GetSingle - invalid request")
+	}
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return entity.Menu{}, err
+	}
+
+	menu, err := scanMenu(r.pg.Pool.QueryRow(ctx, qeury, args...))
+	if err != nil {
+		return entity.Menu{}, err
+	}
+
+	menu.Sections, err = r.getSections(ctx, menu.ID)
+	if err != nil {
+		return entity.Menu{}, err
+	}
+
+	return menu, nil
+}
+
+func (r *MenuRepo) getSections(ctx context.Context, menuID string) ([]entity.MenuSection, error) {
+	var (
+		sections = []entity.MenuSection{}
+		index    = map[string]int{}
+	)
+
+	qeury, args, err := r.pg.Builder.Select(`id, name, description`).From("menu_section").
+		Where("menu_id = ?", menuID).OrderBy("position").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			section     = entity.MenuSection{Items: []entity.MenuItem{}}
+			description sql.NullString
+		)
+
+		err = rows.Scan(&section.ID, &section.Name, &description)
+		if err != nil {
+			return nil, err
+		}
+		section.Description = description.String
+
+		index[section.ID] = len(sections)
+		sections = append(sections, section)
+	}
+	rows.Close()
+
+	qeury, args, err = r.pg.Builder.
+		Select(`i.id, i.section_id, i.name, i.description, i.price::float8, i.currency, i.dietary_tags, i.photo_url, i.sold_out`).
+		From("menu_item i").
+		Join("menu_section s ON s.id = i.section_id").
+		Where("s.menu_id = ?", menuID).
+		OrderBy("i.position").ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err = r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			item                  entity.MenuItem
+			sectionID             string
+			description, photoURL sql.NullString
+		)
+
+		err = rows.Scan(&item.ID, &sectionID, &item.Name, &description, &item.Price, &item.Currency,
+			&item.DietaryTags, &photoURL, &item.SoldOut)
+		if err != nil {
+			return nil, err
+		}
+		item.Description = description.String
+		item.PhotoURL = photoURL.String
+
+		i := index[sectionID]
+		sections[i].Items = append(sections[i].Items, item)
+	}
+
+	return sections, rows.Err()
+}
+
+// GetList lists menu versions without their content.
+func (r *MenuRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.MenuList, error) {
+	var response = entity.MenuList{}
+
+	qeuryBuilder := r.pg.Builder.Select(menuColumns).From("business_menu")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanMenu(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items = append(response.Items, item)
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("business_menu").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.Pool.QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// SaveDraft replaces the content of the draft menu of req.BusinessID, the draft is created as
+// the next version if the business has none.
+func (r *MenuRepo) SaveDraft(ctx context.Context, req entity.Menu) (entity.Menu, error) {
+	tx, err := r.pg.Pool.Begin(ctx)
+	if err != nil {
+		return entity.Menu{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qeury, args, err := r.pg.Builder.Select("id").From("business_menu").
+		Where(squirrel.Eq{"business_id": req.BusinessID, "status": "draft"}).
+		Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		return entity.Menu{}, err
+	}
+
+	err = tx.QueryRow(ctx, qeury, args...).Scan(&req.ID)
+	switch err {
+	case nil:
+		qeury, args, err = r.pg.Builder.Delete("menu_section").Where("menu_id = ?", req.ID).ToSql()
+		if err != nil {
+			return entity.Menu{}, err
+		}
+
+		_, err = tx.Exec(ctx, qeury, args...)
+		if err != nil {
+			return entity.Menu{}, err
+		}
+
+		qeury, args, err = r.pg.Builder.Update("business_menu").Set("updated_at", "now()").Where("id = ?", req.ID).ToSql()
+	case pgx.ErrNoRows:
+		req.ID = uuid.NewString()
+		qeury, args, err = r.pg.Builder.Insert("business_menu").
+			Columns(`id, business_id, version, status, created_by`).
+			Values(req.ID, req.BusinessID,
+				squirrel.Expr("(SELECT COALESCE(MAX(version), 0) + 1 FROM business_menu WHERE business_id = ?)", req.BusinessID),
+				"draft", nullString(req.CreatedBy)).ToSql()
+	default:
+		return entity.Menu{}, err
+	}
+	if err != nil {
+		return entity.Menu{}, err
+	}
+
+	_, err = tx.Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.Menu{}, err
+	}
+
+	for i, section := range req.Sections {
+		sectionID := uuid.NewString()
+
+		qeury, args, err = r.pg.Builder.Insert("menu_section").
+			Columns(`id, menu_id, name, description, position`).
+			Values(sectionID, req.ID, section.Name, nullString(section.Description), i).ToSql()
+		if err != nil {
+			return entity.Menu{}, err
+		}
+
+		_, err = tx.Exec(ctx, qeury, args...)
+		if err != nil {
+			return entity.Menu{}, err
+		}
+
+		if len(section.Items) == 0 {
+			continue
+		}
+
+		insert := r.pg.Builder.Insert("menu_item").
+			Columns(`id, section_id, name, description, price, currency, dietary_tags, photo_url, sold_out, position`)
+		for j, item := range section.Items {
+			if item.DietaryTags == nil {
+				item.DietaryTags = []string{}
+			}
+			insert = insert.Values(uuid.NewString(), sectionID, item.Name, nullString(item.Description), item.Price,
+				item.Currency, item.DietaryTags, nullString(item.PhotoURL), item.SoldOut, j)
+		}
+
+		qeury, args, err = insert.ToSql()
+		if err != nil {
+			return entity.Menu{}, err
+		}
+
+		_, err = tx.Exec(ctx, qeury, args...)
+		if err != nil {
+			return entity.Menu{}, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return entity.Menu{}, err
+	}
+
+	return r.GetSingle(ctx, entity.MenuSingleRequest{BusinessID: req.BusinessID, Status: "draft"})
+}
+
+// Publish makes the draft the menu customers see and archives the one that was published.
+// It returns pgx.ErrNoRows when the business has no draft.
+func (r *MenuRepo) Publish(ctx context.Context, businessID, userID string) (entity.Menu, error) {
+	tx, err := r.pg.Pool.Begin(ctx)
+	if err != nil {
+		return entity.Menu{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qeury, args, err := r.pg.Builder.Update("business_menu").
+		SetMap(map[string]interface{}{
+			"status":     "archived",
+			"updated_at": "now()",
+		}).
+		Where(squirrel.Eq{"business_id": businessID, "status": "published"}).ToSql()
+	if err != nil {
+		return entity.Menu{}, err
+	}
+
+	_, err = tx.Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.Menu{}, err
+	}
+
+	qeury, args, err = r.pg.Builder.Update("business_menu").
+		SetMap(map[string]interface{}{
+			"status":       "published",
+			"published_by": nullString(userID),
+			"published_at": "now()",
+			"updated_at":   "now()",
+		}).
+		Where(squirrel.Eq{"business_id": businessID, "status": "draft"}).ToSql()
+	if err != nil {
+		return entity.Menu{}, err
+	}
+
+	tag, err := tx.Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.Menu{}, err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.Menu{}, pgx.ErrNoRows
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return entity.Menu{}, err
+	}
+
+	return r.GetSingle(ctx, entity.MenuSingleRequest{BusinessID: businessID, Status: "published"})
+}
@@ -0,0 +1,203 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/Akorm0181/yelp/pkg/logger"
+	"github.com/Akorm0181/yelp/pkg/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+type OwnerRequestRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewOwnerRequestRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *OwnerRequestRepo {
+	return &OwnerRequestRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+const ownerRequestColumns = `id, user_id, business_name, phone, note, status, reviewed_by, review_note, reviewed_at, created_at, updated_at`
+
+func scanOwnerRequest(row pgx.Row) (entity.OwnerRequest, error) {
+	var (
+		item                    entity.OwnerRequest
+		phone, note, reviewNote sql.NullString
+		reviewedBy              sql.NullString
+		reviewedAt              sql.NullTime
+		createdAt, updatedAt    time.Time
+	)
+
+	err := row.Scan(&item.ID, &item.UserID, &item.BusinessName, &phone, &note, &item.Status,
+		&reviewedBy, &reviewNote, &reviewedAt, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.OwnerRequest{}, err
+	}
+
+	item.Phone = phone.String
+	item.Note = note.String
+	item.ReviewedBy = reviewedBy.String
+	item.ReviewNote = reviewNote.String
+	if reviewedAt.Valid {
+		item.ReviewedAt = reviewedAt.Time.Format(time.RFC3339)
+	}
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+	item.UpdatedAt = updatedAt.Format(time.RFC3339)
+
+	return item, nil
+}
+
+func (r *OwnerRequestRepo) Create(ctx context.Context, req entity.OwnerRequest) (entity.OwnerRequest, error) {
+	req.ID = uuid.NewString()
+
+	qeury, args, err := r.pg.Builder.Insert("owner_request").
+		Columns(`id, user_id, business_name, phone, note`).
+		Values(req.ID, req.UserID, req.BusinessName, req.Phone, req.Note).
+		Suffix("RETURNING " + ownerRequestColumns).ToSql()
+	if err != nil {
+		return entity.OwnerRequest{}, err
+	}
+
+	return scanOwnerRequest(r.pg.Pool.QueryRow(ctx, qeury, args...))
+}
+
+func (r *OwnerRequestRepo) GetSingle(ctx context.Context, req entity.Id) (entity.OwnerRequest, error) {
+	qeury, args, err := r.pg.Builder.Select(ownerRequestColumns).
+		From("owner_request").Where("id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.OwnerRequest{}, err
+	}
+
+	return scanOwnerRequest(r.pg.Pool.QueryRow(ctx, qeury, args...))
+}
+
+func (r *OwnerRequestRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.OwnerRequestList, error) {
+	var response = entity.OwnerRequestList{}
+
+	qeuryBuilder := r.pg.Builder.Select(ownerRequestColumns).From("owner_request")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanOwnerRequest(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items = append(response.Items, item)
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("owner_request").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.Pool.QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// Review moves a pending request to req.Status. Returns pgx.ErrNoRows when the request is not
+// pending anymore, so two admins can't both decide on it.
+func (r *OwnerRequestRepo) Review(ctx context.Context, req entity.OwnerRequest) (entity.OwnerRequest, error) {
+	return r.review(ctx, r.pg.Pool, req)
+}
+
+// Approve approves a pending request and gives the applicant the business_owner role in one
+// transaction. The applicant is locked and must still be an active user with the user role and
+// no deletion pending, entity.ErrOwnerApplicantIneligible is returned otherwise.
+func (r *OwnerRequestRepo) Approve(ctx context.Context, req entity.OwnerRequest) (entity.OwnerRequest, error) {
+	var (
+		userType, userRole, status string
+		deletePending              bool
+	)
+
+	tx, err := r.pg.Pool.Begin(ctx)
+	if err != nil {
+		return entity.OwnerRequest{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	req.Status = "approved"
+	request, err := r.review(ctx, tx, req)
+	if err != nil {
+		return entity.OwnerRequest{}, err
+	}
+
+	qeury, args, err := r.pg.Builder.Select("user_type, user_role, status, delete_after IS NOT NULL").
+		From("users").
+		Where("id = ?", request.UserID).
+		Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		return entity.OwnerRequest{}, err
+	}
+
+	err = tx.QueryRow(ctx, qeury, args...).Scan(&userType, &userRole, &status, &deletePending)
+	if err != nil {
+		return entity.OwnerRequest{}, err
+	}
+
+	if userType != "user" || userRole != "user" || status != "active" || deletePending {
+		return entity.OwnerRequest{}, entity.ErrOwnerApplicantIneligible
+	}
+
+	qeury, args, err = r.pg.Builder.Update("users").
+		SetMap(map[string]interface{}{
+			"user_role":  "business_owner",
+			"updated_at": "now()",
+		}).
+		Where("id = ?", request.UserID).ToSql()
+	if err != nil {
+		return entity.OwnerRequest{}, err
+	}
+
+	_, err = tx.Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.OwnerRequest{}, err
+	}
+
+	return request, tx.Commit(ctx)
+}
+
+func (r *OwnerRequestRepo) review(ctx context.Context, db queryRower, req entity.OwnerRequest) (entity.OwnerRequest, error) {
+	qeury, args, err := r.pg.Builder.Update("owner_request").
+		SetMap(map[string]interface{}{
+			"status":      req.Status,
+			"reviewed_by": req.ReviewedBy,
+			"review_note": req.ReviewNote,
+			"reviewed_at": "now()",
+			"updated_at":  "now()",
+		}).
+		Where("id = ? AND status = 'pending'", req.ID).
+		Suffix("RETURNING " + ownerRequestColumns).ToSql()
+	if err != nil {
+		return entity.OwnerRequest{}, err
+	}
+
+	return scanOwnerRequest(db.QueryRow(ctx, qeury, args...))
+}
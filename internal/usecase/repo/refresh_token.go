@@ -0,0 +1,115 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/Akorm0181/yelp/pkg/logger"
+	"github.com/Akorm0181/yelp/pkg/postgres"
+	"github.com/google/uuid"
+)
+
+type RefreshTokenRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewRefreshTokenRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *RefreshTokenRepo {
+	return &RefreshTokenRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *RefreshTokenRepo) Create(ctx context.Context, req entity.RefreshToken) (entity.RefreshToken, error) {
+	req.ID = uuid.NewString()
+
+	expiresAt, err := time.Parse(time.RFC3339, req.ExpiresAt)
+	if err != nil {
+		return entity.RefreshToken{}, err
+	}
+
+	qeury, args, err := r.pg.Builder.Insert("refresh_token").
+		Columns(`id, session_id, token_hash, expires_at`).
+		Values(req.ID, req.SessionID, req.TokenHash, expiresAt).ToSql()
+	if err != nil {
+		return entity.RefreshToken{}, err
+	}
+
+	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.RefreshToken{}, err
+	}
+
+	return req, nil
+}
+
+func (r *RefreshTokenRepo) GetSingle(ctx context.Context, req entity.RefreshTokenSingleRequest) (entity.RefreshToken, error) {
+	response := entity.RefreshToken{}
+	var (
+		createdAt, expiresAt time.Time
+		usedAt               sql.NullTime
+	)
+
+	qeuryBuilder := r.pg.Builder.
+		Select(`id, session_id, token_hash, is_revoked, used_at, expires_at, created_at`).
+		From("refresh_token")
+
+	switch {
+	case req.ID != "":
+		qeuryBuilder = qeuryBuilder.Where("id = ?", req.ID)
+	case req.TokenHash != "":
+		qeuryBuilder = qeuryBuilder.Where("token_hash = ?", req.TokenHash)
+	default:
+		return entity.RefreshToken{}, fmt.Errorf("GetSingle - invalid request")
+	}
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return entity.RefreshToken{}, err
+	}
+
+	err = r.pg.Pool.QueryRow(ctx, qeury, args...).
+		Scan(&response.ID, &response.SessionID, &response.TokenHash, &response.IsRevoked, &usedAt, &expiresAt, &createdAt)
+	if err != nil {
+		return entity.RefreshToken{}, err
+	}
+
+	response.ExpiresAt = expiresAt.Format(time.RFC3339)
+	response.CreatedAt = createdAt.Format(time.RFC3339)
+	if usedAt.Valid {
+		response.UsedAt = usedAt.Time.Format(time.RFC3339)
+	}
+
+	return response, nil
+}
+
+func (r *RefreshTokenRepo) UpdateField(ctx context.Context, req entity.UpdateFieldRequest) (entity.RowsEffected, error) {
+	mp := map[string]interface{}{}
+	response := entity.RowsEffected{}
+
+	for _, item := range req.Items {
+		mp[item.Column] = item.Value
+	}
+
+	qeury, args, err := r.pg.Builder.Update("refresh_token").SetMap(mp).Where(PrepareFilter(req.Filter)).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	n, err := r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+
+	response.RowsEffected = int(n.RowsAffected())
+
+	return response, nil
+}
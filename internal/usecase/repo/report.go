@@ -61,7 +61,7 @@ func (r *ReportRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Repor
 		return entity.Report{}, fmt.Errorf("GetSingle - invalid request")
 	}
 
-	query, args, err := queryBuilder.ToSql()
+	query, args, err := queryBuilder.Where(liveBusiness).ToSql()
 	if err != nil {
 		return entity.Report{}, err
 	}
@@ -83,7 +83,8 @@ func (r *ReportRepo) GetList(ctx context.Context, req entity.GetListFilter) (ent
 	// Start building the query
 	queryBuilder := r.pg.Builder.
 		Select(`id, user_id, business_id, reason, created_at`).
-		From("reports")
+		From("reports").
+		Where(liveBusiness)
 
 	// Apply filters (if any)
 	if req.Filters != nil {
@@ -136,7 +137,7 @@ func (r *ReportRepo) GetList(ctx context.Context, req entity.GetListFilter) (ent
 	}
 
 	// Now, count the reports based on the same filter (business_id)
-	countQueryBuilder := r.pg.Builder.Select("COUNT(1)").From("reports")
+	countQueryBuilder := r.pg.Builder.Select("COUNT(1)").From("reports").Where(liveBusiness)
 	if req.Filters != nil {
 		for _, filter := range req.Filters {
 			if filter.Column == "business_id" && filter.Type == "eq" && filter.Value != "" {
@@ -12,6 +12,7 @@ import (
 	"github.com/Akorm0181/yelp/pkg/postgres"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 )
 
 type ReviewRepo struct {
@@ -65,7 +66,7 @@ func (r *ReviewRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Revie
 		return entity.Review{}, fmt.Errorf("GetSingle - invalid request")
 	}
 
-	qeury, args, err := qeuryBuilder.ToSql()
+	qeury, args, err := qeuryBuilder.Where("deleted_at IS NULL").Where(liveBusiness).ToSql()
 	if err != nil {
 		return entity.Review{}, err
 	}
@@ -92,7 +93,9 @@ func (r *ReviewRepo) GetList(ctx context.Context, req entity.GetListFilter) (ent
 
 	queryBuilder := r.pg.Builder.
 		Select(`id, user_id, business_id, rating, comment, created_at`).
-		From("reviews")
+		From("reviews").
+		Where("deleted_at IS NULL").
+		Where(liveBusiness)
 
 	if req.Filters != nil {
 		for _, filter := range req.Filters {
@@ -131,7 +134,7 @@ func (r *ReviewRepo) GetList(ctx context.Context, req entity.GetListFilter) (ent
 		response.Items = append(response.Items, item)
 	}
 
-	countQueryBuilder := r.pg.Builder.Select("COUNT(1)").From("reviews")
+	countQueryBuilder := r.pg.Builder.Select("COUNT(1)").From("reviews").Where("deleted_at IS NULL").Where(liveBusiness)
 	if req.Filters != nil {
 		for _, filter := range req.Filters {
 			if filter.Column == "business_id" && filter.Type == "eq" && filter.Value != "" {
@@ -160,7 +163,7 @@ func (r *ReviewRepo) Update(ctx context.Context, req entity.Review) (entity.Revi
 		"updated_at": "now()",
 	}
 
-	qeury, args, err := r.pg.Builder.Update("reviews").SetMap(mp).Where("id = ?", req.ID).ToSql()
+	qeury, args, err := r.pg.Builder.Update("reviews").SetMap(mp).Where("id = ? AND deleted_at IS NULL", req.ID).ToSql()
 	if err != nil {
 		return entity.Review{}, err
 	}
@@ -173,16 +176,33 @@ func (r *ReviewRepo) Update(ctx context.Context, req entity.Review) (entity.Revi
 	return req, nil
 }
 
+// Delete hides the review until it is restored or purged, the business rating stops counting
+// it right away.
 func (r *ReviewRepo) Delete(ctx context.Context, req entity.Id) error {
-	qeury, args, err := r.pg.Builder.Delete("reviews").Where("id = ?", req.ID).ToSql()
+	qeury, args, err := r.pg.Builder.Update("reviews").Set("deleted_at", "now()").
+		Where("id = ? AND deleted_at IS NULL", req.ID).ToSql()
 	if err != nil {
 		return err
 	}
 
-	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	tag, err := r.pg.Pool.Exec(ctx, qeury, args...)
 	if err != nil {
 		return err
 	}
 
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
 	return nil
 }
+
+// Restore brings back a deleted review that is not purged yet.
+func (r *ReviewRepo) Restore(ctx context.Context, req entity.Id) error {
+	return restoreDeleted(ctx, r.pg, "reviews", req.ID)
+}
+
+// Purge removes the reviews deleted before the given time.
+func (r *ReviewRepo) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	return purgeDeleted(ctx, r.pg, "reviews", deletedBefore)
+}
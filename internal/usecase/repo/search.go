@@ -0,0 +1,198 @@
+package repo
+
+import (
+	"context"
+	"html"
+	"sort"
+	"strings"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/Akorm0181/yelp/pkg/logger"
+	"github.com/Akorm0181/yelp/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+)
+
+// SearchRepo runs the full-text search over businesses, reviews and events.
+type SearchRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewSearchRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *SearchRepo {
+	return &SearchRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+// ts_headline marks the matches with control characters, the text is HTML escaped before they
+// are turned into <mark> tags so markup in a review can't get into a snippet.
+const (
+	snippetStart    = "\x01"
+	snippetStop     = "\x02"
+	headlineOptions = `StartSel="` + snippetStart + `", StopSel="` + snippetStop + `", MaxWords=30, MinWords=10, MaxFragments=2`
+)
+
+var snippetMarks = strings.NewReplacer(snippetStart, "<mark>", snippetStop, "</mark>")
+
+func highlightSnippet(snippet string) string {
+	return snippetMarks.Replace(html.EscapeString(snippet))
+}
+
+// searchSource describes how one type of result is searched. The query is available as q.
+type searchSource struct {
+	from    string
+	join    string
+	where   string // hides soft deleted rows
+	vector  string
+	columns string // id, business_id, title, snippet
+}
+
+var searchSources = map[string]searchSource{
+	"business": {
+		from:   "businesses b",
+		where:  "b.deleted_at IS NULL",
+		vector: "b.search_vector",
+		columns: `b.id, b.id, b.name,
+			ts_headline('simple', concat_ws(' ', b.description, b.address), q, '` + headlineOptions + `')`,
+	},
+	"review": {
+		from:   "reviews r",
+		join:   "businesses b ON b.id = r.business_id",
+		where:  "r.deleted_at IS NULL AND b.deleted_at IS NULL",
+		vector: "r.search_vector",
+		columns: `r.id, r.business_id, b.name,
+			ts_headline('simple', coalesce(r.comment, ''), q, '` + headlineOptions + `')`,
+	},
+	"event": {
+		from:   "events e",
+		join:   "businesses b ON b.id = e.business_id",
+		where:  "e.deleted_at IS NULL AND b.deleted_at IS NULL",
+		vector: "e.search_vector",
+		columns: `e.id, e.business_id, coalesce(e.name, ''),
+			ts_headline('simple', concat_ws(' ', e.description, e.location), q, '` + headlineOptions + `')`,
+	},
+}
+
+// Search ranks every requested type on its own and merges the results by rank. Each type
+// is asked for enough rows to fill the requested page.
+func (r *SearchRepo) Search(ctx context.Context, req entity.SearchRequest) (entity.SearchResponse, error) {
+	var response = entity.SearchResponse{
+		Items:  []entity.SearchResult{},
+		Counts: map[string]int{},
+	}
+
+	tsQuery := PrepareTsQuery(req.Query)
+	if tsQuery == "" {
+		return response, nil
+	}
+
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+
+	offset := (req.Page - 1) * req.Limit
+
+	for _, kind := range req.Types {
+		source, ok := searchSources[kind]
+		if !ok {
+			continue
+		}
+
+		items, err := r.searchSource(ctx, kind, source, tsQuery, offset+req.Limit)
+		if err != nil {
+			return response, err
+		}
+		response.Items = append(response.Items, items...)
+
+		count, err := r.countSource(ctx, source, tsQuery)
+		if err != nil {
+			return response, err
+		}
+		response.Counts[kind] = count
+		response.Count += count
+	}
+
+	sort.SliceStable(response.Items, func(i, j int) bool {
+		return response.Items[i].Rank > response.Items[j].Rank
+	})
+
+	if offset >= len(response.Items) {
+		response.Items = []entity.SearchResult{}
+		return response, nil
+	}
+
+	response.Items = response.Items[offset:min(offset+req.Limit, len(response.Items))]
+
+	return response, nil
+}
+
+func (r *SearchRepo) searchSource(ctx context.Context, kind string, source searchSource, tsQuery string, limit int) ([]entity.SearchResult, error) {
+	var items []entity.SearchResult
+
+	qeuryBuilder := r.pg.Builder.
+		Select(source.columns, "ts_rank("+source.vector+", q)::float8 AS rank").
+		From(source.from).
+		CrossJoin("to_tsquery('simple', ?) q", tsQuery)
+
+	if source.join != "" {
+		qeuryBuilder = qeuryBuilder.Join(source.join)
+	}
+
+	qeury, args, err := qeuryBuilder.
+		Where(source.vector + " @@ q").
+		Where(source.where).
+		OrderBy("rank DESC").
+		Limit(uint64(limit)).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := entity.SearchResult{Type: kind}
+		err = rows.Scan(&item.ID, &item.BusinessID, &item.Title, &item.Snippet, &item.Rank)
+		if err != nil {
+			return nil, err
+		}
+		item.Snippet = highlightSnippet(item.Snippet)
+
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+func (r *SearchRepo) countSource(ctx context.Context, source searchSource, tsQuery string) (int, error) {
+	var count int
+
+	qeuryBuilder := r.pg.Builder.Select("COUNT(1)").From(source.from)
+
+	if source.join != "" {
+		qeuryBuilder = qeuryBuilder.Join(source.join)
+	}
+
+	qeury, args, err := qeuryBuilder.
+		Where(squirrel.Expr(source.vector+" @@ to_tsquery('simple', ?)", tsQuery)).
+		Where(source.where).ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.pg.Pool.QueryRow(ctx, qeury, args...).Scan(&count)
+
+	return count, err
+}
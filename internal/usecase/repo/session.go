@@ -3,12 +3,14 @@ package repo
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/Akorm0181/yelp/config"
 	"github.com/Akorm0181/yelp/internal/entity"
 	"github.com/Akorm0181/yelp/pkg/logger"
 	"github.com/Akorm0181/yelp/pkg/postgres"
+	rediscache "github.com/golanguzb70/redis-cache"
 	"github.com/google/uuid"
 )
 
@@ -16,14 +18,80 @@ type SessionRepo struct {
 	pg     *postgres.Postgres
 	config *config.Config
 	logger *logger.Logger
+	cache  rediscache.RedisCache
 }
 
 // New -.
-func NewSessionRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *SessionRepo {
+// Sessions are looked up on every authenticated request, so GetSingle is served from redis and
+// every write through this repo drops the cached copy, which keeps logout immediate on all replicas.
+func NewSessionRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger, cache rediscache.RedisCache) *SessionRepo {
 	return &SessionRepo{
 		pg:     pg,
 		config: config,
 		logger: logger,
+		cache:  cache,
+	}
+}
+
+func sessionCacheKey(id string) string {
+	return "session-" + id
+}
+
+// sessionVersionKey holds a token that every invalidate replaces. Cached sessions carry the token
+// that was current before they were read from the database, so a read that raced a logout writes
+// back an entry that is already outdated and never served.
+func sessionVersionKey(id string) string {
+	return "session-version-" + id
+}
+
+type cachedSession struct {
+	Version string         `json:"version"`
+	Session entity.Session `json:"session"`
+}
+
+// getCached returns the cached session and the current version, which GetSingle caches a fresh
+// read with.
+func (r *SessionRepo) getCached(ctx context.Context, id string) (entity.Session, string, bool) {
+	var cached cachedSession
+
+	version, _ := r.cache.Get(ctx, sessionVersionKey(id))
+
+	value, err := r.cache.Get(ctx, sessionCacheKey(id))
+	if err != nil || value == "" {
+		return cached.Session, version, false
+	}
+
+	if err = json.Unmarshal([]byte(value), &cached); err != nil || cached.Version != version {
+		return entity.Session{}, version, false
+	}
+
+	return cached.Session, version, true
+}
+
+func (r *SessionRepo) setCached(ctx context.Context, session entity.Session, version string) {
+	value, err := json.Marshal(cachedSession{Version: version, Session: session})
+	if err != nil {
+		return
+	}
+
+	err = r.cache.Set(ctx, sessionCacheKey(session.ID), string(value), int(config.SessionCacheTTL.Seconds()))
+	if err != nil {
+		r.logger.Error(err, "error while caching session")
+	}
+}
+
+// invalidate replaces the version before dropping the cached copy. The version outlives any entry
+// a racing read could still write.
+func (r *SessionRepo) invalidate(ctx context.Context, ids ...string) {
+	for _, id := range ids {
+		err := r.cache.Set(ctx, sessionVersionKey(id), uuid.NewString(), int(2*config.SessionCacheTTL.Seconds()))
+		if err != nil {
+			r.logger.Error(err, "error while versioning cached session")
+		}
+
+		if err = r.cache.Del(ctx, sessionCacheKey(id)); err != nil {
+			r.logger.Error(err, "error while invalidating cached session")
+		}
 	}
 }
 
@@ -52,6 +120,11 @@ func (r *SessionRepo) Create(ctx context.Context, req entity.Session) (entity.Se
 }
 
 func (r *SessionRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Session, error) {
+	cached, version, ok := r.getCached(ctx, req.ID)
+	if ok {
+		return cached, nil
+	}
+
 	response := entity.Session{}
 	var (
 		createdAt, updatedAt    time.Time
@@ -84,6 +157,8 @@ func (r *SessionRepo) GetSingle(ctx context.Context, req entity.Id) (entity.Sess
 		response.LastActiveAt = lastActiveAt.Time.Format(time.RFC3339)
 	}
 
+	r.setCached(ctx, response, version)
+
 	return response, nil
 }
 
@@ -164,6 +239,8 @@ func (r *SessionRepo) Update(ctx context.Context, req entity.Session) (entity.Se
 		return entity.Session{}, err
 	}
 
+	r.invalidate(ctx, req.ID)
+
 	return req, nil
 }
 
@@ -178,6 +255,8 @@ func (r *SessionRepo) Delete(ctx context.Context, req entity.Id) error {
 		return err
 	}
 
+	r.invalidate(ctx, req.ID)
+
 	return nil
 }
 
@@ -189,17 +268,35 @@ func (r *SessionRepo) UpdateField(ctx context.Context, req entity.UpdateFieldReq
 		mp[item.Column] = item.Value
 	}
 
-	qeury, args, err := r.pg.Builder.Update("session").SetMap(mp).Where(PrepareFilter(req.Filter)).ToSql()
+	// the filter can match any number of sessions, RETURNING tells which cached entries to drop
+	qeury, args, err := r.pg.Builder.Update("session").SetMap(mp).Where(PrepareFilter(req.Filter)).
+		Suffix("RETURNING id").ToSql()
 	if err != nil {
 		return response, err
 	}
 
-	n, err := r.pg.Pool.Exec(ctx, qeury, args...)
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
 	if err != nil {
 		return response, err
 	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			return response, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return response, err
+	}
 
-	response.RowsEffected = int(n.RowsAffected())
+	r.invalidate(ctx, ids...)
+	response.RowsEffected = len(ids)
 
 	return response, nil
 }
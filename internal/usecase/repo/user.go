@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -9,7 +10,9 @@ import (
 	"github.com/Akorm0181/yelp/internal/entity"
 	"github.com/Akorm0181/yelp/pkg/logger"
 	"github.com/Akorm0181/yelp/pkg/postgres"
+	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 )
 
 type UserRepo struct {
@@ -30,9 +33,14 @@ func NewUserRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Lo
 func (r *UserRepo) Create(ctx context.Context, req entity.User) (entity.User, error) {
 	req.ID = uuid.NewString()
 
+	var gender interface{} = req.Gender
+	if req.Gender == "" {
+		gender = squirrel.Expr("DEFAULT")
+	}
+
 	qeury, args, err := r.pg.Builder.Insert("users").
 		Columns(`id, full_name, email, username, password, user_type, user_role, status, profile_picture, gender, bio`).
-		Values(req.ID, req.FullName, req.Email, req.UserName, req.Password, req.UserType, req.UserRole, req.Status, req.ProfilePic, req.Gender, req.Bio).ToSql()
+		Values(req.ID, req.FullName, req.Email, req.UserName, req.Password, req.UserType, req.UserRole, req.Status, req.ProfilePic, gender, req.Bio).ToSql()
 	if err != nil {
 		return entity.User{}, err
 	}
@@ -49,10 +57,12 @@ func (r *UserRepo) GetSingle(ctx context.Context, req entity.UserSingleRequest)
 	response := entity.User{}
 	var (
 		createdAt, updatedAt time.Time
+		blockedUntil         sql.NullTime
+		blockReason          sql.NullString
 	)
 
 	qeuryBuilder := r.pg.Builder.
-		Select(`id, full_name, email, username, password, user_type, user_role, status, profile_picture, gender, bio, created_at, updated_at`).
+		Select(`id, full_name, email, username, password, user_type, user_role, status, profile_picture, gender, bio, blocked_until, block_reason, created_at, updated_at`).
 		From("users")
 
 	switch {
@@ -73,11 +83,16 @@ func (r *UserRepo) GetSingle(ctx context.Context, req entity.UserSingleRequest)
 
 	err = r.pg.Pool.QueryRow(ctx, qeury, args...).
 		Scan(&response.ID, &response.FullName, &response.Email, &response.UserName, &response.Password,
-			&response.UserType, &response.UserRole, &response.Status, &response.ProfilePic, &response.Gender, &response.Bio, &createdAt, &updatedAt)
+			&response.UserType, &response.UserRole, &response.Status, &response.ProfilePic, &response.Gender, &response.Bio,
+			&blockedUntil, &blockReason, &createdAt, &updatedAt)
 	if err != nil {
 		return entity.User{}, err
 	}
 
+	if blockedUntil.Valid {
+		response.BlockedUntil = blockedUntil.Time.Format(time.RFC3339)
+	}
+	response.BlockReason = blockReason.String
 	response.CreatedAt = createdAt.Format(time.RFC3339)
 	response.UpdatedAt = updatedAt.Format(time.RFC3339)
 
@@ -88,10 +103,12 @@ func (r *UserRepo) GetList(ctx context.Context, req entity.GetListFilter) (entit
 	var (
 		response             = entity.UserList{}
 		createdAt, updatedAt time.Time
+		blockedUntil         sql.NullTime
+		blockReason          sql.NullString
 	)
 
 	qeuryBuilder := r.pg.Builder.
-		Select(`id, full_name,  email, username, password, user_type, user_role, status, profile_picture, gender, bio, created_at, updated_at`).
+		Select(`id, full_name,  email, username, password, user_type, user_role, status, profile_picture, gender, bio, blocked_until, block_reason, created_at, updated_at`).
 		From("users")
 
 	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
@@ -110,11 +127,16 @@ func (r *UserRepo) GetList(ctx context.Context, req entity.GetListFilter) (entit
 	for rows.Next() {
 		var item entity.User
 		err = rows.Scan(&item.ID, &item.FullName, &item.Email, &item.UserName, &item.Password,
-			&item.UserType, &item.UserRole, &item.Status, &item.ProfilePic, &item.Gender, &item.Bio, &createdAt, &updatedAt)
+			&item.UserType, &item.UserRole, &item.Status, &item.ProfilePic, &item.Gender, &item.Bio,
+			&blockedUntil, &blockReason, &createdAt, &updatedAt)
 		if err != nil {
 			return response, err
 		}
 
+		if blockedUntil.Valid {
+			item.BlockedUntil = blockedUntil.Time.Format(time.RFC3339)
+		}
+		item.BlockReason = blockReason.String
 		item.CreatedAt = createdAt.Format(time.RFC3339)
 		item.UpdatedAt = updatedAt.Format(time.RFC3339)
 
@@ -199,3 +221,144 @@ func (r *UserRepo) UpdateField(ctx context.Context, req entity.UpdateFieldReques
 
 	return response, nil
 }
+
+// ChangeStatus sets the user's status and writes the audit entry in one transaction.
+// The block reason and expiry are kept only while the user is blocked. It returns
+// pgx.ErrNoRows when req.FromStatus is set and the user is in another status.
+func (r *UserRepo) ChangeStatus(ctx context.Context, req entity.UserStatusChange) (entity.UserStatusAudit, error) {
+	var (
+		audit = entity.UserStatusAudit{
+			ID:           uuid.NewString(),
+			UserID:       req.UserID,
+			ActorID:      req.ActorID,
+			NewStatus:    req.Status,
+			Reason:       req.Reason,
+			BlockedUntil: req.BlockedUntil,
+		}
+		blockedUntil, blockReason, actorID interface{}
+	)
+
+	if req.Status == "blocked" {
+		blockReason = req.Reason
+		if req.BlockedUntil != "" {
+			t, err := time.Parse(time.RFC3339, req.BlockedUntil)
+			if err != nil {
+				return entity.UserStatusAudit{}, err
+			}
+			blockedUntil = t
+		}
+	}
+	if req.ActorID != "" {
+		actorID = req.ActorID
+	}
+
+	tx, err := r.pg.Pool.Begin(ctx)
+	if err != nil {
+		return entity.UserStatusAudit{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qeury, args, err := r.pg.Builder.Select("status").From("users").Where("id = ?", req.UserID).Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		return entity.UserStatusAudit{}, err
+	}
+
+	err = tx.QueryRow(ctx, qeury, args...).Scan(&audit.OldStatus)
+	if err != nil {
+		return entity.UserStatusAudit{}, err
+	}
+
+	if req.FromStatus != "" && audit.OldStatus != req.FromStatus {
+		return entity.UserStatusAudit{}, pgx.ErrNoRows
+	}
+
+	qeury, args, err = r.pg.Builder.Update("users").
+		SetMap(map[string]interface{}{
+			"status":        req.Status,
+			"blocked_until": blockedUntil,
+			"block_reason":  blockReason,
+			"updated_at":    "now()",
+		}).
+		Where("id = ?", req.UserID).ToSql()
+	if err != nil {
+		return entity.UserStatusAudit{}, err
+	}
+
+	_, err = tx.Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.UserStatusAudit{}, err
+	}
+
+	var createdAt time.Time
+	qeury, args, err = r.pg.Builder.Insert("user_status_audit").
+		Columns(`id, user_id, actor_id, old_status, new_status, reason, blocked_until`).
+		Values(audit.ID, audit.UserID, actorID, audit.OldStatus, audit.NewStatus, audit.Reason, blockedUntil).
+		Suffix("RETURNING created_at").ToSql()
+	if err != nil {
+		return entity.UserStatusAudit{}, err
+	}
+
+	err = tx.QueryRow(ctx, qeury, args...).Scan(&createdAt)
+	if err != nil {
+		return entity.UserStatusAudit{}, err
+	}
+
+	audit.CreatedAt = createdAt.Format(time.RFC3339)
+
+	return audit, tx.Commit(ctx)
+}
+
+func (r *UserRepo) GetStatusHistory(ctx context.Context, req entity.GetListFilter) (entity.UserStatusAuditList, error) {
+	var (
+		response        = entity.UserStatusAuditList{}
+		createdAt       time.Time
+		blockedUntil    sql.NullTime
+		actorID, reason sql.NullString
+	)
+
+	qeuryBuilder := r.pg.Builder.
+		Select(`id, user_id, actor_id, old_status, new_status, reason, blocked_until, created_at`).
+		From("user_status_audit")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entity.UserStatusAudit
+		err = rows.Scan(&item.ID, &item.UserID, &actorID, &item.OldStatus, &item.NewStatus, &reason, &blockedUntil, &createdAt)
+		if err != nil {
+			return response, err
+		}
+
+		item.ActorID = actorID.String
+		item.Reason = reason.String
+		if blockedUntil.Valid {
+			item.BlockedUntil = blockedUntil.Time.Format(time.RFC3339)
+		}
+		item.CreatedAt = createdAt.Format(time.RFC3339)
+
+		response.Items = append(response.Items, item)
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("user_status_audit").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.Pool.QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,154 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/Akorm0181/yelp/pkg/logger"
+	"github.com/Akorm0181/yelp/pkg/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+type UserChangeRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewUserChangeRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *UserChangeRepo {
+	return &UserChangeRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+const userChangeColumns = `id, user_id, kind, old_value, new_value, reserved_until, created_at`
+
+func scanUserChange(row pgx.Row) (entity.UserChange, error) {
+	var (
+		item          entity.UserChange
+		userID        sql.NullString
+		reservedUntil sql.NullTime
+		createdAt     time.Time
+	)
+
+	err := row.Scan(&item.ID, &userID, &item.Kind, &item.OldValue, &item.NewValue, &reservedUntil, &createdAt)
+	if err != nil {
+		return entity.UserChange{}, err
+	}
+
+	item.UserID = userID.String
+	if reservedUntil.Valid {
+		item.ReservedUntil = reservedUntil.Time.Format(time.RFC3339)
+	}
+	item.CreatedAt = createdAt.Format(time.RFC3339)
+
+	return item, nil
+}
+
+func (r *UserChangeRepo) Create(ctx context.Context, req entity.UserChange) (entity.UserChange, error) {
+	req.ID = uuid.NewString()
+
+	var reservedUntil interface{}
+	if req.ReservedUntil != "" {
+		t, err := time.Parse(time.RFC3339, req.ReservedUntil)
+		if err != nil {
+			return entity.UserChange{}, err
+		}
+		reservedUntil = t
+	}
+
+	qeury, args, err := r.pg.Builder.Insert("user_change_history").
+		Columns(`id, user_id, kind, old_value, new_value, reserved_until`).
+		Values(req.ID, req.UserID, req.Kind, req.OldValue, req.NewValue, reservedUntil).
+		Suffix("RETURNING " + userChangeColumns).ToSql()
+	if err != nil {
+		return entity.UserChange{}, err
+	}
+
+	return scanUserChange(r.pg.Pool.QueryRow(ctx, qeury, args...))
+}
+
+func (r *UserChangeRepo) GetList(ctx context.Context, req entity.GetListFilter) (entity.UserChangeList, error) {
+	var response = entity.UserChangeList{}
+
+	qeuryBuilder := r.pg.Builder.Select(userChangeColumns).From("user_change_history")
+
+	qeuryBuilder, where := PrepareGetListQuery(qeuryBuilder, req)
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	rows, err := r.pg.Pool.Query(ctx, qeury, args...)
+	if err != nil {
+		return response, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanUserChange(rows)
+		if err != nil {
+			return response, err
+		}
+
+		response.Items = append(response.Items, item)
+	}
+
+	countQuery, args, err := r.pg.Builder.Select("COUNT(1)").From("user_change_history").Where(where).ToSql()
+	if err != nil {
+		return response, err
+	}
+
+	err = r.pg.Pool.QueryRow(ctx, countQuery, args...).Scan(&response.Count)
+	if err != nil {
+		return response, err
+	}
+
+	return response, nil
+}
+
+// GetLast returns the latest change of the given kind, pgx.ErrNoRows when there is none.
+func (r *UserChangeRepo) GetLast(ctx context.Context, userID, kind string) (entity.UserChange, error) {
+	qeury, args, err := r.pg.Builder.Select(userChangeColumns).From("user_change_history").
+		Where("user_id = ? AND kind = ?", userID, kind).
+		OrderBy("created_at desc").
+		Limit(1).ToSql()
+	if err != nil {
+		return entity.UserChange{}, err
+	}
+
+	return scanUserChange(r.pg.Pool.QueryRow(ctx, qeury, args...))
+}
+
+// UsernameReserved reports whether username was recently given up by a user other than
+// exceptUserID and is still held for them, also when that user has been purged since. The
+// comparison ignores case.
+func (r *UserChangeRepo) UsernameReserved(ctx context.Context, username, exceptUserID string) (bool, error) {
+	qeuryBuilder := r.pg.Builder.Select("COUNT(1)").From("user_change_history").
+		Where("kind = 'username' AND lower(old_value) = lower(?) AND reserved_until > now()", username)
+
+	if exceptUserID != "" {
+		qeuryBuilder = qeuryBuilder.Where("user_id IS DISTINCT FROM ?", exceptUserID)
+	}
+
+	qeury, args, err := qeuryBuilder.ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	var count int
+	err = r.pg.Pool.QueryRow(ctx, qeury, args...).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
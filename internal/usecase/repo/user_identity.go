@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/Akorm0181/yelp/pkg/logger"
+	"github.com/Akorm0181/yelp/pkg/postgres"
+	"github.com/google/uuid"
+)
+
+type UserIdentityRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewUserIdentityRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *UserIdentityRepo {
+	return &UserIdentityRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+func (r *UserIdentityRepo) Create(ctx context.Context, req entity.UserIdentity) (entity.UserIdentity, error) {
+	req.ID = uuid.NewString()
+
+	qeury, args, err := r.pg.Builder.Insert("user_identity").
+		Columns(`id, user_id, provider, subject, email`).
+		Values(req.ID, req.UserID, req.Provider, req.Subject, req.Email).ToSql()
+	if err != nil {
+		return entity.UserIdentity{}, err
+	}
+
+	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.UserIdentity{}, err
+	}
+
+	return req, nil
+}
+
+func (r *UserIdentityRepo) GetSingle(ctx context.Context, req entity.UserIdentitySingleRequest) (entity.UserIdentity, error) {
+	response := entity.UserIdentity{}
+	var (
+		createdAt, updatedAt time.Time
+		email                sql.NullString
+	)
+
+	qeury, args, err := r.pg.Builder.
+		Select(`id, user_id, provider, subject, email, created_at, updated_at`).
+		From("user_identity").
+		Where("provider = ? AND subject = ?", req.Provider, req.Subject).ToSql()
+	if err != nil {
+		return entity.UserIdentity{}, err
+	}
+
+	err = r.pg.Pool.QueryRow(ctx, qeury, args...).
+		Scan(&response.ID, &response.UserID, &response.Provider, &response.Subject, &email, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.UserIdentity{}, err
+	}
+
+	response.CreatedAt = createdAt.Format(time.RFC3339)
+	response.UpdatedAt = updatedAt.Format(time.RFC3339)
+	if email.Valid {
+		response.Email = email.String
+	}
+
+	return response, nil
+}
@@ -0,0 +1,129 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Akorm0181/yelp/config"
+	"github.com/Akorm0181/yelp/internal/entity"
+	"github.com/Akorm0181/yelp/pkg/logger"
+	"github.com/Akorm0181/yelp/pkg/postgres"
+	"github.com/Masterminds/squirrel"
+)
+
+type UserMfaRepo struct {
+	pg     *postgres.Postgres
+	config *config.Config
+	logger *logger.Logger
+}
+
+// New -.
+func NewUserMfaRepo(pg *postgres.Postgres, config *config.Config, logger *logger.Logger) *UserMfaRepo {
+	return &UserMfaRepo{
+		pg:     pg,
+		config: config,
+		logger: logger,
+	}
+}
+
+// Upsert starts a new (not yet enabled) enrollment, replacing any previous secret of the user.
+func (r *UserMfaRepo) Upsert(ctx context.Context, req entity.UserMfa) (entity.UserMfa, error) {
+	qeury, args, err := r.pg.Builder.Insert("user_mfa").
+		Columns(`user_id, secret, is_enabled, recovery_codes`).
+		Values(req.UserID, req.Secret, false, req.RecoveryCodes).
+		Suffix(`ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, is_enabled = false,
+			recovery_codes = EXCLUDED.recovery_codes, enabled_at = NULL, updated_at = now()`).ToSql()
+	if err != nil {
+		return entity.UserMfa{}, err
+	}
+
+	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return entity.UserMfa{}, err
+	}
+
+	req.IsEnabled = false
+
+	return req, nil
+}
+
+func (r *UserMfaRepo) GetSingle(ctx context.Context, req entity.Id) (entity.UserMfa, error) {
+	response := entity.UserMfa{}
+	var (
+		createdAt, updatedAt time.Time
+		enabledAt            sql.NullTime
+	)
+
+	qeury, args, err := r.pg.Builder.
+		Select(`user_id, secret, is_enabled, recovery_codes, enabled_at, created_at, updated_at`).
+		From("user_mfa").Where("user_id = ?", req.ID).ToSql()
+	if err != nil {
+		return entity.UserMfa{}, err
+	}
+
+	err = r.pg.Pool.QueryRow(ctx, qeury, args...).
+		Scan(&response.UserID, &response.Secret, &response.IsEnabled, &response.RecoveryCodes, &enabledAt, &createdAt, &updatedAt)
+	if err != nil {
+		return entity.UserMfa{}, err
+	}
+
+	response.CreatedAt = createdAt.Format(time.RFC3339)
+	response.UpdatedAt = updatedAt.Format(time.RFC3339)
+	if enabledAt.Valid {
+		response.EnabledAt = enabledAt.Time.Format(time.RFC3339)
+	}
+
+	return response, nil
+}
+
+func (r *UserMfaRepo) Enable(ctx context.Context, req entity.Id) error {
+	qeury, args, err := r.pg.Builder.Update("user_mfa").
+		SetMap(map[string]interface{}{
+			"is_enabled": true,
+			"enabled_at": "now()",
+			"updated_at": "now()",
+		}).Where("user_id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UseRecoveryCode removes the hashed recovery code from the user, reporting whether it was there.
+func (r *UserMfaRepo) UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	qeury, args, err := r.pg.Builder.Update("user_mfa").
+		Set("recovery_codes", squirrel.Expr("array_remove(recovery_codes, ?)", codeHash)).
+		Set("updated_at", "now()").
+		Where("user_id = ? AND ? = ANY(recovery_codes)", userID, codeHash).ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	n, err := r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return false, err
+	}
+
+	return n.RowsAffected() == 1, nil
+}
+
+func (r *UserMfaRepo) Delete(ctx context.Context, req entity.Id) error {
+	qeury, args, err := r.pg.Builder.Delete("user_mfa").Where("user_id = ?", req.ID).ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pg.Pool.Exec(ctx, qeury, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
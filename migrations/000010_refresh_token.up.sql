@@ -0,0 +1,11 @@
+CREATE TABLE refresh_token (
+  id uuid PRIMARY KEY,
+  session_id uuid NOT NULL REFERENCES session(id) ON DELETE CASCADE,
+  token_hash varchar(64) UNIQUE NOT NULL,
+  is_revoked boolean NOT NULL DEFAULT false,
+  used_at timestamp,
+  expires_at timestamp NOT NULL,
+  created_at timestamp NOT NULL DEFAULT 'now()'
+);
+
+CREATE INDEX ON refresh_token(session_id);
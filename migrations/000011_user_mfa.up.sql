@@ -0,0 +1,9 @@
+CREATE TABLE user_mfa (
+  user_id uuid PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  secret varchar(64) NOT NULL,
+  is_enabled boolean NOT NULL DEFAULT false,
+  recovery_codes text[] NOT NULL DEFAULT '{}',
+  enabled_at timestamp,
+  created_at timestamp NOT NULL DEFAULT 'now()',
+  updated_at timestamp NOT NULL DEFAULT 'now()'
+);
@@ -0,0 +1,12 @@
+CREATE TABLE user_identity (
+  id uuid PRIMARY KEY,
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  provider varchar(32) NOT NULL,
+  subject varchar(255) NOT NULL,
+  email varchar(255),
+  created_at timestamp NOT NULL DEFAULT 'now()',
+  updated_at timestamp NOT NULL DEFAULT 'now()'
+);
+
+CREATE UNIQUE INDEX ON user_identity(provider, subject);
+CREATE INDEX ON user_identity(user_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS owner_request;
+DROP TYPE IF EXISTS owner_request_status;
+-- postgres can't drop a value from an enum, 'business_owner' stays in platform
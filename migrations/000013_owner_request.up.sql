@@ -0,0 +1,24 @@
+ALTER TYPE platform ADD VALUE IF NOT EXISTS 'business_owner';
+
+CREATE TYPE owner_request_status AS ENUM (
+  'pending',
+  'approved',
+  'rejected'
+);
+
+CREATE TABLE owner_request (
+  id uuid PRIMARY KEY,
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  business_name varchar(255) NOT NULL,
+  phone varchar(32),
+  note text,
+  status owner_request_status NOT NULL DEFAULT 'pending',
+  reviewed_by uuid REFERENCES users(id) ON DELETE SET NULL,
+  review_note text,
+  reviewed_at timestamp,
+  created_at timestamp NOT NULL DEFAULT 'now()',
+  updated_at timestamp NOT NULL DEFAULT 'now()'
+);
+
+-- a user can only have one request waiting for review
+CREATE UNIQUE INDEX ON owner_request(user_id) WHERE status = 'pending';
@@ -0,0 +1,15 @@
+CREATE TABLE api_key (
+  id uuid PRIMARY KEY,
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name varchar(100) NOT NULL,
+  prefix varchar(16) NOT NULL,
+  key_hash varchar(64) UNIQUE NOT NULL,
+  scopes text[] NOT NULL DEFAULT '{}',
+  last_used_at timestamp,
+  expires_at timestamp,
+  revoked_at timestamp,
+  created_at timestamp NOT NULL DEFAULT 'now()',
+  updated_at timestamp NOT NULL DEFAULT 'now()'
+);
+
+CREATE INDEX ON api_key(user_id);
@@ -0,0 +1,7 @@
+ALTER TABLE notifications
+  DROP CONSTRAINT IF EXISTS notifications_user_id_fkey,
+  ADD CONSTRAINT notifications_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
+
+ALTER TABLE users
+  DROP COLUMN IF EXISTS deletion_requested_at,
+  DROP COLUMN IF EXISTS delete_after;
@@ -0,0 +1,10 @@
+ALTER TABLE users
+  ADD COLUMN deletion_requested_at timestamp,
+  ADD COLUMN delete_after timestamp;
+
+CREATE INDEX ON users(delete_after) WHERE delete_after IS NOT NULL;
+
+-- purged accounts take their notifications with them like every other user owned row
+ALTER TABLE notifications
+  DROP CONSTRAINT IF EXISTS notifications_user_id_fkey,
+  ADD CONSTRAINT notifications_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS user_status_audit;
+
+ALTER TABLE users
+  DROP COLUMN IF EXISTS blocked_until,
+  DROP COLUMN IF EXISTS block_reason;
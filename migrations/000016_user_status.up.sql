@@ -0,0 +1,16 @@
+ALTER TABLE users
+  ADD COLUMN blocked_until timestamp,
+  ADD COLUMN block_reason text;
+
+CREATE TABLE user_status_audit (
+  id uuid PRIMARY KEY,
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  actor_id uuid REFERENCES users(id) ON DELETE SET NULL,
+  old_status user_status NOT NULL,
+  new_status user_status NOT NULL,
+  reason text,
+  blocked_until timestamp,
+  created_at timestamp NOT NULL DEFAULT 'now()'
+);
+
+CREATE INDEX ON user_status_audit(user_id, created_at);
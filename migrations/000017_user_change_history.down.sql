@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS user_change_history;
+DROP TYPE IF EXISTS user_change_kind;
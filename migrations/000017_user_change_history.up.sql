@@ -0,0 +1,17 @@
+CREATE TYPE user_change_kind AS ENUM (
+  'email',
+  'username'
+);
+
+CREATE TABLE user_change_history (
+  id uuid PRIMARY KEY,
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  kind user_change_kind NOT NULL,
+  old_value varchar(255) NOT NULL,
+  new_value varchar(255) NOT NULL,
+  reserved_until timestamp,
+  created_at timestamp NOT NULL DEFAULT 'now()'
+);
+
+CREATE INDEX ON user_change_history(user_id, kind, created_at);
+CREATE INDEX ON user_change_history(kind, lower(old_value)) WHERE reserved_until IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS businesses_lat_lng_idx;
+DROP INDEX IF EXISTS businesses_location_idx;
+DROP EXTENSION IF EXISTS earthdistance;
+DROP EXTENSION IF EXISTS cube;
@@ -0,0 +1,9 @@
+CREATE EXTENSION IF NOT EXISTS cube;
+CREATE EXTENSION IF NOT EXISTS earthdistance;
+
+-- radius search and distance sort
+CREATE INDEX businesses_location_idx ON businesses USING gist (ll_to_earth(latitude, longitude))
+  WHERE latitude IS NOT NULL AND longitude IS NOT NULL;
+
+-- bounding box search
+CREATE INDEX businesses_lat_lng_idx ON businesses(latitude, longitude);
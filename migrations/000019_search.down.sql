@@ -0,0 +1,8 @@
+ALTER TABLE events DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE reviews DROP COLUMN IF EXISTS search_vector;
+
+DROP TRIGGER IF EXISTS business_categories_search_vector_trigger ON business_categories;
+DROP FUNCTION IF EXISTS business_categories_search_vector_update();
+DROP TRIGGER IF EXISTS businesses_search_vector_trigger ON businesses;
+DROP FUNCTION IF EXISTS businesses_search_vector_update();
+ALTER TABLE businesses DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,48 @@
+-- 'simple' keeps words as they are, names and reviews are written in several languages
+ALTER TABLE businesses ADD COLUMN search_vector tsvector;
+
+-- the category name lives in another table, so the vector is kept up to date by triggers
+-- instead of being a generated column
+CREATE OR REPLACE FUNCTION businesses_search_vector_update() RETURNS trigger AS $$
+BEGIN
+  NEW.search_vector :=
+    setweight(to_tsvector('simple', coalesce(NEW.name, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce((SELECT name FROM business_categories WHERE id = NEW.category_id), '')), 'B') ||
+    setweight(to_tsvector('simple', coalesce(NEW.description, '')), 'C') ||
+    setweight(to_tsvector('simple', coalesce(NEW.address, '')), 'D');
+  RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER businesses_search_vector_trigger
+  BEFORE INSERT OR UPDATE OF name, description, address, category_id ON businesses
+  FOR EACH ROW EXECUTE FUNCTION businesses_search_vector_update();
+
+CREATE OR REPLACE FUNCTION business_categories_search_vector_update() RETURNS trigger AS $$
+BEGIN
+  UPDATE businesses SET name = name WHERE category_id = NEW.id;
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER business_categories_search_vector_trigger
+  AFTER UPDATE OF name ON business_categories
+  FOR EACH ROW EXECUTE FUNCTION business_categories_search_vector_update();
+
+UPDATE businesses SET name = name;
+
+CREATE INDEX businesses_search_idx ON businesses USING gin (search_vector);
+
+ALTER TABLE reviews ADD COLUMN search_vector tsvector
+  GENERATED ALWAYS AS (to_tsvector('simple', coalesce(comment, ''))) STORED;
+
+CREATE INDEX reviews_search_idx ON reviews USING gin (search_vector);
+
+ALTER TABLE events ADD COLUMN search_vector tsvector
+  GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(description, '')), 'B') ||
+    setweight(to_tsvector('simple', coalesce(location, '')), 'C')
+  ) STORED;
+
+CREATE INDEX events_search_idx ON events USING gin (search_vector);
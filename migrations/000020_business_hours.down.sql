@@ -0,0 +1,25 @@
+DROP FUNCTION IF EXISTS business_open_at(uuid, text, timestamptz);
+DROP FUNCTION IF EXISTS business_day_hours(uuid, date);
+
+ALTER TABLE businesses ADD COLUMN hours_of_operation JSON;
+
+UPDATE businesses b SET hours_of_operation = (
+  SELECT json_object_agg(d.name, h.hours)
+  FROM (VALUES ('sunday', 0), ('monday', 1), ('tuesday', 2), ('wednesday', 3),
+               ('thursday', 4), ('friday', 5), ('saturday', 6)) AS d(name, weekday)
+  LEFT JOIN LATERAL (
+    SELECT string_agg(to_char(opens_at, 'HH24:MI') || '-' || to_char(closes_at, 'HH24:MI'), ', ' ORDER BY opens_at) AS hours
+    FROM business_hours WHERE business_id = b.id AND weekday = d.weekday
+  ) h ON true
+);
+
+-- the hours that were never entered again come back as they were
+UPDATE businesses b SET hours_of_operation = l.hours_of_operation
+FROM business_hours_legacy l
+WHERE l.business_id = b.id;
+
+DROP TABLE IF EXISTS business_hours_legacy;
+DROP TABLE IF EXISTS business_special_hours;
+DROP TABLE IF EXISTS business_hours;
+
+ALTER TABLE businesses DROP COLUMN IF EXISTS time_zone;
@@ -0,0 +1,89 @@
+ALTER TABLE businesses ADD COLUMN time_zone varchar(64) NOT NULL DEFAULT 'UTC';
+
+-- weekly hours, a range whose closes_at is at or before opens_at runs past midnight
+CREATE TABLE business_hours (
+  id uuid PRIMARY KEY,
+  business_id uuid NOT NULL REFERENCES businesses(id) ON DELETE CASCADE,
+  weekday smallint NOT NULL CHECK (weekday BETWEEN 0 AND 6), -- 0 is Sunday
+  opens_at time NOT NULL,
+  closes_at time NOT NULL
+);
+
+CREATE INDEX ON business_hours(business_id, weekday);
+
+-- holidays and other dates that replace the weekly hours of that day
+CREATE TABLE business_special_hours (
+  id uuid PRIMARY KEY,
+  business_id uuid NOT NULL REFERENCES businesses(id) ON DELETE CASCADE,
+  date date NOT NULL,
+  closed boolean NOT NULL DEFAULT false,
+  opens_at time,
+  closes_at time,
+  note varchar(255),
+  CHECK (closed OR (opens_at IS NOT NULL AND closes_at IS NOT NULL))
+);
+
+CREATE INDEX ON business_special_hours(business_id, date);
+
+-- keep whatever of the free-form hours reads as "HH:MM - HH:MM"
+INSERT INTO business_hours (id, business_id, weekday, opens_at, closes_at)
+SELECT gen_random_uuid(), b.id, d.weekday, m[1]::time, m[2]::time
+FROM businesses b
+CROSS JOIN (VALUES ('sunday', 0), ('monday', 1), ('tuesday', 2), ('wednesday', 3),
+                   ('thursday', 4), ('friday', 5), ('saturday', 6)) AS d(name, weekday)
+CROSS JOIN LATERAL regexp_match(b.hours_of_operation ->> d.name,
+  '^\s*((?:[01]?\d|2[0-3]):[0-5]\d)\s*-\s*((?:[01]?\d|2[0-3]):[0-5]\d)\s*$') AS m
+WHERE m IS NOT NULL;
+
+-- the free-form hours that didn't read that way are kept here until they are entered again by
+-- hand, the row of a business is deleted once that is done
+CREATE TABLE business_hours_legacy (
+  business_id uuid PRIMARY KEY REFERENCES businesses(id) ON DELETE CASCADE,
+  hours_of_operation JSON NOT NULL,
+  archived_at timestamp NOT NULL DEFAULT now()
+);
+
+INSERT INTO business_hours_legacy (business_id, hours_of_operation)
+SELECT b.id, b.hours_of_operation
+FROM businesses b
+WHERE b.hours_of_operation IS NOT NULL AND CASE json_typeof(b.hours_of_operation)
+  WHEN 'object' THEN EXISTS (
+    SELECT 1 FROM json_each_text(b.hours_of_operation) e
+    WHERE btrim(coalesce(e.value, '')) <> ''
+      AND (e.key NOT IN ('sunday', 'monday', 'tuesday', 'wednesday', 'thursday', 'friday', 'saturday')
+        OR e.value !~ '^\s*((?:[01]?\d|2[0-3]):[0-5]\d)\s*-\s*((?:[01]?\d|2[0-3]):[0-5]\d)\s*$')
+  )
+  WHEN 'null' THEN false
+  ELSE true
+END;
+
+ALTER TABLE businesses DROP COLUMN hours_of_operation;
+
+-- ranges that apply on a day: its special hours if there are any, the weekly ones otherwise
+CREATE OR REPLACE FUNCTION business_day_hours(p_business_id uuid, p_day date)
+RETURNS TABLE (opens_at time, closes_at time) AS $$
+  SELECT s.opens_at, s.closes_at
+  FROM business_special_hours s
+  WHERE s.business_id = p_business_id AND s.date = p_day AND NOT s.closed
+  UNION ALL
+  SELECT h.opens_at, h.closes_at
+  FROM business_hours h
+  WHERE h.business_id = p_business_id AND h.weekday = extract(dow FROM p_day)
+    AND NOT EXISTS (
+      SELECT 1 FROM business_special_hours s WHERE s.business_id = p_business_id AND s.date = p_day
+    )
+$$ LANGUAGE sql STABLE;
+
+-- whether the business is open at p_at, checking the local day and the overnight ranges of
+-- the day before
+CREATE OR REPLACE FUNCTION business_open_at(p_business_id uuid, p_time_zone text, p_at timestamptz)
+RETURNS boolean AS $$
+  WITH l AS (SELECT (p_at AT TIME ZONE p_time_zone) AS ts)
+  SELECT EXISTS (
+    SELECT 1 FROM l, business_day_hours(p_business_id, l.ts::date) d
+    WHERE l.ts::time >= d.opens_at AND (d.closes_at <= d.opens_at OR l.ts::time < d.closes_at)
+  ) OR EXISTS (
+    SELECT 1 FROM l, business_day_hours(p_business_id, l.ts::date - 1) d
+    WHERE d.closes_at <= d.opens_at AND l.ts::time < d.closes_at
+  )
+$$ LANGUAGE sql STABLE;
@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS reviews_rating_aggregate_trigger ON reviews;
+DROP FUNCTION IF EXISTS reviews_rating_aggregate();
+
+ALTER TABLE businesses
+  DROP COLUMN IF EXISTS rating_avg,
+  DROP COLUMN IF EXISTS rating_histogram,
+  DROP COLUMN IF EXISTS rating_sum,
+  DROP COLUMN IF EXISTS review_count;
@@ -0,0 +1,57 @@
+ALTER TABLE businesses
+  ADD COLUMN review_count integer NOT NULL DEFAULT 0,
+  ADD COLUMN rating_sum bigint NOT NULL DEFAULT 0,
+  ADD COLUMN rating_histogram integer[] NOT NULL DEFAULT '{0,0,0,0,0}'; -- reviews per star, 1 to 5
+
+ALTER TABLE businesses
+  ADD COLUMN rating_avg numeric(3, 2) GENERATED ALWAYS AS (
+    CASE WHEN review_count = 0 THEN 0 ELSE round(rating_sum::numeric / review_count, 2) END
+  ) STORED;
+
+-- adjusts the aggregates by the difference a review makes, so concurrent writes add up
+-- instead of overwriting each other
+CREATE OR REPLACE FUNCTION reviews_rating_aggregate() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP IN ('UPDATE', 'DELETE') AND OLD.business_id IS NOT NULL THEN
+    UPDATE businesses SET
+      review_count = review_count - 1,
+      rating_sum = rating_sum - OLD.rating,
+      rating_histogram[OLD.rating] = rating_histogram[OLD.rating] - 1
+    WHERE id = OLD.business_id;
+  END IF;
+
+  IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.business_id IS NOT NULL THEN
+    UPDATE businesses SET
+      review_count = review_count + 1,
+      rating_sum = rating_sum + NEW.rating,
+      rating_histogram[NEW.rating] = rating_histogram[NEW.rating] + 1
+    WHERE id = NEW.business_id;
+  END IF;
+
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER reviews_rating_aggregate_trigger
+  AFTER INSERT OR DELETE OR UPDATE OF rating, business_id ON reviews
+  FOR EACH ROW EXECUTE FUNCTION reviews_rating_aggregate();
+
+UPDATE businesses b SET
+  review_count = s.review_count,
+  rating_sum = s.rating_sum,
+  rating_histogram = s.rating_histogram
+FROM (
+  SELECT business_id,
+    COUNT(1) AS review_count,
+    SUM(rating) AS rating_sum,
+    ARRAY[
+      COUNT(1) FILTER (WHERE rating = 1), COUNT(1) FILTER (WHERE rating = 2), COUNT(1) FILTER (WHERE rating = 3),
+      COUNT(1) FILTER (WHERE rating = 4), COUNT(1) FILTER (WHERE rating = 5)
+    ]::integer[] AS rating_histogram
+  FROM reviews
+  WHERE business_id IS NOT NULL
+  GROUP BY business_id
+) s
+WHERE b.id = s.business_id;
+
+CREATE INDEX businesses_rating_idx ON businesses(rating_avg DESC, review_count DESC);
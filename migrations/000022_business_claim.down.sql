@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS business_claim_evidence;
+DROP TABLE IF EXISTS business_claim;
+DROP TYPE IF EXISTS business_claim_status;
+
+ALTER TABLE businesses
+  DROP COLUMN IF EXISTS created_by,
+  DROP COLUMN IF EXISTS verified_at,
+  DROP COLUMN IF EXISTS claim_state;
+
+DROP TYPE IF EXISTS business_claim_state;
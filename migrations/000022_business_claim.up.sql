@@ -0,0 +1,48 @@
+CREATE TYPE business_claim_state AS ENUM (
+  'unclaimed',
+  'claimed',
+  'verified'
+);
+
+-- unclaimed: listed by a user, nobody owns it yet
+-- claimed: created by a business owner who is not verified
+-- verified: ownership was confirmed by an admin through a claim
+ALTER TABLE businesses
+  ADD COLUMN claim_state business_claim_state NOT NULL DEFAULT 'unclaimed',
+  ADD COLUMN verified_at timestamp,
+  ADD COLUMN created_by uuid REFERENCES users(id) ON DELETE SET NULL;
+
+UPDATE businesses SET claim_state = 'claimed', created_by = owner_id WHERE owner_id IS NOT NULL;
+
+CREATE TYPE business_claim_status AS ENUM (
+  'pending',
+  'approved',
+  'rejected'
+);
+
+CREATE TABLE business_claim (
+  id uuid PRIMARY KEY,
+  business_id uuid NOT NULL REFERENCES businesses(id) ON DELETE CASCADE,
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  phone varchar(32),
+  note text,
+  status business_claim_status NOT NULL DEFAULT 'pending',
+  reviewed_by uuid REFERENCES users(id) ON DELETE SET NULL,
+  review_note text,
+  reviewed_at timestamp,
+  created_at timestamp NOT NULL DEFAULT 'now()',
+  updated_at timestamp NOT NULL DEFAULT 'now()'
+);
+
+-- one open claim per user and business
+CREATE UNIQUE INDEX ON business_claim(business_id, user_id) WHERE status = 'pending';
+CREATE INDEX ON business_claim(user_id);
+
+CREATE TABLE business_claim_evidence (
+  id uuid PRIMARY KEY,
+  claim_id uuid NOT NULL REFERENCES business_claim(id) ON DELETE CASCADE,
+  filepath varchar(255) NOT NULL,
+  created_at timestamp NOT NULL DEFAULT 'now()'
+);
+
+CREATE INDEX ON business_claim_evidence(claim_id);
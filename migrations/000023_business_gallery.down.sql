@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS business_attachment_cover_idx;
+DROP INDEX IF EXISTS business_attachment_position_idx;
+
+ALTER TABLE business_attachment
+  DROP COLUMN IF EXISTS uploaded_by,
+  DROP COLUMN IF EXISTS caption,
+  DROP COLUMN IF EXISTS is_cover,
+  DROP COLUMN IF EXISTS position;
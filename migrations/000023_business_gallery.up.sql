@@ -0,0 +1,28 @@
+ALTER TABLE business_attachment
+  ADD COLUMN position INT NOT NULL DEFAULT 0,
+  ADD COLUMN is_cover BOOLEAN NOT NULL DEFAULT FALSE,
+  ADD COLUMN caption VARCHAR(500),
+  ADD COLUMN uploaded_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+-- keep the current order, which so far has been upload order
+UPDATE business_attachment ba
+SET position = o.position
+FROM (
+  SELECT id, ROW_NUMBER() OVER (PARTITION BY business_id ORDER BY created_at, id) - 1 AS position
+  FROM business_attachment
+) o
+WHERE ba.id = o.id;
+
+-- the first image of every business becomes its cover
+UPDATE business_attachment ba
+SET is_cover = TRUE
+FROM (
+  SELECT DISTINCT ON (business_id) id
+  FROM business_attachment
+  WHERE content_type = 'image'
+  ORDER BY business_id, position
+) c
+WHERE ba.id = c.id;
+
+CREATE INDEX business_attachment_position_idx ON business_attachment (business_id, position);
+CREATE UNIQUE INDEX business_attachment_cover_idx ON business_attachment (business_id) WHERE is_cover;
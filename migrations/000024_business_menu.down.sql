@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS menu_item;
+DROP TABLE IF EXISTS menu_section;
+DROP TABLE IF EXISTS business_menu;
+DROP TYPE IF EXISTS menu_status;
+
+ALTER TABLE businesses DROP COLUMN IF EXISTS price_range;
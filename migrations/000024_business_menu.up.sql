@@ -0,0 +1,52 @@
+-- 1 to 4, shown as $ to $$$$
+ALTER TABLE businesses ADD COLUMN price_range smallint CHECK (price_range BETWEEN 1 AND 4);
+
+CREATE INDEX ON businesses(price_range);
+
+CREATE TYPE menu_status AS ENUM (
+  'draft',
+  'published',
+  'archived'
+);
+
+-- every edit goes to the draft, publishing it archives the menu that was live before
+CREATE TABLE business_menu (
+  id uuid PRIMARY KEY,
+  business_id uuid NOT NULL REFERENCES businesses(id) ON DELETE CASCADE,
+  version int NOT NULL,
+  status menu_status NOT NULL DEFAULT 'draft',
+  created_by uuid REFERENCES users(id) ON DELETE SET NULL,
+  published_by uuid REFERENCES users(id) ON DELETE SET NULL,
+  published_at timestamp,
+  created_at timestamp NOT NULL DEFAULT 'now()',
+  updated_at timestamp NOT NULL DEFAULT 'now()',
+  UNIQUE (business_id, version)
+);
+
+CREATE UNIQUE INDEX ON business_menu(business_id) WHERE status = 'draft';
+CREATE UNIQUE INDEX ON business_menu(business_id) WHERE status = 'published';
+
+CREATE TABLE menu_section (
+  id uuid PRIMARY KEY,
+  menu_id uuid NOT NULL REFERENCES business_menu(id) ON DELETE CASCADE,
+  name varchar(255) NOT NULL,
+  description text,
+  position int NOT NULL DEFAULT 0
+);
+
+CREATE INDEX ON menu_section(menu_id, position);
+
+CREATE TABLE menu_item (
+  id uuid PRIMARY KEY,
+  section_id uuid NOT NULL REFERENCES menu_section(id) ON DELETE CASCADE,
+  name varchar(255) NOT NULL,
+  description text,
+  price numeric(12, 2) NOT NULL CHECK (price >= 0),
+  currency char(3) NOT NULL,
+  dietary_tags text[] NOT NULL DEFAULT '{}',
+  photo_url varchar(500),
+  sold_out boolean NOT NULL DEFAULT false,
+  position int NOT NULL DEFAULT 0
+);
+
+CREATE INDEX ON menu_item(section_id, position);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS business_attribute_value;
+DROP TABLE IF EXISTS business_category_attribute;
+DROP TABLE IF EXISTS business_attribute;
+DROP TYPE IF EXISTS attribute_value_type;
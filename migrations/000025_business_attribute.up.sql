@@ -0,0 +1,44 @@
+CREATE TYPE attribute_value_type AS ENUM (
+  'bool',
+  'enum'
+);
+
+-- amenities like wifi or parking, a business only gets the attributes of its category
+CREATE TABLE business_attribute (
+  id uuid PRIMARY KEY,
+  key varchar(64) NOT NULL UNIQUE,
+  name varchar(255) NOT NULL,
+  value_type attribute_value_type NOT NULL DEFAULT 'bool',
+  options text[] NOT NULL DEFAULT '{}', -- allowed values of an enum attribute
+  created_at timestamp NOT NULL DEFAULT 'now()',
+  updated_at timestamp NOT NULL DEFAULT 'now()'
+);
+
+CREATE TABLE business_category_attribute (
+  category_id uuid NOT NULL REFERENCES business_categories(id) ON DELETE CASCADE,
+  attribute_id uuid NOT NULL REFERENCES business_attribute(id) ON DELETE CASCADE,
+  PRIMARY KEY (category_id, attribute_id)
+);
+
+CREATE INDEX ON business_category_attribute(attribute_id);
+
+-- bool attributes store 'true' or 'false'
+CREATE TABLE business_attribute_value (
+  business_id uuid NOT NULL REFERENCES businesses(id) ON DELETE CASCADE,
+  attribute_id uuid NOT NULL REFERENCES business_attribute(id) ON DELETE CASCADE,
+  value varchar(64) NOT NULL,
+  PRIMARY KEY (business_id, attribute_id)
+);
+
+CREATE INDEX ON business_attribute_value(attribute_id, value);
+
+INSERT INTO business_attribute (id, key, name, value_type, options) VALUES
+  (gen_random_uuid(), 'wifi', 'Wi-Fi', 'enum', '{free,paid,no}'),
+  (gen_random_uuid(), 'parking', 'Parking', 'enum', '{street,lot,garage,valet,none}'),
+  (gen_random_uuid(), 'wheelchair_accessible', 'Wheelchair accessible', 'bool', '{}'),
+  (gen_random_uuid(), 'outdoor_seating', 'Outdoor seating', 'bool', '{}'),
+  (gen_random_uuid(), 'accepts_cards', 'Accepts credit cards', 'bool', '{}');
+
+-- existing categories start with every attribute, admins narrow them down
+INSERT INTO business_category_attribute (category_id, attribute_id)
+SELECT c.id, a.id FROM business_categories c CROSS JOIN business_attribute a;
@@ -0,0 +1,50 @@
+DROP TRIGGER IF EXISTS business_categories_search_vector_trigger ON business_categories;
+DROP TRIGGER IF EXISTS business_category_link_search_vector_trigger ON business_category_link;
+DROP FUNCTION IF EXISTS business_category_link_search_vector_update();
+DROP TRIGGER IF EXISTS businesses_search_vector_trigger ON businesses;
+
+ALTER TABLE businesses ADD COLUMN category_id uuid REFERENCES business_categories(id) ON DELETE CASCADE;
+
+UPDATE businesses b SET category_id = l.category_id
+FROM business_category_link l
+WHERE l.business_id = b.id AND l.is_primary;
+
+DROP TABLE IF EXISTS business_category_link;
+DROP FUNCTION IF EXISTS business_category_names(uuid);
+
+CREATE OR REPLACE FUNCTION businesses_search_vector_update() RETURNS trigger AS $$
+BEGIN
+  NEW.search_vector :=
+    setweight(to_tsvector('simple', coalesce(NEW.name, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce((SELECT name FROM business_categories WHERE id = NEW.category_id), '')), 'B') ||
+    setweight(to_tsvector('simple', coalesce(NEW.description, '')), 'C') ||
+    setweight(to_tsvector('simple', coalesce(NEW.address, '')), 'D');
+  RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER businesses_search_vector_trigger
+  BEFORE INSERT OR UPDATE OF name, description, address, category_id ON businesses
+  FOR EACH ROW EXECUTE FUNCTION businesses_search_vector_update();
+
+CREATE OR REPLACE FUNCTION business_categories_search_vector_update() RETURNS trigger AS $$
+BEGIN
+  UPDATE businesses SET name = name WHERE category_id = NEW.id;
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER business_categories_search_vector_trigger
+  AFTER UPDATE OF name ON business_categories
+  FOR EACH ROW EXECUTE FUNCTION business_categories_search_vector_update();
+
+UPDATE businesses SET name = name;
+
+DROP INDEX IF EXISTS business_categories_sibling_name_idx;
+
+ALTER TABLE business_categories
+  DROP CONSTRAINT IF EXISTS business_categories_slug_key,
+  DROP COLUMN IF EXISTS icon,
+  DROP COLUMN IF EXISTS slug,
+  DROP COLUMN IF EXISTS parent_id,
+  ADD CONSTRAINT business_categories_name_key UNIQUE (name);
@@ -0,0 +1,112 @@
+-- categories form a tree like Restaurants > Italian > Pizza, names only have to be unique
+-- among siblings and the slug identifies a category in urls
+ALTER TABLE business_categories
+  DROP CONSTRAINT IF EXISTS business_categories_name_key,
+  ADD COLUMN parent_id uuid REFERENCES business_categories(id) ON DELETE RESTRICT,
+  ADD COLUMN slug varchar(120),
+  ADD COLUMN icon varchar(255);
+
+UPDATE business_categories
+SET slug = trim(both '-' from regexp_replace(lower(name), '[^a-z0-9]+', '-', 'g'));
+
+UPDATE business_categories SET slug = left(id::text, 8) WHERE slug = '';
+
+UPDATE business_categories c
+SET slug = c.slug || '-' || left(c.id::text, 8)
+FROM (
+  SELECT id, ROW_NUMBER() OVER (PARTITION BY slug ORDER BY created_at, id) AS n
+  FROM business_categories
+) d
+WHERE c.id = d.id AND d.n > 1;
+
+ALTER TABLE business_categories
+  ALTER COLUMN slug SET NOT NULL,
+  ADD CONSTRAINT business_categories_slug_key UNIQUE (slug);
+
+CREATE UNIQUE INDEX business_categories_sibling_name_idx
+  ON business_categories (COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'), lower(name));
+CREATE INDEX ON business_categories(parent_id);
+
+-- a business has one or more categories, the primary one is shown first
+CREATE TABLE business_category_link (
+  business_id uuid NOT NULL REFERENCES businesses(id) ON DELETE CASCADE,
+  category_id uuid NOT NULL REFERENCES business_categories(id) ON DELETE CASCADE,
+  is_primary boolean NOT NULL DEFAULT false,
+  PRIMARY KEY (business_id, category_id)
+);
+
+CREATE INDEX ON business_category_link(category_id);
+CREATE UNIQUE INDEX ON business_category_link(business_id) WHERE is_primary;
+
+INSERT INTO business_category_link (business_id, category_id, is_primary)
+SELECT id, category_id, true FROM businesses WHERE category_id IS NOT NULL;
+
+-- the search vector now takes the names of every category of the business and their parents
+CREATE OR REPLACE FUNCTION business_category_names(business uuid) RETURNS text AS $$
+  WITH RECURSIVE c AS (
+    SELECT bc.id, bc.name, bc.parent_id
+    FROM business_categories bc
+    JOIN business_category_link l ON l.category_id = bc.id
+    WHERE l.business_id = business
+    UNION
+    SELECT p.id, p.name, p.parent_id
+    FROM business_categories p
+    JOIN c ON p.id = c.parent_id
+  )
+  SELECT coalesce(string_agg(name, ' '), '') FROM c
+$$ LANGUAGE sql STABLE;
+
+DROP TRIGGER businesses_search_vector_trigger ON businesses;
+
+ALTER TABLE businesses DROP COLUMN category_id;
+
+CREATE OR REPLACE FUNCTION businesses_search_vector_update() RETURNS trigger AS $$
+BEGIN
+  NEW.search_vector :=
+    setweight(to_tsvector('simple', coalesce(NEW.name, '')), 'A') ||
+    setweight(to_tsvector('simple', business_category_names(NEW.id)), 'B') ||
+    setweight(to_tsvector('simple', coalesce(NEW.description, '')), 'C') ||
+    setweight(to_tsvector('simple', coalesce(NEW.address, '')), 'D');
+  RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER businesses_search_vector_trigger
+  BEFORE INSERT OR UPDATE OF name, description, address ON businesses
+  FOR EACH ROW EXECUTE FUNCTION businesses_search_vector_update();
+
+CREATE OR REPLACE FUNCTION business_category_link_search_vector_update() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    UPDATE businesses SET name = name WHERE id = OLD.business_id;
+  ELSE
+    UPDATE businesses SET name = name WHERE id = NEW.business_id;
+  END IF;
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER business_category_link_search_vector_trigger
+  AFTER INSERT OR DELETE ON business_category_link
+  FOR EACH ROW EXECUTE FUNCTION business_category_link_search_vector_update();
+
+-- renaming or moving a category changes the vector of every business below it
+CREATE OR REPLACE FUNCTION business_categories_search_vector_update() RETURNS trigger AS $$
+BEGIN
+  UPDATE businesses SET name = name WHERE id IN (
+    WITH RECURSIVE d AS (
+      SELECT NEW.id AS id
+      UNION ALL
+      SELECT c.id FROM business_categories c JOIN d ON c.parent_id = d.id
+    )
+    SELECT l.business_id FROM business_category_link l JOIN d ON d.id = l.category_id
+  );
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER business_categories_search_vector_trigger ON business_categories;
+
+CREATE TRIGGER business_categories_search_vector_trigger
+  AFTER UPDATE OF name, parent_id ON business_categories
+  FOR EACH ROW EXECUTE FUNCTION business_categories_search_vector_update();
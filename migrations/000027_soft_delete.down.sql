@@ -0,0 +1,40 @@
+-- rows that are still deleted go for good, they don't count in the aggregates already
+DELETE FROM reviews WHERE deleted_at IS NOT NULL;
+DELETE FROM events WHERE deleted_at IS NOT NULL;
+DELETE FROM businesses WHERE deleted_at IS NOT NULL;
+
+DROP TRIGGER reviews_rating_aggregate_trigger ON reviews;
+
+CREATE OR REPLACE FUNCTION reviews_rating_aggregate() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP IN ('UPDATE', 'DELETE') AND OLD.business_id IS NOT NULL THEN
+    UPDATE businesses SET
+      review_count = review_count - 1,
+      rating_sum = rating_sum - OLD.rating,
+      rating_histogram[OLD.rating] = rating_histogram[OLD.rating] - 1
+    WHERE id = OLD.business_id;
+  END IF;
+
+  IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.business_id IS NOT NULL THEN
+    UPDATE businesses SET
+      review_count = review_count + 1,
+      rating_sum = rating_sum + NEW.rating,
+      rating_histogram[NEW.rating] = rating_histogram[NEW.rating] + 1
+    WHERE id = NEW.business_id;
+  END IF;
+
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER reviews_rating_aggregate_trigger
+  AFTER INSERT OR DELETE OR UPDATE OF rating, business_id ON reviews
+  FOR EACH ROW EXECUTE FUNCTION reviews_rating_aggregate();
+
+DROP INDEX events_deleted_at_idx;
+DROP INDEX reviews_deleted_at_idx;
+DROP INDEX businesses_deleted_at_idx;
+
+ALTER TABLE events DROP COLUMN deleted_at;
+ALTER TABLE reviews DROP COLUMN deleted_at;
+ALTER TABLE businesses DROP COLUMN deleted_at;
@@ -0,0 +1,38 @@
+ALTER TABLE businesses ADD COLUMN deleted_at timestamp;
+ALTER TABLE reviews ADD COLUMN deleted_at timestamp;
+ALTER TABLE events ADD COLUMN deleted_at timestamp;
+
+-- the purge job only looks at deleted rows
+CREATE INDEX businesses_deleted_at_idx ON businesses(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX reviews_deleted_at_idx ON reviews(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX events_deleted_at_idx ON events(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- deleted reviews don't count, deleting one takes it out of the aggregates and restoring it
+-- puts it back. Purging a deleted review changes nothing.
+CREATE OR REPLACE FUNCTION reviews_rating_aggregate() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP IN ('UPDATE', 'DELETE') AND OLD.business_id IS NOT NULL AND OLD.deleted_at IS NULL THEN
+    UPDATE businesses SET
+      review_count = review_count - 1,
+      rating_sum = rating_sum - OLD.rating,
+      rating_histogram[OLD.rating] = rating_histogram[OLD.rating] - 1
+    WHERE id = OLD.business_id;
+  END IF;
+
+  IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.business_id IS NOT NULL AND NEW.deleted_at IS NULL THEN
+    UPDATE businesses SET
+      review_count = review_count + 1,
+      rating_sum = rating_sum + NEW.rating,
+      rating_histogram[NEW.rating] = rating_histogram[NEW.rating] + 1
+    WHERE id = NEW.business_id;
+  END IF;
+
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER reviews_rating_aggregate_trigger ON reviews;
+
+CREATE TRIGGER reviews_rating_aggregate_trigger
+  AFTER INSERT OR DELETE OR UPDATE OF rating, business_id, deleted_at ON reviews
+  FOR EACH ROW EXECUTE FUNCTION reviews_rating_aggregate();
@@ -0,0 +1,6 @@
+DELETE FROM user_change_history WHERE user_id IS NULL;
+
+ALTER TABLE user_change_history
+  DROP CONSTRAINT user_change_history_user_id_fkey,
+  ADD CONSTRAINT user_change_history_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  ALTER COLUMN user_id SET NOT NULL;
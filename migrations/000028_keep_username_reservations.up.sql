@@ -0,0 +1,6 @@
+-- username reservations outlive a purged account, so an old username can't be taken over right
+-- away. AccountRepo.Purge deletes the rest of the history before the user.
+ALTER TABLE user_change_history
+  ALTER COLUMN user_id DROP NOT NULL,
+  DROP CONSTRAINT user_change_history_user_id_fkey,
+  ADD CONSTRAINT user_change_history_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
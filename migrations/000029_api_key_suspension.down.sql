@@ -0,0 +1 @@
+ALTER TABLE api_key DROP COLUMN IF EXISTS suspended_at;
@@ -0,0 +1,3 @@
+-- keys of an account that is scheduled for deletion are suspended until the deletion is
+-- cancelled, revoked_at stays reserved for keys their owner revoked
+ALTER TABLE api_key ADD COLUMN suspended_at timestamp;
@@ -0,0 +1 @@
+-- the replaced time zones were unusable, there is nothing to restore
@@ -0,0 +1,3 @@
+-- time zones postgres doesn't know make AT TIME ZONE fail for every business list
+UPDATE businesses SET time_zone = 'UTC', updated_at = now()
+WHERE time_zone NOT IN (SELECT name FROM pg_timezone_names);
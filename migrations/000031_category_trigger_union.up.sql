@@ -0,0 +1,14 @@
+-- UNION stops the walk down the tree if the categories ever form a loop
+CREATE OR REPLACE FUNCTION business_categories_search_vector_update() RETURNS trigger AS $$
+BEGIN
+  UPDATE businesses SET name = name WHERE id IN (
+    WITH RECURSIVE d AS (
+      SELECT NEW.id AS id
+      UNION
+      SELECT c.id FROM business_categories c JOIN d ON c.parent_id = d.id
+    )
+    SELECT l.business_id FROM business_category_link l JOIN d ON d.id = l.category_id
+  );
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
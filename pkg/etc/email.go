@@ -36,6 +36,32 @@ func GenerateOtpEmailBody(otp string) (string, error) {
 	return builder.String(), nil
 }
 
+// GeneratePasswordResetEmailBody generates the HTML email body with a password reset code
+func GeneratePasswordResetEmailBody(otp string) (string, error) {
+	templateString := `
+<!DOCTYPE html>
+<html>
+<body>
+    <p>Your code to reset your YELP password {{.Code}},</p>
+    <p>If you did not request a password reset, you can ignore this email.</p>
+</body>
+</html>
+`
+	tmpl, err := template.New("email").Parse(templateString)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse email template: %w", err)
+	}
+	otpData := Otp{otp}
+
+	var builder strings.Builder
+	err = tmpl.Execute(&builder, otpData)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute email template: %w", err)
+	}
+
+	return builder.String(), nil
+}
+
 // sendEmail sends an email using SMTP
 func SendEmail(smtpHost, smtpPort, from, password, to, body string) error {
 	auth := smtp.PlainAuth("", from, password, smtpHost)
@@ -77,3 +103,55 @@ func GenerateNotificationEmailBody(message string) (string, error) {
 
 	return builder.String(), nil
 }
+
+// GenerateEmailChangeOtpBody generates the HTML email body sent to a new email address
+func GenerateEmailChangeOtpBody(otp string) (string, error) {
+	templateString := `
+<!DOCTYPE html>
+<html>
+<body>
+    <p>Your code to confirm the new email address of your YELP account {{.Code}},</p>
+    <p>If you did not request this change, you can ignore this email.</p>
+</body>
+</html>
+`
+	tmpl, err := template.New("email").Parse(templateString)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse email template: %w", err)
+	}
+	otpData := Otp{otp}
+
+	var builder strings.Builder
+	err = tmpl.Execute(&builder, otpData)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute email template: %w", err)
+	}
+
+	return builder.String(), nil
+}
+
+// GenerateEmailChangedNoticeBody generates the HTML email body sent to the previous email address
+func GenerateEmailChangedNoticeBody(newEmail string) (string, error) {
+	templateString := `
+<!DOCTYPE html>
+<html>
+<body>
+    <p>The email address of your YELP account was changed to {{.Code}}.</p>
+    <p>If you did not make this change, contact support right away.</p>
+</body>
+</html>
+`
+	tmpl, err := template.New("email").Parse(templateString)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse email template: %w", err)
+	}
+	otpData := Otp{newEmail}
+
+	var builder strings.Builder
+	err = tmpl.Execute(&builder, otpData)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute email template: %w", err)
+	}
+
+	return builder.String(), nil
+}
@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 
 	firebase "firebase.google.com/go"
 	"github.com/Akorm0181/yelp/internal/entity"
@@ -51,7 +52,8 @@ func UploadFiles(file *multipart.Form) (*entity.MultipleFileUploadResponse, erro
 		}
 		defer imageFile.Close()
 
-		fileName := v.Filename
+		// prefixed with the token so equal file names don't overwrite each other
+		fileName := id + "-" + v.Filename
 
 		objectHandle := bucketHandle.Object(fileName)
 		writer := objectHandle.NewWriter(context.Background())
@@ -152,7 +154,7 @@ func DeleteFile(id string) error {
 	ctx := context.Background()
 	client, err := storage.NewService(ctx, option.WithCredentialsFile("/app/serviceAccountKey.json"))
 	if err != nil {
-		log.Fatalf("Failed to create client: %v", err)
+		return fmt.Errorf("failed to create client: %w", err)
 	}
 
 	// Bucket name and object path to delete
@@ -161,9 +163,25 @@ func DeleteFile(id string) error {
 	// Delete the object
 	err = client.Objects.Delete(bucketName, objectPath).Do()
 	if err != nil {
-		log.Fatalf("Failed to delete object: %v", err)
+		return fmt.Errorf("failed to delete object: %w", err)
 	}
 
 	fmt.Printf("Object %s deleted successfully from bucket %s\n", objectPath, bucketName)
 	return nil
 }
+
+// ObjectName returns the object path of a download url made by UploadFiles or UploadFile,
+// that is the id DeleteFile expects
+func ObjectName(fileURL string) (string, error) {
+	u, err := url.Parse(fileURL)
+	if err != nil {
+		return "", err
+	}
+
+	_, name, found := strings.Cut(u.Path, "/o/")
+	if u.Host != "firebasestorage.googleapis.com" || !found || name == "" {
+		return "", fmt.Errorf("not a firebase storage url: %s", fileURL)
+	}
+
+	return name, nil
+}
@@ -0,0 +1,231 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported signing algorithms.
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	EdDSA = "EdDSA"
+)
+
+// Key is one signing key. HS256 keys hold a shared Secret, RS256 and EdDSA keys a PEM encoded
+// private key. A key signs new tokens from ActiveFrom on, until a newer key becomes active,
+// and verifies tokens for as long as it is part of the KeySet.
+type Key struct {
+	ID         string
+	Algorithm  string
+	Secret     string
+	PrivateKey string
+	ActiveFrom time.Time
+}
+
+type parsedKey struct {
+	id         string
+	method     jwt.SigningMethod
+	sign       interface{}
+	verify     interface{}
+	activeFrom time.Time
+}
+
+// KeySet signs tokens with its current key and verifies tokens signed by any of its keys,
+// selected by the kid header. Rotating means adding a key with a future ActiveFrom: it is
+// published in the JWKS right away, takes over signing at ActiveFrom, and the old key can be
+// removed once the tokens it signed have expired.
+type KeySet struct {
+	keys []parsedKey
+	byID map[string]parsedKey
+}
+
+// NewKeySet -.
+func NewKeySet(keys ...Key) (*KeySet, error) {
+	set := &KeySet{
+		byID: make(map[string]parsedKey, len(keys)),
+	}
+
+	for _, key := range keys {
+		parsed := parsedKey{
+			id:         key.ID,
+			activeFrom: key.ActiveFrom,
+		}
+
+		switch key.Algorithm {
+		case HS256, "":
+			if key.Secret == "" {
+				return nil, fmt.Errorf("jwt key %q: secret is empty", key.ID)
+			}
+			parsed.method = jwt.SigningMethodHS256
+			parsed.sign = []byte(key.Secret)
+			parsed.verify = []byte(key.Secret)
+		case RS256:
+			private, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(key.PrivateKey))
+			if err != nil {
+				return nil, fmt.Errorf("jwt key %q: %w", key.ID, err)
+			}
+			parsed.method = jwt.SigningMethodRS256
+			parsed.sign = private
+			parsed.verify = &private.PublicKey
+		case EdDSA:
+			private, err := jwt.ParseEdPrivateKeyFromPEM([]byte(key.PrivateKey))
+			if err != nil {
+				return nil, fmt.Errorf("jwt key %q: %w", key.ID, err)
+			}
+			edPrivate, ok := private.(ed25519.PrivateKey)
+			if !ok {
+				return nil, fmt.Errorf("jwt key %q: not an ed25519 key", key.ID)
+			}
+			parsed.method = jwt.SigningMethodEdDSA
+			parsed.sign = edPrivate
+			parsed.verify = edPrivate.Public()
+		default:
+			return nil, fmt.Errorf("jwt key %q: unsupported algorithm %s", key.ID, key.Algorithm)
+		}
+
+		if key.ID == "" && parsed.method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("jwt: %s key needs a kid", key.Algorithm)
+		}
+
+		if _, ok := set.byID[key.ID]; ok {
+			return nil, fmt.Errorf("jwt key %q: duplicate kid", key.ID)
+		}
+
+		set.byID[key.ID] = parsed
+		set.keys = append(set.keys, parsed)
+	}
+
+	if len(set.keys) == 0 {
+		return nil, errors.New("jwt: no keys configured")
+	}
+
+	// newest first, so the signing key is the first one that is already active;
+	// keys with the same ActiveFrom keep the order they were given in
+	sort.SliceStable(set.keys, func(i, j int) bool {
+		return set.keys[i].activeFrom.After(set.keys[j].activeFrom)
+	})
+
+	return set, nil
+}
+
+func (s *KeySet) signingKey() (parsedKey, error) {
+	now := time.Now()
+	for _, key := range s.keys {
+		if !key.activeFrom.After(now) {
+			return key, nil
+		}
+	}
+
+	return parsedKey{}, errors.New("jwt: no active signing key")
+}
+
+// Sign signs the claims with the current key and sets iat/exp so the token expires after ttl.
+func (s *KeySet) Sign(keys map[string]interface{}, ttl time.Duration) (string, error) {
+	key, err := s.signingKey()
+	if err != nil {
+		return "", err
+	}
+
+	claims := jwt.MapClaims{}
+	for k, v := range keys {
+		claims[k] = v
+	}
+
+	now := time.Now()
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(ttl).Unix()
+
+	token := jwt.NewWithClaims(key.method, claims)
+	if key.id != "" {
+		token.Header["kid"] = key.id
+	}
+
+	return token.SignedString(key.sign)
+}
+
+// Parse verifies the token with the key named by its kid header. Tokens without a kid were
+// signed before keys had ids and are checked against the key with an empty id.
+func (s *KeySet) Parse(tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+
+		key, ok := s.byID[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+
+		// the algorithm must be the one of the key, never the one the token asks for
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+
+		return key.verify, nil
+	}, jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	return claims, nil
+}
+
+// JWK is a public key in JSON Web Key format.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys of the set, including keys that are not active yet so that
+// verifiers already know them when they start signing. Shared HS256 secrets are never published.
+func (s *KeySet) JWKS() JWKS {
+	response := JWKS{Keys: []JWK{}}
+
+	for _, key := range s.keys {
+		switch public := key.verify.(type) {
+		case *rsa.PublicKey:
+			response.Keys = append(response.Keys, JWK{
+				Kty: "RSA",
+				Kid: key.id,
+				Use: "sig",
+				Alg: RS256,
+				N:   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			response.Keys = append(response.Keys, JWK{
+				Kty: "OKP",
+				Kid: key.id,
+				Use: "sig",
+				Alg: EdDSA,
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(public),
+			})
+		}
+	}
+
+	return response
+}
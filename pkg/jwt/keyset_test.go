@@ -0,0 +1,329 @@
+package jwt_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+
+	"github.com/Akorm0181/yelp/pkg/jwt"
+)
+
+func pemEncode(t *testing.T, key interface{}) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+func newEdKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return private
+}
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return private
+}
+
+// kidOf returns the kid header of a token without verifying it.
+func kidOf(t *testing.T, token string) string {
+	t.Helper()
+
+	parsed, _, err := gojwt.NewParser().ParseUnverified(token, gojwt.MapClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
+
+func signRaw(t *testing.T, method gojwt.SigningMethod, kid string, key interface{}) string {
+	t.Helper()
+
+	token := gojwt.NewWithClaims(method, gojwt.MapClaims{
+		"user_id": "1",
+		"exp":     time.Now().Add(time.Minute).Unix(),
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed
+}
+
+func TestRotation(t *testing.T) {
+	oldKey, newKey := newEdKey(t), newEdKey(t)
+	now := time.Now()
+
+	// the new key is published but does not sign yet
+	before, err := jwt.NewKeySet(
+		jwt.Key{ID: "old", Algorithm: jwt.EdDSA, PrivateKey: pemEncode(t, oldKey), ActiveFrom: now.Add(-time.Hour)},
+		jwt.Key{ID: "new", Algorithm: jwt.EdDSA, PrivateKey: pemEncode(t, newKey), ActiveFrom: now.Add(time.Hour)},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	oldToken, err := before.Sign(map[string]interface{}{"user_id": "1"}, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if kid := kidOf(t, oldToken); kid != "old" {
+		t.Fatalf("expected the old key to sign before the new one is active, got kid %q", kid)
+	}
+
+	// the new key has taken over signing, the old one still verifies
+	after, err := jwt.NewKeySet(
+		jwt.Key{ID: "old", Algorithm: jwt.EdDSA, PrivateKey: pemEncode(t, oldKey), ActiveFrom: now.Add(-2 * time.Hour)},
+		jwt.Key{ID: "new", Algorithm: jwt.EdDSA, PrivateKey: pemEncode(t, newKey), ActiveFrom: now.Add(-time.Hour)},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	newToken, err := after.Sign(map[string]interface{}{"user_id": "1"}, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if kid := kidOf(t, newToken); kid != "new" {
+		t.Fatalf("expected the new key to sign once it is active, got kid %q", kid)
+	}
+
+	for _, token := range []string{oldToken, newToken} {
+		claims, err := after.Parse(token)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if claims["user_id"] != "1" {
+			t.Fatalf("unexpected claims %+v", claims)
+		}
+	}
+
+	// once the old key is removed its tokens are rejected
+	removed, err := jwt.NewKeySet(
+		jwt.Key{ID: "new", Algorithm: jwt.EdDSA, PrivateKey: pemEncode(t, newKey), ActiveFrom: now.Add(-time.Hour)},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err = removed.Parse(oldToken); err == nil {
+		t.Fatal("expected error for a token of a removed key")
+	}
+}
+
+func TestNoActiveKey(t *testing.T) {
+	set, err := jwt.NewKeySet(
+		jwt.Key{ID: "future", Algorithm: jwt.EdDSA, PrivateKey: pemEncode(t, newEdKey(t)), ActiveFrom: time.Now().Add(time.Hour)},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err = set.Sign(map[string]interface{}{}, time.Minute); err == nil {
+		t.Fatal("expected error when no key is active yet")
+	}
+}
+
+func TestParse(t *testing.T) {
+	edKey, rsaKey, otherKey := newEdKey(t), newRSAKey(t), newEdKey(t)
+
+	set, err := jwt.NewKeySet(
+		jwt.Key{ID: "ed", Algorithm: jwt.EdDSA, PrivateKey: pemEncode(t, edKey)},
+		jwt.Key{ID: "rsa", Algorithm: jwt.RS256, PrivateKey: pemEncode(t, rsaKey)},
+		jwt.Key{Algorithm: jwt.HS256, Secret: "legacy-secret"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	withoutLegacy, err := jwt.NewKeySet(
+		jwt.Key{ID: "ed", Algorithm: jwt.EdDSA, PrivateKey: pemEncode(t, edKey)},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name    string
+		set     *jwt.KeySet
+		token   string
+		wantErr bool
+	}{
+		{
+			name:  "eddsa key selected by kid",
+			set:   set,
+			token: signRaw(t, gojwt.SigningMethodEdDSA, "ed", edKey),
+		},
+		{
+			name:  "rsa key selected by kid",
+			set:   set,
+			token: signRaw(t, gojwt.SigningMethodRS256, "rsa", rsaKey),
+		},
+		{
+			name:  "token without kid checked against the legacy secret",
+			set:   set,
+			token: signRaw(t, gojwt.SigningMethodHS256, "", []byte("legacy-secret")),
+		},
+		{
+			name:    "token without kid and no legacy secret",
+			set:     withoutLegacy,
+			token:   signRaw(t, gojwt.SigningMethodHS256, "", []byte("legacy-secret")),
+			wantErr: true,
+		},
+		{
+			name:    "unknown kid",
+			set:     set,
+			token:   signRaw(t, gojwt.SigningMethodEdDSA, "rotated-away", edKey),
+			wantErr: true,
+		},
+		{
+			name:    "signed by another key with a known kid",
+			set:     set,
+			token:   signRaw(t, gojwt.SigningMethodEdDSA, "ed", otherKey),
+			wantErr: true,
+		},
+		{
+			name:    "algorithm other than the one of the key",
+			set:     set,
+			token:   signRaw(t, gojwt.SigningMethodHS256, "rsa", []byte("legacy-secret")),
+			wantErr: true,
+		},
+		{
+			name: "expired",
+			set:  set,
+			token: func() string {
+				token := gojwt.NewWithClaims(gojwt.SigningMethodEdDSA, gojwt.MapClaims{
+					"exp": time.Now().Add(-time.Minute).Unix(),
+				})
+				token.Header["kid"] = "ed"
+				signed, err := token.SignedString(edKey)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return signed
+			}(),
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			claims, err := tc.set.Parse(tc.token)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got claims %+v", claims)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if claims["user_id"] != "1" {
+				t.Fatalf("unexpected claims %+v", claims)
+			}
+		})
+	}
+}
+
+func TestNewKeySetErrors(t *testing.T) {
+	edPEM := pemEncode(t, newEdKey(t))
+
+	tests := []struct {
+		name string
+		keys []jwt.Key
+	}{
+		{name: "no keys"},
+		{name: "empty secret", keys: []jwt.Key{{Algorithm: jwt.HS256}}},
+		{name: "asymmetric key without kid", keys: []jwt.Key{{Algorithm: jwt.EdDSA, PrivateKey: edPEM}}},
+		{name: "duplicate kid", keys: []jwt.Key{
+			{ID: "a", Algorithm: jwt.EdDSA, PrivateKey: edPEM},
+			{ID: "a", Algorithm: jwt.EdDSA, PrivateKey: edPEM},
+		}},
+		{name: "ed25519 pem given as rsa", keys: []jwt.Key{{ID: "a", Algorithm: jwt.RS256, PrivateKey: edPEM}}},
+		{name: "unsupported algorithm", keys: []jwt.Key{{ID: "a", Algorithm: "ES256", PrivateKey: edPEM}}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := jwt.NewKeySet(tc.keys...); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	edKey, rsaKey := newEdKey(t), newRSAKey(t)
+
+	set, err := jwt.NewKeySet(
+		jwt.Key{ID: "ed", Algorithm: jwt.EdDSA, PrivateKey: pemEncode(t, edKey), ActiveFrom: time.Now().Add(time.Hour)},
+		jwt.Key{ID: "rsa", Algorithm: jwt.RS256, PrivateKey: pemEncode(t, rsaKey)},
+		jwt.Key{Algorithm: jwt.HS256, Secret: "legacy-secret"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// the key that is not active yet is published too, the shared secret never is
+	want := map[string]jwt.JWK{
+		"ed": {
+			Kty: "OKP",
+			Kid: "ed",
+			Use: "sig",
+			Alg: jwt.EdDSA,
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(edKey.Public().(ed25519.PublicKey)),
+		},
+		"rsa": {
+			Kty: "RSA",
+			Kid: "rsa",
+			Use: "sig",
+			Alg: jwt.RS256,
+			N:   base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+		},
+	}
+
+	jwks := set.JWKS()
+	if len(jwks.Keys) != len(want) {
+		t.Fatalf("expected %d keys, got %+v", len(want), jwks.Keys)
+	}
+
+	for _, key := range jwks.Keys {
+		if key != want[key.Kid] {
+			t.Fatalf("key %q: expected %+v, got %+v", key.Kid, want[key.Kid], key)
+		}
+	}
+}
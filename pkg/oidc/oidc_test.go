@@ -0,0 +1,175 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/Akorm0181/yelp/pkg/oidc"
+)
+
+const (
+	testKid      = "test-key"
+	testClientID = "yelp-mobile"
+)
+
+// stubIssuer serves a JWKS document with a single RSA key, like a real identity provider.
+func stubIssuer(t *testing.T) (*httptest.Server, *rsa.PrivateKey) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": testKid,
+				"kty": "RSA",
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server, key
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed
+}
+
+func TestVerify(t *testing.T) {
+	server, key := stubIssuer(t)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier := oidc.NewVerifier(oidc.Provider{
+		Name:      "stub",
+		Issuer:    server.URL,
+		JWKSURL:   server.URL + "/jwks",
+		ClientIDs: []string{testClientID},
+	})
+
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":            server.URL,
+			"aud":            testClientID,
+			"sub":            "1234567890",
+			"email":          "jane@example.com",
+			"email_verified": "true",
+			"name":           "Jane Doe",
+			"iat":            time.Now().Unix(),
+			"exp":            time.Now().Add(time.Hour).Unix(),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		provider string
+		token    func() string
+		wantErr  bool
+	}{
+		{
+			name:     "valid token",
+			provider: "stub",
+			token:    func() string { return signToken(t, key, testKid, validClaims()) },
+		},
+		{
+			name:     "unknown provider",
+			provider: "google",
+			token:    func() string { return signToken(t, key, testKid, validClaims()) },
+			wantErr:  true,
+		},
+		{
+			name:     "wrong audience",
+			provider: "stub",
+			token: func() string {
+				claims := validClaims()
+				claims["aud"] = "someone-else"
+				return signToken(t, key, testKid, claims)
+			},
+			wantErr: true,
+		},
+		{
+			name:     "wrong issuer",
+			provider: "stub",
+			token: func() string {
+				claims := validClaims()
+				claims["iss"] = "https://evil.example.com"
+				return signToken(t, key, testKid, claims)
+			},
+			wantErr: true,
+		},
+		{
+			name:     "expired",
+			provider: "stub",
+			token: func() string {
+				claims := validClaims()
+				claims["exp"] = time.Now().Add(-time.Minute).Unix()
+				return signToken(t, key, testKid, claims)
+			},
+			wantErr: true,
+		},
+		{
+			name:     "signed by another key",
+			provider: "stub",
+			token:    func() string { return signToken(t, otherKey, testKid, validClaims()) },
+			wantErr:  true,
+		},
+		{
+			name:     "unknown key id",
+			provider: "stub",
+			token:    func() string { return signToken(t, key, "rotated-away", validClaims()) },
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			claims, err := verifier.Verify(context.Background(), tc.provider, tc.token())
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got claims %+v", claims)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if claims.Subject != "1234567890" || claims.Email != "jane@example.com" || !claims.EmailVerified || claims.Name != "Jane Doe" {
+				t.Fatalf("unexpected claims %+v", claims)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Package ratelimit implements a redis backed sliding window rate limiter.
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindow keeps one sorted set entry per hit scored by its time in milliseconds.
+// Entries older than the window are dropped before counting, so the limit applies to
+// any window-long interval, not to fixed buckets. Runs atomically inside redis.
+var slidingWindow = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, 0, now - window)
+
+if redis.call('ZCARD', key) >= limit then
+	local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+	return {0, tonumber(oldest[2]) + window - now}
+end
+
+redis.call('ZADD', key, now, ARGV[4])
+redis.call('PEXPIRE', key, window)
+
+return {1, 0}
+`)
+
+// Limiter -.
+type Limiter struct {
+	client *redis.Client
+	prefix string
+}
+
+// New -.
+func New(client *redis.Client) *Limiter {
+	return &Limiter{
+		client: client,
+		prefix: "ratelimit-",
+	}
+}
+
+// Allow records a hit for key if fewer than limit hits happened during the last window.
+// When the hit is rejected it returns how long to wait until the next one is accepted.
+func (l *Limiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	res, err := slidingWindow.Run(ctx, l.client, []string{l.prefix + key},
+		time.Now().UnixMilli(), window.Milliseconds(), limit, uuid.NewString()).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+
+	if res[0] == 1 {
+		return true, 0, nil
+	}
+
+	return false, time.Duration(res[1]) * time.Millisecond, nil
+}
+
+// counter adds one to the counter and keeps it for the given time after the last hit.
+var counter = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[1])
+
+return count
+`)
+
+// Incr atomically counts a hit for key and returns the new count. The counter is forgotten
+// ttl after its last hit.
+func (l *Limiter) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return counter.Run(ctx, l.client, []string{l.prefix + key}, ttl.Milliseconds()).Int64()
+}
+
+// Reset forgets the counters of the keys.
+func (l *Limiter) Reset(ctx context.Context, keys ...string) error {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = l.prefix + key
+	}
+
+	return l.client.Del(ctx, prefixed...).Err()
+}
+
+// Claim atomically marks key as taken for ttl, false when it was taken already. Used for
+// things that may happen only once, like spending a single-use token.
+func (l *Limiter) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return l.client.SetNX(ctx, l.prefix+key, "1", ttl).Result()
+}
+
+// Claimed reports whether key is taken.
+func (l *Limiter) Claimed(ctx context.Context, key string) (bool, error) {
+	n, err := l.client.Exists(ctx, l.prefix+key).Result()
+	return n > 0, err
+}
@@ -0,0 +1,152 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Akorm0181/yelp/pkg/ratelimit"
+)
+
+// newLimiter returns a limiter on an in-memory redis, the server is returned to move its clock.
+func newLimiter(t *testing.T) (*ratelimit.Limiter, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return ratelimit.New(client), server
+}
+
+func TestAllowLimit(t *testing.T) {
+	limiter, _ := newLimiter(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		ok, retryAfter, err := limiter.Allow(ctx, "login:1.2.3.4", 3, time.Minute)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !ok || retryAfter != 0 {
+			t.Fatalf("hit %d: expected to be allowed, got %t retry after %s", i+1, ok, retryAfter)
+		}
+	}
+
+	ok, retryAfter, err := limiter.Allow(ctx, "login:1.2.3.4", 3, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if ok {
+		t.Fatal("hit over the limit was allowed")
+	}
+	if retryAfter <= 0 || retryAfter > time.Minute {
+		t.Fatalf("expected retry after within the window, got %s", retryAfter)
+	}
+
+	// other keys have their own window
+	ok, _, err = limiter.Allow(ctx, "login:5.6.7.8", 3, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !ok {
+		t.Fatal("hit of another key was rejected")
+	}
+}
+
+func TestAllowWindowExpiry(t *testing.T) {
+	limiter, _ := newLimiter(t)
+	ctx := context.Background()
+	window := 200 * time.Millisecond
+
+	ok, _, err := limiter.Allow(ctx, "otp:jane", 1, window)
+	if err != nil || !ok {
+		t.Fatalf("first hit: expected to be allowed, got %t %v", ok, err)
+	}
+
+	ok, retryAfter, err := limiter.Allow(ctx, "otp:jane", 1, window)
+	if err != nil || ok {
+		t.Fatalf("second hit: expected to be rejected, got %t %v", ok, err)
+	}
+
+	// the script keeps time with the clock of the caller
+	time.Sleep(retryAfter + 10*time.Millisecond)
+
+	ok, _, err = limiter.Allow(ctx, "otp:jane", 1, window)
+	if err != nil || !ok {
+		t.Fatalf("hit after the window: expected to be allowed, got %t %v", ok, err)
+	}
+}
+
+func TestIncr(t *testing.T) {
+	limiter, server := newLimiter(t)
+	ctx := context.Background()
+
+	for want := int64(1); want <= 3; want++ {
+		count, err := limiter.Incr(ctx, "failures", time.Minute)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if count != want {
+			t.Fatalf("expected count %d, got %d", want, count)
+		}
+	}
+
+	server.FastForward(time.Minute)
+
+	count, err := limiter.Incr(ctx, "failures", time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected the counter to start over after its ttl, got %d", count)
+	}
+
+	if err = limiter.Reset(ctx, "failures"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	count, err = limiter.Incr(ctx, "failures", time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected the counter to start over after reset, got %d", count)
+	}
+}
+
+func TestClaim(t *testing.T) {
+	limiter, server := newLimiter(t)
+	ctx := context.Background()
+
+	claimed, err := limiter.Claimed(ctx, "mfa:jti")
+	if err != nil || claimed {
+		t.Fatalf("expected unclaimed key, got %t %v", claimed, err)
+	}
+
+	ok, err := limiter.Claim(ctx, "mfa:jti", time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("first claim: expected to succeed, got %t %v", ok, err)
+	}
+
+	ok, err = limiter.Claim(ctx, "mfa:jti", time.Minute)
+	if err != nil || ok {
+		t.Fatalf("second claim: expected to fail, got %t %v", ok, err)
+	}
+
+	claimed, err = limiter.Claimed(ctx, "mfa:jti")
+	if err != nil || !claimed {
+		t.Fatalf("expected claimed key, got %t %v", claimed, err)
+	}
+
+	server.FastForward(time.Minute)
+
+	ok, err = limiter.Claim(ctx, "mfa:jti", time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("claim after the ttl: expected to succeed, got %t %v", ok, err)
+	}
+}
@@ -0,0 +1,161 @@
+package totp_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Akorm0181/yelp/pkg/totp"
+)
+
+// rfcSecret is the SHA1 seed of RFC 6238 appendix B, "12345678901234567890", in base32.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// rfcVectors are the SHA1 test vectors of RFC 6238 appendix B, cut to the last six digits.
+var rfcVectors = []struct {
+	unix int64
+	code string
+}{
+	{unix: 59, code: "287082"},
+	{unix: 1111111109, code: "081804"},
+	{unix: 1111111111, code: "050471"},
+	{unix: 1234567890, code: "005924"},
+	{unix: 2000000000, code: "279037"},
+	{unix: 20000000000, code: "353130"},
+}
+
+func TestGenerateCode(t *testing.T) {
+	for _, tc := range rfcVectors {
+		code, err := totp.GenerateCode(rfcSecret, time.Unix(tc.unix, 0))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if code != tc.code {
+			t.Fatalf("code at %d: expected %s, got %s", tc.unix, tc.code, code)
+		}
+	}
+}
+
+func TestGenerateCodeInvalidSecret(t *testing.T) {
+	if _, err := totp.GenerateCode("not base32!", time.Now()); err == nil {
+		t.Fatal("expected error for an invalid secret")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	// 1111111111 is step 37037037, 1111111109 is the last second of step 37037036
+	at := time.Unix(1111111111, 0)
+	step := at.Unix() / totp.Period
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		at       time.Time
+		wantStep int64
+		wantOK   bool
+	}{
+		{
+			name:     "current step",
+			secret:   rfcSecret,
+			code:     "050471",
+			at:       at,
+			wantStep: step,
+			wantOK:   true,
+		},
+		{
+			name:     "lowercase padded secret",
+			secret:   strings.ToLower(rfcSecret) + "====",
+			code:     "050471",
+			at:       at,
+			wantStep: step,
+			wantOK:   true,
+		},
+		{
+			name:     "previous step within skew",
+			secret:   rfcSecret,
+			code:     "081804",
+			at:       at,
+			wantStep: step - totp.Skew,
+			wantOK:   true,
+		},
+		{
+			name:     "code of the next step within skew",
+			secret:   rfcSecret,
+			code:     "050471",
+			at:       at.Add(-totp.Skew * totp.Period * time.Second),
+			wantStep: step,
+			wantOK:   true,
+		},
+		{
+			name:   "older than skew",
+			secret: rfcSecret,
+			code:   "050471",
+			at:     at.Add((totp.Skew + 1) * totp.Period * time.Second),
+		},
+		{
+			name:   "newer than skew",
+			secret: rfcSecret,
+			code:   "050471",
+			at:     at.Add(-(totp.Skew + 1) * totp.Period * time.Second),
+		},
+		{
+			name:   "wrong code",
+			secret: rfcSecret,
+			code:   "123456",
+			at:     at,
+		},
+		{
+			name:   "wrong length",
+			secret: rfcSecret,
+			code:   "07081804",
+			at:     at,
+		},
+		{
+			name:   "invalid secret",
+			secret: "not base32!",
+			code:   "050471",
+			at:     at,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			gotStep, ok := totp.Validate(tc.secret, tc.code, tc.at)
+			if ok != tc.wantOK {
+				t.Fatalf("expected valid %t, got %t", tc.wantOK, ok)
+			}
+
+			if gotStep != tc.wantStep {
+				t.Fatalf("expected step %d, got %d", tc.wantStep, gotStep)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	now := time.Now()
+	code, err := totp.GenerateCode(secret, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, ok := totp.Validate(secret, code, now); !ok {
+		t.Fatalf("code %s of a generated secret is not valid", code)
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := totp.ProvisioningURI("Yelp", "jane@example.com", rfcSecret)
+
+	want := "otpauth://totp/Yelp:jane@example.com?algorithm=SHA1&digits=6&issuer=Yelp&period=30&secret=" + rfcSecret
+	if uri != want {
+		t.Fatalf("expected %s, got %s", want, uri)
+	}
+}